@@ -0,0 +1,93 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     align.go
+ * Overview:
+ *     alignment of the text index fields within the composite keys, to the right as numbers are or to the left as words
+ *     are.
+ * Functions:
+ *     WithAlignment(column int, align Alignment) Option
+ *         Option setting the alignment of a text index field in the composite keys.
+ * Types:
+ *     Alignment
+ *         Alignment of a text index field.
+ * History:
+ *     v1.56.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "fmt"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+type Alignment int
+const(
+    AlignRight Alignment = iota //padded with spaces on the left, e.g. "   ab", the default
+    AlignLeft                   //padded with spaces on the right, e.g. "ab   "
+)
+
+func WithAlignment(column int, align Alignment) Option {
+/*         Purpose : Sets the alignment of a text index field in the composite keys.
+ *       Arguments : column = number of the field, the first being 1. Must be an index field of type FieldText.
+ *                   align  = the alignment.
+ *         Returns : The option.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : None.
+ *         Remarks : Right-aligned values compare on their length first, e.g. "9" < "10" and "b c" < "a b c", which suits
+ *                   numeric-ish columns such as counts, ids or codes of varying length. Left-aligned values compare
+ *                   character by character from their start, as in a dictionary, e.g. "10" < "9" and "a b c" < "b c",
+ *                   which suits words, names and other left-significant text. Either way, a value compares equal to
+ *                   itself padded with spaces on its padded side. A later option for the same field replaces an earlier
+ *                   one.
+ *         History : v1.56.0 - October 15, 2026 - Original release.
+ */
+    return func(s *Sorter) {
+        if s.aligns == nil { s.aligns = map[int]Alignment{} }
+        s.aligns[column - 1] = align
+    }
+} //end func WithAlignment
+//Private ----------------------------------------------------------------------------------------------------------------------
+func (s *Sorter) checkAligns() {
+    //Halts on an aligned field that is not a text index field, or on an unknown alignment
+    for colIdx, align := range s.aligns {
+        if !s.isIndexField(colIdx) { haltKind(ErrBadFieldSpec, fmt.Sprintf("the aligned column %d is not an index field",
+                                                                          colIdx + 1), nil) }
+        if align != AlignRight && align != AlignLeft { halt(fmt.Sprintf("the alignment of column %d is unknown", colIdx + 1)) }
+        if align == AlignRight { continue }
+        if _, padded := s.zeroPads[colIdx]; padded || s.fieldTypes[colIdx] != FieldText {
+            halt(fmt.Sprintf("the left-aligned column %d is not of type FieldText or is zero-padded", colIdx + 1))
+        }
+    }
+} //end func checkAligns
+func (s *Sorter) orderComparer() func(a, b []string) int {
+    //Returns the comparison of the values returned by orderKey for two records, each pair being aligned per its field and
+    //compared in reverse if its field is ordered against the sort direction
+    var(
+        leftAligned []bool                                        //flags of the left-aligned values, in orderKey's layout
+        reversed    []bool                                        //flags of the values compared in reverse, likewise
+        anyReversed bool
+    )
+    if s.derive != nil { leftAligned, reversed = append(leftAligned, false), append(reversed, false) }
+    for k, colIdx := range s.colIdxs {
+        _, padded := s.zeroPads[colIdx]
+        desc      := s.fieldAsc(k) != s.sortAsc
+        for _, marked := range []bool{s.nullPrefix(colIdx, true) != "", s.hashOrder, padded} {
+            if marked { leftAligned, reversed = append(leftAligned, false), append(reversed, desc) }
+        }
+        leftAligned, reversed = append(leftAligned, s.aligns[colIdx] == AlignLeft), append(reversed, desc)
+        anyReversed           = anyReversed || desc
+    }
+    if !anyReversed { return func(a, b []string) int { return compareAligned(a, b, leftAligned) } }
+    return func(a, b []string) int {
+        for k := 0; k < len(a) && k < len(b) && k < len(reversed); k++ {
+            cmp := compareAligned(a[k:k + 1], b[k:k + 1], leftAligned[k:k + 1])
+            if reversed[k] { cmp = -cmp }
+            if cmp != 0 { return cmp }
+        }
+        return 0
+    }
+} //end func orderComparer
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file align.go
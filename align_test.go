@@ -0,0 +1,54 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     align_test.go
+ * Overview:
+ *     tests of the relative order of text values with embedded spaces once right- or left-aligned in the composite keys.
+ * Functions:
+ *     TestAlignmentOrder(t *testing.T)
+ *         Checks the orders of the same values right- and left-aligned, in both directions.
+ * History:
+ *     v1.56.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "fmt"
+    "strings"
+    "testing"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func TestAlignmentOrder(t *testing.T) {
+    values := []string{"b c", "a b c", "a b", "ab", "a  b", "b", "a", "10", "9 x"}
+    tests  := []struct {
+        align   Alignment
+        sortAsc bool
+        order   []string
+    }{
+        //Right-aligned: on their length first, spaces included, so "b" < "ab" < "a b" < "a  b" and "10" < "9 x"
+        {AlignRight, true, []string{"a", "b", "10", "ab", "9 x", "a b", "b c", "a  b", "a b c"}},
+        {AlignRight, false, []string{"a b c", "a  b", "b c", "a b", "9 x", "ab", "10", "b", "a"}},
+        //Left-aligned: as in a dictionary, a space sorting ahead of every letter and digit
+        {AlignLeft, true, []string{"10", "9 x", "a", "a  b", "a b", "a b c", "ab", "b", "b c"}},
+        {AlignLeft, false, []string{"b c", "b", "ab", "a b c", "a b", "a  b", "a", "9 x", "10"}},
+    }
+    for _, test := range tests {
+        name     := fmt.Sprintf("align=%d/asc=%v", test.align, test.sortAsc)
+        input    := strings.Join(values, "\t.\n") + "\t.\n"
+        expected := strings.Join(test.order, "\t.\n") + "\t.\n"
+        output   := sortBytes(t, input, WithFields("1"), WithAlignment(1, test.align), WithAscending(test.sortAsc))
+        if string(output) != expected { t.Errorf("%s: output\n%q\nexpected\n%q", name, output, expected) }
+    }
+    //A value compares equal to itself padded on its padded side, keeping the input order
+    for _, test := range []struct {
+        align Alignment
+        input string
+    }{{AlignRight, " a\tx\na\ty\n  a\tz\n"}, {AlignLeft, "a \tx\na\ty\na  \tz\n"}} {
+        if output := string(sortBytes(t, test.input, WithFields("1"), WithAlignment(1, test.align))); output != test.input {
+            t.Errorf("align=%d: output\n%q\nexpected\n%q", test.align, output, test.input)
+        }
+    }
+} //end func TestAlignmentOrder
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file align_test.go
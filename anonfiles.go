@@ -0,0 +1,107 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     anonfiles.go
+ * Overview:
+ *     anonymous temporary files of the OS storage, which have no directory entry and vanish with the process that created
+ *     them, tracked by a table of open handles rather than by their names.
+ * History:
+ *     v1.59.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "os"
+    "path/filepath"
+    "sort"
+    "strconv"
+    "strings"
+    "sync"
+    "sync/atomic"
+)
+//Private ----------------------------------------------------------------------------------------------------------------------
+type anonFiles struct {
+    mutex       sync.Mutex
+    files       map[string]*os.File //handle kept by each anonymous file, by its name, until removed
+    seq         uint64              //last sequence number given to a name
+    limiter     *fileLimiter        //cap on the open files charged with the handles kept, named files being created past it
+    unsupported bool                //anonymous files unavailable in the directory, named files being created instead
+}
+type anonFile struct {
+    *os.File
+    name string                     //name given to the file, which its OS handle lacks
+}
+func newAnonFiles(limiter *fileLimiter) *anonFiles {
+    return &anonFiles{files:map[string]*os.File{}, limiter:limiter}
+} //end func newAnonFiles
+func (f anonFile) Name() string { return f.name }
+func (a *anonFiles) create(dir, name string) (TempFile, bool, error) {
+    //Creates an anonymous file of the directory under the given name and returns a handle open for writing, or returns false
+    //if named files are to be created instead
+    if a == nil { return nil, false, nil }
+    a.mutex.Lock()
+    defer a.mutex.Unlock()
+    if a.unsupported { return nil, false, nil }
+    name = filepath.Join(dir, name)
+    if _, taken := a.files[name]; taken { return nil, true, &os.PathError{Op:"create", Path:name, Err:os.ErrExist} }
+    if !a.limiter.hold() { return nil, false, nil }              //no room left for the handle kept
+    fh, err := openAnonymous(dir)
+    if err == errAnonUnsupported { a.unsupported = true }
+    if err != nil {
+        a.limiter.unhold()
+        return nil, false, nil                                    //the named file reporting any lasting failure
+    }
+    writer, err := reopenAnonymous(fh, os.O_RDWR)
+    if err != nil {
+        fh.Close()
+        a.limiter.unhold()
+        a.unsupported = true
+        return nil, false, nil
+    }
+    a.files[name] = fh
+    return anonFile{File:writer, name:name}, true, nil
+} //end func create
+func (a *anonFiles) nextName(prefix string) string {
+    //Returns a new name made of prefix followed by a sequence number
+    return prefix + strconv.FormatUint(atomic.AddUint64(&a.seq, 1), 10)
+} //end func nextName
+func (a *anonFiles) open(name string) (TempFile, bool, error) {
+    //Opens an anonymous file for reading, returning false if there is no such file
+    if a == nil { return nil, false, nil }
+    a.mutex.Lock()
+    defer a.mutex.Unlock()
+    fh, ok := a.files[name]
+    if !ok { return nil, false, nil }
+    reader, err := reopenAnonymous(fh, os.O_RDONLY)
+    if err != nil { return nil, true, &os.PathError{Op:"open", Path:name, Err:err} }
+    return anonFile{File:reader, name:name}, true, nil
+} //end func open
+func (a *anonFiles) remove(name string) bool {
+    //Drops the handle of an anonymous file, the OS releasing its space once the other handles are closed, and returns false
+    //if there is no such file
+    if a == nil { return false }
+    a.mutex.Lock()
+    defer a.mutex.Unlock()
+    fh, ok := a.files[name]
+    if ok {
+        fh.Close()
+        delete(a.files, name)
+        a.limiter.unhold()
+    }
+    return ok
+} //end func remove
+func (a *anonFiles) list(dir, prefix string) []string {
+    //Returns the names of the anonymous files created with prefix
+    var names []string
+    if a == nil { return names }
+    a.mutex.Lock()
+    defer a.mutex.Unlock()
+    for name := range a.files {
+        if strings.HasPrefix(name, filepath.Join(dir, prefix)) { names = append(names, name) }
+    }
+    sort.Strings(names)
+    return names
+} //end func list
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file anonfiles.go
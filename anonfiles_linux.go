@@ -0,0 +1,58 @@
+//go:build linux
+// +build linux
+
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     anonfiles_linux.go
+ * Overview:
+ *     anonymous temporary files through O_TMPFILE, or files unlinked once created where the file system lacks it, reopened
+ *     through /proc/self/fd.
+ * History:
+ *     v1.59.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "errors"
+    "fmt"
+    "io/ioutil"
+    "os"
+    "sync"
+    "syscall"
+)
+//Private ----------------------------------------------------------------------------------------------------------------------
+const _oTmpFile = 020000000 | syscall.O_DIRECTORY //O_TMPFILE, missing from package syscall
+var(
+    errAnonUnsupported = errors.New("anonymous files unsupported") //no way of reopening the anonymous files
+    _procFdOnce        sync.Once
+    _procFd            bool                                         //availability of /proc/self/fd
+)
+func openAnonymous(dir string) (*os.File, error) {
+    //Creates a file without a directory entry in dir, unlinking a named one if the file system lacks O_TMPFILE
+    _procFdOnce.Do(func() {
+        _, err := os.Stat("/proc/self/fd")
+        _procFd = err == nil
+    })
+    if !_procFd { return nil, errAnonUnsupported }
+    fh, err := os.OpenFile(dir, os.O_RDWR | _oTmpFile, 0600)
+    if err == nil { return fh, nil }
+    var errno syscall.Errno
+    if !errors.As(err, &errno) || (errno != syscall.EISDIR && errno != syscall.EOPNOTSUPP && errno != syscall.EINVAL) {
+        return nil, err
+    }
+    if fh, err = ioutil.TempFile(dir, ".unlinked_"); err != nil { return nil, err }
+    if err = os.Remove(fh.Name()); err != nil {
+        fh.Close()
+        os.Remove(fh.Name())
+        return nil, errAnonUnsupported
+    }
+    return fh, nil
+} //end func openAnonymous
+func reopenAnonymous(fh *os.File, flag int) (*os.File, error) {
+    //Opens a new handle, with its own offset, on the file of an anonymous one
+    return os.OpenFile(fmt.Sprintf("/proc/self/fd/%d", fh.Fd()), flag, 0)
+} //end func reopenAnonymous
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file anonfiles_linux.go
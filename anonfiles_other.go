@@ -0,0 +1,31 @@
+//go:build !linux
+// +build !linux
+
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     anonfiles_other.go
+ * Overview:
+ *     anonymous temporary files, on the systems without O_TMPFILE, where the temporary files keep their names.
+ * History:
+ *     v1.59.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "errors"
+    "os"
+)
+//Private ----------------------------------------------------------------------------------------------------------------------
+var errAnonUnsupported = errors.New("anonymous files unsupported") //no anonymous files on the system
+func openAnonymous(dir string) (*os.File, error) {
+    //Returns that anonymous files are not supported
+    return nil, errAnonUnsupported
+} //end func openAnonymous
+func reopenAnonymous(fh *os.File, flag int) (*os.File, error) {
+    //Returns that anonymous files are not supported
+    return nil, errAnonUnsupported
+} //end func reopenAnonymous
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file anonfiles_other.go
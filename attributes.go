@@ -0,0 +1,55 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     attributes.go
+ * Overview:
+ *     copy of the permissions, modification time and owner of the input files to their sorted output.
+ * Functions:
+ *     WithPreserveAttributes() Option
+ *         Option giving the output the mode bits, modification time and, where permitted, owner of the input.
+ * History:
+ *     v1.75.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "os"
+    "time"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func WithPreserveAttributes() Option {
+/*         Purpose : Gives the output the mode bits, modification time and, where permitted, owner of the input.
+ *       Arguments : None.
+ *         Returns : The option.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : None.
+ *         Remarks : Applies to Sort, SortAndReduce, Run, SortWithKeys, MergeRuns, MergeInto, whose output takes the
+ *                   attributes of the master file, and SortGlob. The input is read with os.Stat once the output is
+ *                   written, synced and closed, and before any removal per WithRemoveInput. On the BSDs, Linux, macOS
+ *                   and Solaris, the owner and group are copied first, then the permission, setuid, setgid and sticky
+ *                   bits, and the modification time; a process without the privilege to give the output the input's
+ *                   owner or group, typically one not running as root, silently keeps its own. On Windows, only the
+ *                   read-only attribute follows the mode bits, the owner and ACLs being those of a new file. Elsewhere,
+ *                   the owner is not copied. The access time of the output, the input's extended attributes and ACLs
+ *                   and, on the systems recording one, its creation time are never copied. A failure to copy the mode
+ *                   or time is returned as an error, the output being complete. SortFS and RunFS, whose inputs are not
+ *                   on the OS file system, fail on the option before reading them.
+ *         History : v1.75.0 - October 15, 2026 - Original release.
+ */
+    return func(s *Sorter) { s.preserveAttrs = true }
+} //end func WithPreserveAttributes
+//Private ----------------------------------------------------------------------------------------------------------------------
+const _preservedMode = os.ModePerm | os.ModeSetuid | os.ModeSetgid | os.ModeSticky //mode bits copied to the output
+func (r *sortRun) preserveAttributes(inFile, outFile string) {
+    //Gives the output the mode bits, modification time & owner of the input if requested, halting if they cannot be set
+    if !r.preserveAttrs { return }
+    fi, err := os.Stat(inFile)
+    if err != nil { halt("os.Stat - " + err.Error()) }
+    preserveOwner(fi, outFile)                                    //first, a change of owner clearing the setuid bits
+    if err := os.Chmod(outFile, fi.Mode() & _preservedMode);   err != nil { halt("os.Chmod - " + err.Error()) }
+    if err := os.Chtimes(outFile, time.Time{}, fi.ModTime()); err != nil { halt("os.Chtimes - " + err.Error()) }
+} //end func preserveAttributes
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file attributes.go
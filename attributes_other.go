@@ -0,0 +1,24 @@
+//go:build !aix && !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !solaris
+// +build !aix,!darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!solaris
+
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     attributes_other.go
+ * Overview:
+ *     copy of the owner of an input file to its output, on the systems without Unix ownership.
+ * History:
+ *     v1.75.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "os"
+)
+//Private ----------------------------------------------------------------------------------------------------------------------
+func preserveOwner(fi os.FileInfo, outFile string) {
+    //Leaves the output to the owner of the process, the input's not being copied on this system
+} //end func preserveOwner
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file attributes_other.go
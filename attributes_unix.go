@@ -0,0 +1,26 @@
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris
+
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     attributes_unix.go
+ * Overview:
+ *     copy of the owner of an input file to its output, on the systems with Unix ownership.
+ * History:
+ *     v1.75.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "os"
+    "syscall"
+)
+//Private ----------------------------------------------------------------------------------------------------------------------
+func preserveOwner(fi os.FileInfo, outFile string) {
+    //Gives the output the owner & group of the input, keeping the process's own without the privilege to do so
+    if st, ok := fi.Sys().(*syscall.Stat_t); ok { os.Chown(outFile, int(st.Uid), int(st.Gid)) }
+} //end func preserveOwner
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file attributes_unix.go
@@ -0,0 +1,153 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     bench
+ * File:
+ *     bench.go
+ * Overview:
+ *     benchmark harness of the mergesort package: external sorts of generated test files, measured consistently.
+ * Functions:
+ *     RunBenchmark(spec mergesort.GenSpec, opts ...mergesort.Option) (BenchResult, error)
+ *         Generates a test file per spec, sorts it once with the options and measures the sort.
+ *     Sort(b *testing.B, spec mergesort.GenSpec, opts ...mergesort.Option)
+ *         Runs a testing.B benchmark sorting a test file generated per spec with the options.
+ * Types:
+ *     BenchResult
+ *         Measurements of a benchmarked sort.
+ * Variables:
+ *     Small, Large
+ *         Test files of about 8MB and 150MB, for comparisons at a couple of sizes.
+ * History:
+ *     v1.41.0 - October 15, 2026 - Original release.
+ *     v1.92.0 - October 15, 2026 - Added BenchResult.Allocs; Sort reports the allocations.
+ *============================================================================================================================*/
+package bench
+
+import(
+    "io/ioutil"
+    "os"
+    "path/filepath"
+    "runtime"
+    "testing"
+    "time"
+
+    "github.com/ybeaudoin/go-mergesort"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+type BenchResult struct {
+    Records    int64                      //number of records sorted
+    InputBytes int64                      //size of the input file
+    Wall       time.Duration              //elapsed time of the sort
+    CPU        time.Duration              //user & system CPU time of the process during the sort, 0 if unobtainable
+    TempBytes  int64                      //bytes written to the temporary files
+    Passes     int                        //merge passes over the key files
+    PeakRSS    int64                      //peak resident set size of the process, in bytes, 0 if unobtainable
+    Allocs     int64                      //heap allocations of the process during the sort
+}
+var(
+    Small = mergesort.GenSpec{Records:200000,  Seed:1, DupRate:0.1, Fields:_benchFields} //about 8MB
+    Large = mergesort.GenSpec{Records:4000000, Seed:1, DupRate:0.1, Fields:_benchFields} //about 150MB
+)
+
+func RunBenchmark(spec mergesort.GenSpec, opts ...mergesort.Option) (result BenchResult, err error) {
+/*         Purpose : Generates a test file per spec, sorts it once with the options and measures the sort.
+ *       Arguments : spec = the specification of the test file, e.g. Small or Large.
+ *                   opts = the options of the sorter. Without WithFields, the first field is the index. A WithStats
+ *                          option is superseded by the harness's own.
+ *         Returns : The measurements, and any error encountered.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : measure, newBenchDir, newBenchSorter
+ *         Remarks : The input and output files reside in a directory created under the OS temporary directory and
+ *                   removed on return; only the sort itself is timed. The CPU time and peak RSS are those of the whole
+ *                   process as reported by getrusage, so they are only meaningful in a process running nothing else.
+ *                   The peak RSS is the highest since the process started. Both are 0 where getrusage is missing.
+ *                   The heap allocations, from runtime.ReadMemStats, are likewise those of the whole process.
+ *         History : v1.41.0 - October 15, 2026 - Original release.
+ *                   v1.92.0 - October 15, 2026 - Added the heap allocations.
+ */
+    dir, err := newBenchDir(spec)
+    if err != nil { return result, err }
+    defer os.RemoveAll(dir)
+    sorter, stats, err := newBenchSorter(opts)
+    if err != nil { return result, err }
+    return measure(sorter, stats, dir, spec.Records)
+} //end func RunBenchmark
+func Sort(b *testing.B, spec mergesort.GenSpec, opts ...mergesort.Option) {
+/*         Purpose : Runs a testing.B benchmark sorting a test file generated per spec with the options.
+ *       Arguments : b    = the benchmark.
+ *                   spec = the specification of the test file, e.g. Small or Large.
+ *                   opts = the options of the sorter. Without WithFields, the first field is the index.
+ *         Returns : None.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : measure, newBenchDir, newBenchSorter
+ *         Remarks : The file is generated once, outside the timer, and sorted b.N times. The throughput is reported on
+ *                   the input bytes, along with the allocations, and the temporary bytes and merge passes of a sort as
+ *                   custom metrics. To be called from a benchmark function of a _test.go file, e.g.
+ *                       func BenchmarkSmall(b *testing.B) { bench.Sort(b, bench.Small, mergesort.WithKeysPerSort(20000)) }
+ *         History : v1.41.0 - October 15, 2026 - Original release.
+ *                   v1.92.0 - October 15, 2026 - Reports the allocations.
+ */
+    dir, err := newBenchDir(spec)
+    if err != nil { b.Fatal(err) }
+    defer os.RemoveAll(dir)
+    sorter, stats, err := newBenchSorter(opts)
+    if err != nil { b.Fatal(err) }
+    var result BenchResult
+    b.ReportAllocs()
+    b.ResetTimer()
+    for k := 0; k < b.N; k++ {
+        if result, err = measure(sorter, stats, dir, spec.Records); err != nil { b.Fatal(err) }
+    }
+    b.StopTimer()
+    b.SetBytes(result.InputBytes)
+    b.ReportMetric(float64(result.TempBytes), "tempB/op")
+    b.ReportMetric(float64(result.Passes),    "passes/op")
+} //end func Sort
+//Private ----------------------------------------------------------------------------------------------------------------------
+var _benchFields = []mergesort.GenField{                            //fields of Small & Large
+    {Type:mergesort.GenString, Width:12},
+    {Type:mergesort.GenInt,    Width:9},
+    {Type:mergesort.GenDate},
+    {Type:mergesort.GenString, Width:24},
+}
+func newBenchDir(spec mergesort.GenSpec) (string, error) {
+    //Creates a directory holding the test file "in.txt" generated per spec
+    dir, err := ioutil.TempDir("", "mergesort_bench_")
+    if err != nil { return "", err }
+    if err := mergesort.GenerateTestFile(filepath.Join(dir, "in.txt"), spec); err != nil {
+        os.RemoveAll(dir)
+        return "", err
+    }
+    return dir, nil
+} //end func newBenchDir
+func newBenchSorter(opts []mergesort.Option) (*mergesort.Sorter, *mergesort.SortStats, error) {
+    //Creates the sorter of a benchmark, indexed on the first field unless the options say otherwise, with its statistics
+    stats     := &mergesort.SortStats{}
+    opts       = append(append([]mergesort.Option{mergesort.WithFields("1")}, opts...), mergesort.WithStats(stats))
+    sorter, err := mergesort.NewSorter(opts...)
+    return sorter, stats, err
+} //end func newBenchSorter
+func measure(sorter *mergesort.Sorter, stats *mergesort.SortStats, dir string, records int64) (result BenchResult,
+                                                                                                err error) {
+    //Sorts the test file of dir to "out.txt" and measures the sort
+    inFile, outFile := filepath.Join(dir, "in.txt"), filepath.Join(dir, "out.txt")
+    fi, err         := os.Stat(inFile)
+    if err != nil { return result, err }
+    var memStart, memEnd runtime.MemStats
+    runtime.ReadMemStats(&memStart)
+    cpuStart, _     := processUsage()
+    start           := time.Now()
+    err              = sorter.Run(inFile, outFile)
+    result.Wall      = time.Since(start)
+    if err != nil { return result, err }
+    cpuEnd, peakRSS := processUsage()
+    runtime.ReadMemStats(&memEnd)
+    result.Allocs    = int64(memEnd.Mallocs - memStart.Mallocs)
+    result.Records, result.InputBytes = records, fi.Size()
+    result.CPU, result.PeakRSS        = cpuEnd - cpuStart, peakRSS
+    result.TempBytes, result.Passes   = stats.TempBytes, stats.MergePasses
+    return result, nil
+} //end func measure
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file bench.go
@@ -0,0 +1,49 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     bench
+ * File:
+ *     bench_test.go
+ * Overview:
+ *     testing.B benchmarks of external sorts of the generated test files, so that "go test -bench ." gives performance
+ *     changes a consistent yardstick.
+ * Functions:
+ *     BenchmarkSort(b *testing.B)
+ *         Sorts Small and Large, sweeping keysPerSort and the in-memory buffering of the temporary files.
+ * History:
+ *     v1.41.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package bench
+
+import(
+    "fmt"
+    "testing"
+
+    "github.com/ybeaudoin/go-mergesort"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func BenchmarkSort(b *testing.B) {
+    //Sub-benchmarks named e.g. "Small/keys=100000/buffer=64MB"; Large is skipped with -short
+    presets := []struct {
+        name string
+        spec mergesort.GenSpec
+    }{{"Small", Small}, {"Large", Large}}
+    for _, preset := range presets {
+        b.Run(preset.name, func(b *testing.B) {
+            if preset.name == "Large" && testing.Short() { b.Skip("Large is skipped in short mode") }
+            for _, keys := range _benchKeysPerSort {
+                for _, buffer := range _benchBuffers {
+                    b.Run(fmt.Sprintf("keys=%d/buffer=%dMB", keys, buffer >> 20), func(b *testing.B) {
+                        Sort(b, preset.spec, mergesort.WithKeysPerSort(keys), mergesort.WithInMemorySpillThreshold(buffer))
+                    })
+                }
+            }
+        })
+    }
+} //end func BenchmarkSort
+//Private ----------------------------------------------------------------------------------------------------------------------
+var(
+    _benchKeysPerSort = []int{20000, 100000, 400000}              //keys per initial run swept by BenchmarkSort
+    _benchBuffers     = []int64{0, 64 << 20}                      //in-memory budgets of the temporary files swept
+)
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file bench_test.go
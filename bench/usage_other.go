@@ -0,0 +1,25 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd
+
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     bench
+ * File:
+ *     usage_other.go
+ * Overview:
+ *     CPU time & peak memory of the process, on the systems without getrusage.
+ * History:
+ *     v1.41.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package bench
+
+import(
+    "time"
+)
+//Private ----------------------------------------------------------------------------------------------------------------------
+func processUsage() (cpu time.Duration, peakRSS int64) {
+    //Reports both as unobtainable
+    return 0, 0
+} //end func processUsage
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file usage_other.go
@@ -0,0 +1,32 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     bench
+ * File:
+ *     usage_unix.go
+ * Overview:
+ *     CPU time & peak memory of the process through getrusage.
+ * History:
+ *     v1.41.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package bench
+
+import(
+    "runtime"
+    "syscall"
+    "time"
+)
+//Private ----------------------------------------------------------------------------------------------------------------------
+func processUsage() (cpu time.Duration, peakRSS int64) {
+    //Returns the user & system CPU time of the process and its peak resident set size in bytes, zeros on failure
+    var usage syscall.Rusage
+    if err := syscall.Getrusage(syscall.RUSAGE_SELF, &usage); err != nil { return 0, 0 }
+    cpu     = time.Duration(usage.Utime.Nano() + usage.Stime.Nano())
+    peakRSS = int64(usage.Maxrss)
+    if runtime.GOOS != "darwin" { peakRSS *= 1024 }              //kilobytes but on macOS
+    return
+} //end func processUsage
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file usage_unix.go
@@ -0,0 +1,79 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     booleans.go
+ * Overview:
+ *     boolean index fields, whose true and false tokens vary from one source system to another.
+ * Functions:
+ *     WithBooleanTokens(column int, truthy, falsy []string) Option
+ *         Option setting the tokens of a boolean field.
+ * History:
+ *     v1.52.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "fmt"
+    "strings"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func WithBooleanTokens(column int, truthy, falsy []string) Option {
+/*         Purpose : Sets the tokens of a boolean field.
+ *       Arguments : column = number of the field, the first being 1. Must be an index field of type FieldBoolean.
+ *                   truthy = the tokens standing for true. Must not be empty.
+ *                   falsy  = the tokens standing for false. Must not be empty.
+ *         Returns : The option.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : boolTokenMap
+ *         Remarks : The tokens are matched whatever their case, the values being trimmed of spaces, and replace the
+ *                   default ones, e.g. []string{"Y", "1"} and []string{"N", "0"}. A token may not be both truthy and
+ *                   falsy. False values precede true ones in ascending sorts. The values matching neither set are
+ *                   counted as invalid in SortStats and placed per WithInvalidValuesLast. A later option for the same
+ *                   field replaces an earlier one.
+ *         History : v1.52.0 - October 15, 2026 - Original release.
+ */
+    return func(s *Sorter) {
+        if s.boolTokens == nil { s.boolTokens = map[int]map[string]string{} }
+        s.boolTokens[column - 1] = boolTokenMap(truthy, falsy)
+    }
+} //end func WithBooleanTokens
+//Private ----------------------------------------------------------------------------------------------------------------------
+const _conflictToken = "" //encoding of a token that is both truthy and falsy
+var _defaultBoolTokens = boolTokenMap([]string{"true", "t", "yes", "y", "on", "1"},
+                                      []string{"false", "f", "no", "n", "off", "0"}) //tokens of WithFieldType
+func boolTokenMap(truthy, falsy []string) map[string]string {
+    //Returns the encodings of the lowercased tokens, "1" for the truthy ones and "0" for the falsy ones
+    tokens := map[string]string{}
+    for _, token := range truthy { tokens[strings.ToLower(strings.TrimSpace(token))] = "1" }
+    for _, token := range falsy {
+        token = strings.ToLower(strings.TrimSpace(token))
+        if tokens[token] == "1" { tokens[token] = _conflictToken } else { tokens[token] = "0" }
+    }
+    return tokens
+} //end func boolTokenMap
+func (s *Sorter) checkBoolTokens() {
+    //Halts on boolean tokens set for a field that is not a boolean index field, or on empty or conflicting token sets
+    for colIdx, tokens := range s.boolTokens {
+        if !s.isIndexField(colIdx) { haltKind(ErrBadFieldSpec, fmt.Sprintf("the boolean column %d is not an index field",
+                                                                          colIdx + 1), nil) }
+        if s.fieldTypes[colIdx] != FieldBoolean {
+            halt(fmt.Sprintf("the column %d given boolean tokens is not of type FieldBoolean", colIdx + 1))
+        }
+        counts := map[string]int{}
+        for token, encoded := range tokens {
+            if encoded == _conflictToken { halt(fmt.Sprintf("the token %q of column %d is both truthy and falsy", token,
+                                                            colIdx + 1)) }
+            counts[encoded]++
+        }
+        if counts["0"] == 0 || counts["1"] == 0 { halt(fmt.Sprintf("the boolean tokens of column %d are missing", colIdx + 1)) }
+    }
+} //end func checkBoolTokens
+func encodeBoolean(value string, tokens map[string]string) (string, bool) {
+    //Maps a boolean token onto "0" for false or "1" for true
+    encoded, ok := tokens[strings.ToLower(strings.TrimSpace(value))]
+    return encoded, ok
+} //end func encodeBoolean
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file booleans.go
@@ -0,0 +1,156 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     clusters.go
+ * Overview:
+ *     clustering of the records of consecutive keys lying close together in the input, so that each cluster is fetched by a
+ *     single sequential read.
+ * History:
+ *     v1.37.0 - October 15, 2026 - Original release.
+ *     v1.38.0 - October 15, 2026 - Clusters of records embedded in their keys.
+ *     v1.61.0 - October 15, 2026 - Clusters read by positioned reads only.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "bufio"
+    "errors"
+    "io"
+    "strconv"
+    "strings"
+    "sync"
+)
+//Private ----------------------------------------------------------------------------------------------------------------------
+const(
+    _clusterKeys = 256                  //most keys in a cluster
+    _clusterGap  = 4096                 //most bytes skipped between two records of a cluster
+    _clusterSpan = 1 << 18              //most bytes read for a cluster
+)
+type recordCluster struct {
+    keys    []string                    //composite keys of the records, in key order
+    offsets []int64                     //offsets of the records in the input, increasing
+    lengths []int                       //lengths of the records, terminators included
+    start   int64                       //offset of the first record
+    end     int64                       //offset following the last record
+    data    []byte                      //bytes from start to end, once fetched
+    inline  []string                    //records embedded in the keys, if the cluster holds such keys
+    err     error                       //failure of the parsing of the keys or of the fetch
+    done    chan struct{}               //closed once fetched, when fetched in the background
+}
+type clusterScanner struct {
+    scanner *bufio.Scanner              //scanner of the composite keys
+    key     string                      //key read ahead, if any
+    offset  int64                       //its record's offset
+    length  int                         //its record's length
+    err     error                       //failure of its parsing
+    payload string                      //its embedded record, if any
+    inline  bool                        //record embedded in the key
+    haveKey bool                        //key read ahead
+}
+func newClusterScanner(scannerKeys *bufio.Scanner) *clusterScanner {
+    return &clusterScanner{scanner:scannerKeys}
+} //end func newClusterScanner
+func (c *clusterScanner) next() (*recordCluster, bool) {
+    //Returns the next cluster of keys, i.e. those whose records follow each other in the input with gaps of at most
+    //_clusterGap bytes or those embedding their records, or false if the keys have run out. A key that cannot be parsed
+    //makes up a cluster of its own, and a failure of the scan an empty one, with its err set.
+    cluster := &recordCluster{}
+    for {
+        if !c.haveKey {
+            if !c.scanner.Scan() { break }
+            c.key, c.payload, c.inline = splitPayload(c.scanner.Text())
+            c.offset, c.length, c.err  = keyRecord(c.key)
+            c.haveKey                  = true
+            if c.err != nil { c.offset = -1 }
+        }
+        n := len(cluster.keys)
+        if n > 0 && (c.err != nil || n == _clusterKeys || c.inline != (cluster.inline != nil)) { return cluster, true }
+        if n > 0 && !c.inline && (c.offset < cluster.end || c.offset - cluster.end > _clusterGap ||
+                                  c.offset + int64(c.length) - cluster.start > _clusterSpan) { return cluster, true }
+        if n == 0 { cluster.start = c.offset }
+        cluster.keys    = append(cluster.keys,    c.key)
+        cluster.offsets = append(cluster.offsets, c.offset)
+        cluster.lengths = append(cluster.lengths, c.length)
+        cluster.end     = c.offset + int64(c.length)
+        cluster.err     = c.err
+        if c.inline { cluster.inline = append(cluster.inline, c.payload) }
+        c.haveKey       = false
+        if c.err != nil { return cluster, true }
+    }
+    if len(cluster.keys) > 0 { return cluster, true }
+    cluster.err = c.scanner.Err()
+    return cluster, cluster.err != nil
+} //end func next
+func keyRecord(key string) (offset int64, length int, err error) {
+    //Returns the offset & length of the record of a composite key
+    parts := strings.Split(key, _asciiGS)
+    if len(parts) < 3 { return 0, 0, errors.New("the composite key lacks its record offset or length") }
+    offset, err = strconv.ParseInt(strings.TrimLeft(parts[1], " "), 10, 64)
+    if err == nil { length, err = strconv.Atoi(strings.TrimLeft(parts[2], " ")) }
+    return
+} //end func keyRecord
+func (c *recordCluster) readAt(fhIn io.ReaderAt) {
+    //Fetches the bytes of the cluster by a positioned read
+    if c.err != nil || c.inline != nil { return }
+    c.data   = make([]byte, c.end - c.start)
+    n, err  := fhIn.ReadAt(c.data, c.start)
+    if n == len(c.data) { err = nil }
+    c.err    = err
+} //end func readAt
+type seekingReaderAt struct {
+    mutex sync.Mutex
+    fh    io.ReadSeeker                 //file lacking positioned reads, e.g. a temporary file
+}
+func readerAt(fh io.ReadSeeker) io.ReaderAt {
+    //Returns the file itself if it supports positioned reads, or an adapter emulating them by seeks otherwise
+    if fhAt, ok := fh.(io.ReaderAt); ok { return fhAt }
+    return &seekingReaderAt{fh:fh}
+} //end func readerAt
+func (s *seekingReaderAt) ReadAt(p []byte, offset int64) (int, error) {
+    //Reads len(p) bytes at offset by a seek and a sequential read, the seek state being private to the adapter
+    s.mutex.Lock()
+    defer s.mutex.Unlock()
+    if _, err := s.fh.Seek(offset, io.SeekStart); err != nil { return 0, err }
+    return io.ReadFull(s.fh, p)
+} //end func ReadAt
+func (c *recordCluster) record(k int) string {
+    //Returns the k-th record of a fetched cluster
+    if c.inline != nil { return c.inline[k] }
+    start := c.offsets[k] - c.start
+    return string(c.data[start:start + int64(c.lengths[k])])
+} //end func record
+func emitClusters(next func() (*recordCluster, bool), keysFile string, at *RecordError,
+                  emit func(key, record string, lastInGroup bool) bool) {
+    //Feeds emit with the records of the fetched clusters returned by next, looking ahead for the group boundaries, until
+    //the clusters run out or emit returns false
+    cluster, haveCluster := next()
+    for haveCluster {
+        var(
+            following     *recordCluster                          //cluster after this one
+            haveFollowing bool
+        )
+        for k, key := range cluster.keys {
+            *at = RecordError{Offset:cluster.offsets[k], File:keysFile, Key:key}
+            if cluster.err != nil { halt("fetching the records - " + cluster.err.Error()) }
+            record   := cluster.record(k)
+            at.Record = strings.TrimRight(record, "\r\n")
+            nextKey, haveNext := "", k + 1 < len(cluster.keys)
+            if haveNext {
+                nextKey = cluster.keys[k + 1]
+            } else if following, haveFollowing = next(); haveFollowing && len(following.keys) > 0 {
+                nextKey, haveNext = following.keys[0], true
+            }
+            if !emit(key, record, !haveNext || !sameGroup(key, nextKey)) { return }
+        }
+        if len(cluster.keys) == 0 {                               //failure of the key scan
+            *at = RecordError{}
+            haltKind(nil, "scannerKeys.Scan", cluster.err)
+        }
+        cluster, haveCluster = following, haveFollowing
+    }
+    *at = RecordError{}
+    return
+} //end func emitClusters
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file clusters.go
@@ -0,0 +1,104 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     clusters_test.go
+ * Overview:
+ *     benchmarks of the clustered reads of the records on nearly sorted inputs, counting the reads of the input.
+ * Functions:
+ *     BenchmarkNearlySorted(b *testing.B)
+ *         Sorts inputs of which 90% or none of the records are in order, reporting the reads of the input per sort.
+ * History:
+ *     v1.37.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "fmt"
+    "io"
+    "io/fs"
+    "math/rand"
+    "os"
+    "path/filepath"
+    "sort"
+    "strings"
+    "sync/atomic"
+    "testing"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func BenchmarkNearlySorted(b *testing.B) {
+    //Sub-benchmarks "sorted=90%" and "sorted=0%", reporting the read calls and bytes of the input per sort
+    rng     := rand.New(rand.NewSource(10))
+    records := strings.SplitAfter(randomInput(rng, 200000), "\n")
+    records  = records[:len(records) - 1]
+    if err := SortStrings(records, true, "2,1", "\t"); err != nil { b.Fatal(err) }
+    for _, sorted := range []int{90, 0} {
+        //Moves the other records to random positions, the others keeping their order
+        input, positions := append([]string(nil), records...), make([]int, len(records))
+        for k := range positions {
+            positions[k] = k
+            if rng.Intn(100) >= sorted { positions[k] = rng.Intn(len(records)) }
+        }
+        sort.Stable(byPosition{input, positions})
+        inFile := writeInput(b, strings.Join(input, ""))
+        b.Run(fmt.Sprintf("sorted=%d%%", sorted), func(b *testing.B) {
+            var stats SortStats
+            fsys        := &readCounter{FS:os.DirFS(filepath.Dir(inFile))}
+            sorter, err := NewSorter(WithFields("2,1"), WithKeysPerSort(50000), WithTempDir(b.TempDir()), WithStats(&stats))
+            if err != nil { b.Fatal(err) }
+            outFile := filepath.Join(b.TempDir(), "out.txt")
+            b.SetBytes(int64(len(strings.Join(input, ""))))
+            b.ResetTimer()
+            for k := 0; k < b.N; k++ {
+                if err := sorter.RunFS(fsys, filepath.Base(inFile), outFile); err != nil { b.Fatal(err) }
+            }
+            b.StopTimer()
+            b.ReportMetric(float64(atomic.LoadInt64(&fsys.reads)) / float64(b.N), "reads/op")
+            b.ReportMetric(float64(atomic.LoadInt64(&fsys.bytes)) / float64(b.N), "readB/op")
+        })
+    }
+} //end func BenchmarkNearlySorted
+//Private ----------------------------------------------------------------------------------------------------------------------
+type byPosition struct {
+    records   []string
+    positions []int                                               //position of each record in the input
+}
+type readCounter struct {
+    fs.FS
+    reads int64                                                   //read calls on the files opened
+    bytes int64                                                   //bytes they read
+}
+type countedInput struct {
+    fs.File
+    fsys *readCounter
+}
+func (p byPosition) Len() int           { return len(p.records) }
+func (p byPosition) Less(i, j int) bool { return p.positions[i] < p.positions[j] }
+func (p byPosition) Swap(i, j int) {
+    p.records[i], p.records[j]     = p.records[j], p.records[i]
+    p.positions[i], p.positions[j] = p.positions[j], p.positions[i]
+} //end func Swap
+func (c *readCounter) Open(name string) (fs.File, error) {
+    fh, err := c.FS.Open(name)
+    if err != nil { return nil, err }
+    return &countedInput{File:fh, fsys:c}, nil
+} //end func Open
+func (f *countedInput) count(n int) {
+    atomic.AddInt64(&f.fsys.reads, 1)
+    atomic.AddInt64(&f.fsys.bytes, int64(n))
+} //end func count
+func (f *countedInput) Read(p []byte) (int, error) {
+    n, err := f.File.Read(p)
+    f.count(n)
+    return n, err
+} //end func Read
+func (f *countedInput) ReadAt(p []byte, offset int64) (int, error) {
+    n, err := f.File.(io.ReaderAt).ReadAt(p, offset)
+    f.count(n)
+    return n, err
+} //end func ReadAt
+func (f *countedInput) Seek(offset int64, whence int) (int64, error) {
+    return f.File.(io.Seeker).Seek(offset, whence)
+} //end func Seek
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file clusters_test.go
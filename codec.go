@@ -0,0 +1,164 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     codec.go
+ * Overview:
+ *     compression of the composite-key files by a pluggable codec, trading CPU for temporary space, each file naming its
+ *     codec in a header so that it is never read with another.
+ * Functions:
+ *     WithTempCodec(codec Codec) Option
+ *         Option compressing the composite-key files with a codec.
+ * Types:
+ *     Codec
+ *         Compression scheme of the composite-key files.
+ *     GzipCodec
+ *         Codec of compress/gzip.
+ * History:
+ *     v1.63.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "bytes"
+    "compress/gzip"
+    "fmt"
+    "io"
+    "strings"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+type Codec interface {
+    Name() string                                                 //name recorded in the header of every file encoded
+    WrapWriter(w io.Writer) (io.WriteCloser, error)               //encoder writing to w, flushed by its Close
+    WrapReader(r io.Reader) (io.ReadCloser, error)                //decoder reading from r
+}
+type GzipCodec struct {
+    Level int                                                     //compression level of compress/gzip, 0 for BestSpeed
+}
+
+func WithTempCodec(codec Codec) Option {
+/*         Purpose : Compresses the composite-key files with a codec.
+ *       Arguments : codec = the codec, nil for none, the default.
+ *         Returns : The option.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : None.
+ *         Remarks : Every run and merge file then starts with a plain header naming the codec, followed by its keys and
+ *                   trailer as encoded by the codec. A key file is read only with the codec that wrote it: one written
+ *                   with another codec, or with none while a codec is set or vice versa, fails the sort with
+ *                   ErrCodecMismatch instead of being misread, e.g. the runs of GenerateRuns merged by MergeRuns with
+ *                   other settings. Decoding failures are reported as ErrCorruptKeys. The name of a codec must be unique
+ *                   to its format, non-empty and free of line feeds and control characters. The spill files of a
+ *                   SpillQueue and the input spooled by SortChan or RunFS are not compressed. GzipCodec is provided;
+ *                   faster codecs such as zstd plug in through a small adapter, see the README.
+ *         History : v1.63.0 - October 15, 2026 - Original release.
+ */
+    return func(s *Sorter) { s.codec = codec }
+} //end func WithTempCodec
+func (c GzipCodec) Name() string { return "gzip" }                //name recorded in the headers of the files encoded
+func (c GzipCodec) WrapWriter(w io.Writer) (io.WriteCloser, error) {
+/*         Purpose : Returns a gzip encoder writing to w.
+ *       Arguments : w = the destination of the compressed bytes.
+ *         Returns : The encoder, to be closed once all the bytes are written, and any error encountered.
+ *         History : v1.63.0 - October 15, 2026 - Original release.
+ */
+    level := c.Level
+    if level == 0 { level = gzip.BestSpeed }                      //short-lived data: speed over size
+    return gzip.NewWriterLevel(w, level)
+} //end func WrapWriter
+func (c GzipCodec) WrapReader(r io.Reader) (io.ReadCloser, error) {
+/*         Purpose : Returns a gzip decoder reading from r.
+ *       Arguments : r = the source of the compressed bytes.
+ *         Returns : The decoder and any error encountered.
+ *         History : v1.63.0 - October 15, 2026 - Original release.
+ */
+    return gzip.NewReader(r)
+} //end func WrapReader
+//Private ----------------------------------------------------------------------------------------------------------------------
+const(
+    _codecMagic = "mergesort-codec-v1" //first part of the first line of a key file encoded by a codec
+    _codecMax   = 256                  //longest header read, including its line feed
+)
+type decodedFile struct {
+    TempFile
+    decoder  io.ReadCloser                                        //codec's decoder of the file's keys
+    name     string                                               //name of the file, for the errors
+}
+func (s *Sorter) checkCodec() {
+    //Halts on a codec whose name cannot be recorded in a header, or that cannot create an encoder
+    if s.codec == nil { return }
+    name := s.codec.Name()
+    if name == "" || strings.IndexFunc(name, func(c rune) bool { return c < ' ' }) >= 0 ||
+       len(_codecMagic + _asciiGS + name) >= _codecMax {
+        halt(fmt.Sprintf("the codec name %q is empty, too long or holds control characters", name))
+    }
+    encoder, err := s.codec.WrapWriter(io.Discard)
+    if err == nil { err = encoder.Close() }
+    if err != nil { halt(fmt.Sprintf("the codec %q cannot encode - %v", name, err)) }
+} //end func checkCodec
+func (r *sortRun) encodeKeys(fh io.Writer) io.WriteCloser {
+    //Writes the header naming the run's codec to a new key file and returns the codec's encoder of its keys
+    if _, err := fmt.Fprintf(fh, "%s%s%s\n", _codecMagic, _asciiGS, r.codec.Name()); err != nil {
+        haltTemp("writing the codec header", err)
+    }
+    encoder, err := r.codec.WrapWriter(fh)
+    if err != nil { halt("WrapWriter - " + err.Error()) }
+    return encoder
+} //end func encodeKeys
+func (r *sortRun) decodeKeys(fh TempFile, name string) TempFile {
+    //Returns the key file with its keys decoded by the run's codec if it has a header, or unchanged otherwise, positioned at
+    //its first key; halts, closing it, if the header does not name the run's codec
+    codec    := codecHeader(fh)
+    mismatch := ""
+    switch {
+        case codec == "" && r.codec == nil:
+            return fh
+        case codec == "":
+            mismatch = fmt.Sprintf("is not encoded but the codec %q is set", r.codec.Name())
+        case r.codec == nil:
+            mismatch = fmt.Sprintf("is encoded with the codec %q but none is set", codec)
+        case codec != r.codec.Name():
+            mismatch = fmt.Sprintf("is encoded with the codec %q but %q is set", codec, r.codec.Name())
+    }
+    if mismatch != "" {
+        fh.Close()
+        haltKind(ErrCodecMismatch, "the key file " + name + " " + mismatch, nil)
+    }
+    decoder, err := r.codec.WrapReader(fh)
+    if err != nil {
+        fh.Close()
+        haltKind(ErrCorruptKeys, "the key file " + name + " cannot be decoded", err)
+    }
+    return &decodedFile{TempFile:fh, decoder:decoder, name:name}
+} //end func decodeKeys
+func codecHeader(fh io.ReadSeeker) (codec string) {
+    //Returns the codec named by the header of a key file, "" if none, leaving the file positioned past the header
+    var length int64                                              //length of the header, 0 if none
+    head   := make([]byte, _codecMax)
+    n, err := io.ReadFull(fh, head)
+    if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF { halt("reading the codec header - " + err.Error()) }
+    head    = head[:n]
+    prefix := _codecMagic + _asciiGS
+    if k := bytes.IndexByte(head, '\n'); k > len(prefix) && strings.HasPrefix(string(head[:k]), prefix) {
+        codec, length = string(head[len(prefix):k]), int64(k + 1)
+    }
+    if _, err = fh.Seek(length, io.SeekStart); err != nil { halt("Seek - " + err.Error()) }
+    return
+} //end func codecHeader
+func (f *decodedFile) Read(p []byte) (int, error) {
+    //Reads decoded keys, classing the failures to decode as ErrCorruptKeys
+    n, err := f.decoder.Read(p)
+    if err != nil && err != io.EOF {
+        err = &kindError{kind:ErrCorruptKeys, err:fmt.Errorf("mergesort: the key file %s cannot be decoded - %w", f.name,
+                                                             err)}
+    }
+    return n, err
+} //end func Read
+func (f *decodedFile) Close() error {
+    //Closes the decoder, then the file
+    err := f.decoder.Close()
+    if errClose := f.TempFile.Close(); err == nil { err = errClose }
+    return err
+} //end func Close
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file codec.go
@@ -0,0 +1,103 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     compare.go
+ * Overview:
+ *     streaming three-way comparison of two text files sorted by the package, as done by comm.
+ * Functions:
+ *     CompareSorted(fileA, fileB string, usingFields, sep string, sortAsc bool, onlyA, onlyB, both io.Writer) error
+ *         Routes the records of two sorted files to those only in the first, only in the second, or in both.
+ * History:
+ *     v1.64.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "bufio"
+    "errors"
+    "fmt"
+    "io"
+    "strings"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func CompareSorted(fileA, fileB string, usingFields, sep string, sortAsc bool, onlyA, onlyB, both io.Writer) (err error) {
+/*         Purpose : Routes the records of two sorted files to those only in the first, only in the second, or in both.
+ *       Arguments : fileA       = path of the first file, sorted by the package on usingFields.
+ *                   fileB       = path of the second file, sorted by the package on usingFields.
+ *                   usingFields = CSV of field numbers used as indexes when sorting, ordered as primary, secondary, etc.,
+ *                                 with the first field referenced as 1.
+ *                   sep         = the field separator of both files.
+ *                   sortAsc     = boolean flag indicating whether the files were sorted in ascending order.
+ *                   onlyA       = destination of the records of fileA without a match in fileB, nil to discard them.
+ *                   onlyB       = destination of the records of fileB without a match in fileA, nil to discard them.
+ *                   both        = destination of the records of fileA matched in fileB, nil to discard them.
+ *         Returns : Any error encountered, including finding that an input is not sorted.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : compareKeys, newSortedReader, parseColumns
+ *         Remarks : The records are compared on their index fields only, as Sort does, i.e. on values right-aligned to a
+ *                   common width; giving all the fields as usingFields compares whole records as comm does. The files
+ *                   are compared as multisets: a key held m times by fileA and n times by fileB yields min(m, n) records
+ *                   in both, those of fileA in their order, and the |m - n| extra records of the file holding more, its
+ *                   last ones, in onlyA or onlyB. Both files are read once, in step, and the order of each is checked as
+ *                   it is read. Blank records are skipped and every record is written with a terminator.
+ *         History : v1.64.0 - October 15, 2026 - Original release.
+ */
+    if fileA == "" || fileB == "" { return errors.New("mergesort: the input files were not specified") }
+    if usingFields == "" {
+        return &kindError{kind:ErrBadFieldSpec, err:errors.New("mergesort: the index fields columns were not specified")}
+    }
+    colIdxs, err := parseColumns(usingFields)
+    if err != nil { return fmt.Errorf("mergesort: %w", err) }
+
+    a, err := newSortedReader(fileA, colIdxs, sep, sortAsc)
+    if err != nil { return err }
+    defer a.close()
+    b, err := newSortedReader(fileB, colIdxs, sep, sortAsc)
+    if err != nil { return err }
+    defer b.close()
+    writers  := []*bufio.Writer{}
+    buffered := func(w io.Writer) *bufio.Writer {                 //buffers a destination, discarding if nil
+        if w == nil { w = io.Discard }
+        writers = append(writers, bufio.NewWriter(w))
+        return writers[len(writers) - 1]
+    }
+    outA, outB, outBoth := buffered(onlyA), buffered(onlyB), buffered(both)
+    emit := func(w *bufio.Writer, record string) {
+        if !strings.HasSuffix(record, "\n") { record += "\n" }    //file's last record lacking its terminator
+        if err == nil { _, err = w.WriteString(record) }
+    }
+    //Walk both files in key order
+    for a.err == nil && b.err == nil && err == nil && (!a.eof || !b.eof) {
+        var cmp int
+        switch {
+            case a.eof: cmp = 1
+            case b.eof: cmp = -1
+            default:
+                cmp = compareKeys(a.key, b.key)
+                if !sortAsc { cmp = -cmp }
+        }
+        switch {
+            case cmp < 0:
+                emit(outA, a.record)
+                a.advance()
+            case cmp > 0:
+                emit(outB, b.record)
+                b.advance()
+            default:                                              //a match: pair the two records
+                emit(outBoth, a.record)
+                a.advance()
+                b.advance()
+        }
+    }
+    if a.err != nil { return a.err }
+    if b.err != nil { return b.err }
+    if err   != nil { return }
+    for _, w := range writers {
+        if err = w.Flush(); err != nil { return }
+    }
+    return
+} //end func CompareSorted
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file compare.go
@@ -0,0 +1,96 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     continuation.go
+ * Overview:
+ *     grouping of continuation lines, such as those of stack traces or wrapped log entries, with the line they follow into
+ *     a single logical record, keyed by its first line and output whole.
+ * Functions:
+ *     WithContinuationLines(startsRecord *regexp.Regexp, maxGroupLen int) Option
+ *         Option joining the continuation lines of the input to the record they follow.
+ * History:
+ *     v1.65.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "bufio"
+    "bytes"
+    "fmt"
+    "io"
+    "regexp"
+    "strings"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func WithContinuationLines(startsRecord *regexp.Regexp, maxGroupLen int) Option {
+/*         Purpose : Joins the continuation lines of the input to the record they follow.
+ *       Arguments : startsRecord = pattern of the lines starting a record, e.g. `^\d{4}-\d\d-\d\d ` for timestamped
+ *                                  entries, every other line continuing the record before it. If nil, the lines starting
+ *                                  with a space or a tab continue the record before them.
+ *                   maxGroupLen  = the longest record in bytes, continuation lines included, 0 for 16MB. A longer one
+ *                                  fails the sort with a RecordError at its first line.
+ *         Returns : The option.
+ * Externals -  In : _defaultGroupLen
+ * Externals - Out : None.
+ *       Functions : None.
+ *         Remarks : A record then spans its first line and all the continuation lines following it. Its index fields, and
+ *                   whatever else is judged on a record's fields such as the key filters, are taken from its first
+ *                   line, while the whole byte range of its lines is copied to the output, in their input order. Lines
+ *                   are matched on their first 4KB, the pattern being unanchored unless it starts with "^". Leading
+ *                   continuation lines, preceding the first line that starts a record, form a record of their own keyed
+ *                   by the first of them, so that no line is lost. The line numbers of WithLineRange and
+ *                   WithOriginalLineNumbers, and the record counts, are those of the records rather than of the lines.
+ *                   The byte ranges of GenerateRuns and the sampled blocks of WithSampledWidths are resynchronized past
+ *                   any continuation lines, which belong to the record before them.
+ *         History : v1.65.0 - October 15, 2026 - Original release.
+ */
+    return func(s *Sorter) { s.grouped, s.startsRecord, s.maxGroupLen = true, startsRecord, maxGroupLen }
+} //end func WithContinuationLines
+//Private ----------------------------------------------------------------------------------------------------------------------
+const _defaultGroupLen = 16 << 20 //longest record with its continuation lines, unless set
+func (s *Sorter) readRecord(reader *bufio.Reader) (string, error) {
+    //Reads the next record, followed by its continuation lines if grouped
+    s.checkDone()
+    record, err := readString(reader)
+    if !s.grouped || err == io.EOF || !s.continues(reader) { return record, err }
+    maxLen := s.maxGroupLen
+    if maxLen == 0 { maxLen = _defaultGroupLen }
+    var group strings.Builder
+    group.WriteString(record)
+    for err == nil && s.continues(reader) {
+        record, err = readString(reader)
+        group.WriteString(record)
+        if group.Len() > maxLen {
+            halt(fmt.Sprintf("the record exceeds %d bytes with its continuation lines", maxLen))
+        }
+    }
+    return group.String(), err
+} //end func readRecord
+func (s *Sorter) skipContinuations(reader *bufio.Reader) string {
+    //Reads the continuation lines that begin a reader positioned past the start of the input, returning them
+    var skipped strings.Builder
+    for s.grouped && s.continues(reader) {
+        line, _ := readString(reader)
+        skipped.WriteString(line)
+    }
+    return skipped.String()
+} //end func skipContinuations
+func (s *Sorter) continues(reader *bufio.Reader) bool {
+    //Returns whether the next line of a reader continues the current record, judged on its first bytes
+    head, _ := reader.Peek(1)
+    if len(head) == 0 { return false }
+    if s.startsRecord == nil { return head[0] == ' ' || head[0] == '\t' }
+    head, _ = reader.Peek(reader.Buffered())
+    if bytes.IndexByte(head, '\n') < 0 { head, _ = reader.Peek(reader.Size()) } //line straddling the buffered bytes
+    if k := bytes.IndexByte(head, '\n'); k >= 0 { head = head[:k] }
+    return !s.startsRecord.Match(head)
+} //end func continues
+func (s *Sorter) firstLine(record string) string {
+    //Returns the first line of a record, with its terminator, if grouped, or the record otherwise
+    if !s.grouped { return record }
+    if k := strings.IndexByte(record, '\n'); k >= 0 { return record[:k + 1] }
+    return record
+} //end func firstLine
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file continuation.go
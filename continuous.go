@@ -0,0 +1,259 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     continuous.go
+ * Overview:
+ *     continuous merging of the key files, each merge taking the smallest files available as soon as a merging goroutine
+ *     is free, without the barriers between passes, and a cap on the key files present while the runs are written.
+ * Functions:
+ *     WithContinuousMerge() Option
+ *         Option merging the key files continuously rather than in passes.
+ *     WithMaxLiveRuns(n int) Option
+ *         Option capping the number of key files present while the runs are written.
+ * History:
+ *     v1.86.0 - October 15, 2026 - Original release.
+ *     v1.87.0 - October 15, 2026 - The merges after the runs make the later ones full.
+ *     v1.88.0 - October 15, 2026 - Added WithMaxLiveRuns.
+ *     v1.89.0 - October 15, 2026 - The runs take the sizes of WithAdaptiveRunSize.
+ *     v1.91.0 - October 15, 2026 - The runs may be packed by WithPackedRuns.
+ *     v1.93.0 - October 15, 2026 - The runs may be flushed early by WithMemoryPressure.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "errors"
+    "fmt"
+    "sync"
+    "sync/atomic"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func WithContinuousMerge() Option {
+/*         Purpose : Merges the key files continuously rather than in passes.
+ *       Arguments : None.
+ *         Returns : The option.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : None.
+ *         Remarks : WithParallelism goroutines merge the key files, each taking the smallest files available, by number of
+ *                   keys, as soon as it is free, so that the output of a merge becomes a candidate for the next ones at once
+ *                   rather than at the end of its pass: no goroutine waits for a straggler, the merges of the runs overlap
+ *                   their creation as before, and the files merged together are of similar sizes. While runs are still being
+ *                   created, a merge waits for a full fan-in of files merged as many times, as a pass would merge them, so
+ *                   that a large file is not merged anew with each few runs; once they are all written, the goroutines go on
+ *                   merging the smallest files down to a single one, the next merge taking just enough files for the later
+ *                   ones to be full. The output is the same as that of the passes, every key being unique by its offset. The
+ *                   merges are reported as a single pass, in SortStats.MergePasses, SortStats.Phases and MergeProgress,
+ *                   whose tasks are unknown, and Estimate plans them as they would run in a single goroutine.
+ *                   WithDeterministic, which merges in a fixed order, takes precedence.
+ *         History : v1.86.0 - October 15, 2026 - Original release.
+ *                   v1.87.0 - October 15, 2026 - The merges after the runs make the later ones full.
+ *                   v1.88.0 - October 15, 2026 - Estimate plans the continuous merges.
+ */
+    return func(s *Sorter) { s.continuous = true }
+} //end func WithContinuousMerge
+func WithMaxLiveRuns(n int) Option {
+/*         Purpose : Caps the number of key files present while the runs of a sort are written.
+ *       Arguments : n = the cap, at least 2, or 0 for none, the default.
+ *         Returns : The option.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : None.
+ *         Remarks : The key files are merged by the pool of WithContinuousMerge, with a single goroutine unless that option
+ *                   is given too. Before each run is written, the sort waits until fewer than n key files are present, the
+ *                   merging goroutines merging the smallest files, merged as many times if at least two are, without waiting
+ *                   for a full fan-in once n are present. At most n key files are thus present once a run is written,
+ *                   besides the output of each merge under way, e.g. for a scratch volume or a file system short of inodes
+ *                   or entries, and SortStats.PeakTempFiles reports the bound reached. The cap bounds the files, not their
+ *                   bytes: the keys written are merged, not removed, and the last merge holds them all twice whatever the
+ *                   cap, so that SortStats.PeakTempBytes stays about twice their bytes, while the smaller merges rewrite the
+ *                   keys more times, e.g. 1.5 times as many bytes with n = 4 and a fan-in of 4, and the keying waits for
+ *                   them. Estimate plans the same merges, as does WithMaxTempSpace. With WithDeterministic, the merges run
+ *                   at once in the goroutine writing the runs, in a fixed order.
+ *         History : v1.88.0 - October 15, 2026 - Original release.
+ */
+    return func(s *Sorter) { s.maxLiveRuns = n }
+} //end func WithMaxLiveRuns
+//Private ----------------------------------------------------------------------------------------------------------------------
+var errPoolAborted = errors.New("merges aborted") //merges stopped by a failure of the sort
+type mergePool struct {
+    run       *sortRun
+    mutex     sync.Mutex
+    changed   *sync.Cond                                          //signalled as files are added & merges end
+    queue     mergeQueue                                          //files available for merging
+    merging   int                                                 //merges under way
+    present   int                                                 //key files present, those merged & written included
+    limit     int                                                 //cap on the key files present before a run, 0 for none
+    inline    bool                                                //merges run by the goroutine adding the files
+    closed    bool                                                //all the runs added
+    err       error                                               //first error of a merge, or errPoolAborted
+    passTimed func()                                              //records the merges as a pass, once the first starts
+    workers   sync.WaitGroup
+}
+func (r *sortRun) mergeContinuously(produce func(emit func(key string))) string {
+    //Sorts the keys emitted by produce in runs of runSize, merged continuously by a pool of goroutines, or at once if
+    //deterministic, and returns the file holding all the keys in order
+    var keys []string                                             //keys of the current run
+
+    r.progress = &mergeTracker{fn:r.onMerge, spoke:&r.counters.spoke}
+    pool      := &mergePool{run:r, limit:r.maxLiveRuns}
+    switch {
+        case r.deterministic: pool.start(0)
+        case r.continuous:    pool.start(r.parallelism)
+        default:              pool.start(1)
+    }
+    defer func() {
+        if p := recover(); p != nil {                             //on failure, stop the merges & remove the key files
+            pool.abort()
+            r.abandonPack()
+            files, _ := r.storage.List(r.prefix)
+            for _, v := range files { r.removeTemp(v) }
+            panic(p)
+        }
+    }()
+    writeRun := func() {
+        if err := pool.makeRoom(); err != nil { panic(haltError{err}) }
+        for _, name := range r.flushRun(keys) { pool.add(name, r.packedKeys(name, len(keys))) }
+        r.resizeRun(keys)
+        keys = nil
+    }
+    produce(func(key string) {
+        keys = append(keys, key)
+        if len(keys) == r.runSize || r.underPressure(len(keys)) { writeRun() }
+    })
+    if len(keys) > 0 { writeRun() }
+    for _, name := range r.closePack() { pool.add(name, r.packedKeys(name, 0)) }
+    sorted, err := pool.finish()
+    if err != nil { panic(haltError{err}) }
+    if sorted == "" { return r.emptyKeyFile() }                   //no keys
+    return sorted
+} //end func mergeContinuously
+func (p *mergePool) start(workers int) {
+    //Starts the goroutines of the pool merging its files, none if the merges are to run inline
+    p.changed = sync.NewCond(&p.mutex)
+    p.inline  = workers == 0
+    for k := 0; k < workers; k++ {
+        p.workers.Add(1)
+        go p.work()
+    }
+} //end func start
+func (p *mergePool) makeRoom() error {
+    //Waits until fewer key files than the cap are present, merging them if inline, and returns the first error of a merge
+    p.mutex.Lock()
+    defer p.mutex.Unlock()
+    if p.limit > 0 && p.present >= p.limit && p.run.verbose {
+        fmt.Printf("func Sort - %d key files present, waiting for the merges\n", p.present)
+    }
+    if p.inline { p.drain() }
+    for p.err == nil && p.limit > 0 && p.present >= p.limit { p.changed.Wait() }
+    return p.err
+} //end func makeRoom
+func (p *mergePool) add(name string, keys int64) {
+    //Makes a key file available for merging, merging at once if inline
+    p.mutex.Lock()
+    p.queue.add(name, keys)
+    p.present++
+    p.changed.Broadcast()
+    if p.inline { p.drain() }
+    p.mutex.Unlock()
+} //end func add
+func (p *mergePool) finish() (string, error) {
+    //Waits for the files to be merged down to one, returning its name, "" if no file was added, or the first error
+    p.mutex.Lock()
+    p.closed = true
+    p.changed.Broadcast()
+    if p.inline { p.drain() }
+    for p.err == nil && (p.merging > 0 || p.queue.Len() > 1) { p.changed.Wait() }
+    p.mutex.Unlock()
+    p.workers.Wait()
+    if p.passTimed != nil { p.passTimed() }
+    if p.err != nil || p.queue.Len() == 0 { return "", p.err }
+    if p.run.verbose { fmt.Println("\tfunc merge - all tasks done") }
+    return p.queue.files[0].name, nil
+} //end func finish
+func (p *mergePool) abort() {
+    //Stops the merges after those under way, the files being left in place
+    p.mutex.Lock()
+    if p.err == nil { p.err = errPoolAborted }
+    p.changed.Broadcast()
+    p.mutex.Unlock()
+    p.workers.Wait()
+} //end func abort
+func (p *mergePool) work() {
+    //Merges the smallest files available whenever enough of them are, until the files are merged down to one
+    defer p.workers.Done()
+    if p.run.lowPriority { lowerThreadPriority() }
+    p.mutex.Lock()
+    defer p.mutex.Unlock()
+    for {
+        group, names := p.take()
+        for group == nil {
+            if p.err != nil || p.closed && p.merging == 0 && p.queue.Len() <= 1 {
+                p.changed.Broadcast()
+                return
+            }
+            p.changed.Wait()
+            group, names = p.take()
+        }
+        p.merge(group, names)
+    }
+} //end func work
+func (p *mergePool) drain() {
+    //Runs the merges due, inline; to be called with the mutex held
+    for group, names := p.take(); group != nil; group, names = p.take() { p.merge(group, names) }
+} //end func drain
+func (p *mergePool) merge(group []pooledFile, names []string) {
+    //Merges files taken from the pool and adds the merged file to it; to be called with the mutex held, which is released
+    //during the merge
+    r := p.run
+    if p.passTimed == nil {                                       //first merge: the pass of all the merges begins
+        atomic.AddInt64(&r.counters.passes, 1)
+        p.passTimed = r.timePass()
+        r.progress.beginOpen()
+    }
+    p.merging++
+    p.present++
+    p.mutex.Unlock()
+    merged, err := r.mergeFiles(names)
+    p.mutex.Lock()
+    p.merging--
+    if err != nil {
+        if p.err == nil { p.err = err }
+    } else {
+        p.present -= len(group)
+        p.queue.addMerged(merged, group)
+    }
+    p.changed.Broadcast()
+} //end func merge
+func (p *mergePool) take() (group []pooledFile, names []string) {
+    //Removes the smallest files available for a merge, returning them & their names: while runs are still being added, a
+    //full fan-in of them on the same level, as a pass would merge, or at least two on the lowest level once the cap on the
+    //files present is reached, then just enough for the later merges to be full ones; nil if too few are available, e.g.
+    //until the merges under way end, or the merges are stopped. To be called with the mutex held.
+    fanIn   := p.run.mergeFanIn()
+    files   := p.queue.Len()
+    crowded := p.limit > 0 && !p.closed && p.present >= p.limit
+    if p.err != nil || files < 2 || !p.closed && !crowded && files < fanIn { return nil, nil }
+    if p.closed {
+        if fanIn = nextFanIn(files + p.merging, fanIn); fanIn > files { return nil, nil }
+    } else if fanIn > files {
+        fanIn = files
+    }
+    group, names, _ = p.queue.take(fanIn)
+    if !p.closed {
+        level := 1                                                //files on the level of the smallest
+        for level < len(group) && group[level].level == group[0].level { level++ }
+        switch {
+            case level == len(group):
+            case crowded && level >= 2:                           //the lowest level merged, the others put back
+                p.queue.putBack(group[level:])
+                group, names = group[:level], names[:level]
+            case !crowded:                                        //a merged file among the smallest: more runs needed
+                p.queue.putBack(group)
+                return nil, nil
+        }
+    }
+    return
+} //end func take
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file continuous.go
@@ -0,0 +1,87 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     continuous_test.go
+ * Overview:
+ *     tests and benchmarks of the continuous merging of the key files against the passes, on runs of uneven size.
+ * Functions:
+ *     TestContinuousMatchesPasses(t *testing.T)
+ *         Checks that continuous merges output the same bytes as the passes.
+ *     BenchmarkContinuousMerge(b *testing.B)
+ *         Times the passes and the continuous merges, reporting the time spent merging once the runs are written.
+ * History:
+ *     v1.86.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "bytes"
+    "fmt"
+    "math/rand"
+    "path/filepath"
+    "testing"
+    "time"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func TestContinuousMatchesPasses(t *testing.T) {
+    input := randomInput(rand.New(rand.NewSource(6)), 20000)
+    for _, parallelism := range []int{1, 4} {
+        for _, fanIn := range []int{2, 4} {
+            for _, sortAsc := range []bool{true, false} {
+                name := fmt.Sprintf("parallelism=%d/fanIn=%d/asc=%v", parallelism, fanIn, sortAsc)
+                opts := append(unevenRuns(100), WithAscending(sortAsc), WithParallelism(parallelism), WithMergeFanIn(fanIn))
+                var stats SortStats
+                passes     := sortBytes(t, input, opts...)
+                continuous := sortBytes(t, input, append(opts, WithContinuousMerge(), WithStats(&stats))...)
+                if !bytes.Equal(passes, continuous) {
+                    t.Fatalf("%s: the outputs of the passes and of the continuous merges differ", name)
+                }
+                if sizes := stats.RunSizes; len(sizes) < 3 || sizes[0] == sizes[1] {
+                    t.Fatalf("%s: runs of %v keys, expected several of uneven size", name, sizes)
+                }
+            }
+        }
+    }
+    //The same merges capped by the key files present
+    passes := sortBytes(t, input, unevenRuns(100)...)
+    if capped := sortBytes(t, input, append(unevenRuns(100), WithMaxLiveRuns(3))...); !bytes.Equal(passes, capped) {
+        t.Fatal("the outputs of the passes and the merges capped by WithMaxLiveRuns differ")
+    }
+} //end func TestContinuousMatchesPasses
+func BenchmarkContinuousMerge(b *testing.B) {
+    //Sub-benchmarks named e.g. "scheduler=continuous", reporting the milliseconds of the sort left once the width scan, the
+    //key generation and the output are taken out, i.e. spent merging after the last run is written
+    inFile  := writeInput(b, randomInput(rand.New(rand.NewSource(7)), 300000))
+    outFile := filepath.Join(filepath.Dir(inFile), "out.txt")
+    for _, continuous := range []bool{false, true} {
+        scheduler := "passes"
+        opts      := append(unevenRuns(2000), WithParallelism(4), WithMergeFanIn(4), WithTempDir(b.TempDir()))
+        if continuous { scheduler, opts = "continuous", append(opts, WithContinuousMerge()) }
+        b.Run("scheduler=" + scheduler, func(b *testing.B) {
+            var stats SortStats
+            sorter, err := NewSorter(append(opts, WithStats(&stats))...)
+            if err != nil { b.Fatal(err) }
+            var tail time.Duration
+            b.ResetTimer()
+            for k := 0; k < b.N; k++ {
+                start := time.Now()
+                if err := sorter.Run(inFile, outFile); err != nil { b.Fatal(err) }
+                tail += time.Since(start)
+                for _, phase := range stats.Phases {
+                    switch phase.Name {
+                        case "width scan", "key generation", "output": tail -= phase.Duration
+                    }
+                }
+            }
+            b.ReportMetric(float64(tail.Milliseconds()) / float64(b.N), "tail-ms/op")
+        })
+    }
+} //end func BenchmarkContinuousMerge
+//Private ----------------------------------------------------------------------------------------------------------------------
+func unevenRuns(keysPerSort int) []Option {
+    //Returns the options of a sort whose runs double in size from keysPerSort keys, as WithAdaptiveRunSize grows them
+    return []Option{WithFields("2,1"), WithKeysPerSort(keysPerSort), WithAdaptiveRunSize(1 << 30)}
+} //end func unevenRuns
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file continuous_test.go
@@ -0,0 +1,111 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     dedup.go
+ * Overview:
+ *     deduplication of the output, a single record of each group of equal index fields being kept and the others
+ *     suppressed, optionally to a duplicates file so that none vanishes unaccounted for.
+ * Functions:
+ *     WithDedup(policy DedupPolicy) Option
+ *         Option keeping a single record of each group of equal index fields.
+ *     WithDuplicatesFile(path string) Option
+ *         Option writing the records suppressed by WithDedup to a file.
+ * Types:
+ *     DedupPolicy
+ *         Record kept of each group of equal index fields.
+ * History:
+ *     v1.97.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "bufio"
+    "os"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+type DedupPolicy int
+const(
+    KeepAll   DedupPolicy = iota //every record output, the default
+    KeepFirst                    //first record of each group in input order
+    KeepLast                     //last record of each group in input order
+)
+
+func WithDedup(policy DedupPolicy) Option {
+/*         Purpose : Keeps a single record of each group of equal index fields.
+ *       Arguments : policy = the record kept, KeepFirst or KeepLast, or KeepAll to keep every one, the default.
+ *         Returns : The option.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : None.
+ *         Remarks : The records of a group, adjacent once sorted, are output in input order, so that KeepFirst keeps the
+ *                   record nearest the start of the input and KeepLast the one nearest its end, in both sort directions.
+ *                   The others are suppressed in the output phase, counted by SortStats.SuppressedRecords rather than by
+ *                   its OutputRecords, and written to the file of WithDuplicatesFile if any. The groups are those the
+ *                   sort compares, i.e. of equal index parts of the composite keys, the record offsets being disregarded.
+ *                   WithVerifyOutput counts the suppressed records as output. The ranks and line numbers annotate the
+ *                   kept records only, the percentiles remaining relative to all the records sorted. SortAndReduce, whose
+ *                   reducer sees the groups whole, ignores the option.
+ *         History : v1.97.0 - October 15, 2026 - Original release.
+ */
+    return func(s *Sorter) { s.dedupPolicy = policy }
+} //end func WithDedup
+func WithDuplicatesFile(path string) Option {
+/*         Purpose : Writes the records suppressed by WithDedup to a file.
+ *       Arguments : path = path of the duplicates file, created or truncated by each sort. "" for none, the default.
+ *         Returns : The option.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : None.
+ *         Remarks : The kept record of each group goes to the output and all the other records of the group to the
+ *                   duplicates file, in their input order, the groups following one another in output order. Each record
+ *                   is written verbatim, without the annotations of the output, and followed by a line feed if it lacks
+ *                   one, so that the output and the duplicates file together hold every record sorted. The option requires
+ *                   a policy of WithDedup other than KeepAll. A sorter sorting several inputs at once, e.g. through
+ *                   SortGlob, must be given a duplicates file per input, as with WithRejectFile.
+ *         History : v1.97.0 - October 15, 2026 - Original release.
+ */
+    return func(s *Sorter) { s.dupsPath = path }
+} //end func WithDuplicatesFile
+//Private ----------------------------------------------------------------------------------------------------------------------
+type dedupState struct {
+    groupStart bool                                               //next record the first of its group
+    digest     uint64                                             //sum of the digests of the suppressed records
+    file       *rejectFile                                        //duplicates file, once open
+}
+func (r *sortRun) openDuplicates() func() {
+    //Creates the run's duplicates file, if requested, and returns the function closing it
+    r.dedup = dedupState{groupStart:true}
+    if r.dupsPath == "" || r.dedupPolicy == KeepAll { return func() {} }
+    fh, err := os.Create(r.dupsPath)
+    if err != nil { halt("the duplicates file cannot be created - " + err.Error()) }
+    r.dedup.file = &rejectFile{fh:fh, writer:bufio.NewWriter(fh)}
+    return func() {
+        file        := r.dedup.file
+        r.dedup.file = nil
+        err         := file.writer.Flush()
+        if err == nil { err = file.fh.Sync() }
+        if errClose := file.fh.Close(); err == nil { err = errClose }
+        if err != nil { halt("the duplicates file cannot be written - " + err.Error()) }
+    }
+} //end func openDuplicates
+func (r *sortRun) keeps(record string, lastInGroup bool) bool {
+    //Returns whether an output record, terminated, is kept per the policy of WithDedup, else counts it as suppressed and
+    //writes it to the duplicates file if any
+    first := r.dedup.groupStart
+    r.dedup.groupStart = lastInGroup
+    switch r.dedupPolicy {
+        case KeepFirst: if first       { return true }
+        case KeepLast:  if lastInGroup { return true }
+        default:        return true
+    }
+    r.stats.SuppressedRecords++
+    if r.verify { r.dedup.digest += recordDigest(record) }
+    if r.dedup.file == nil { return false }
+    if _, err := r.dedup.file.writer.WriteString(record); err != nil {
+        halt("the duplicates file cannot be written - " + err.Error())
+    }
+    return false
+} //end func keeps
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file dedup.go
@@ -0,0 +1,63 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     dedup_test.go
+ * Overview:
+ *     tests of the deduplication of the output and of the duplicates file receiving the suppressed records.
+ * Functions:
+ *     TestDedup(t *testing.T)
+ *         Checks the record kept of each group per KeepFirst and KeepLast, in either direction, and the duplicates file.
+ * History:
+ *     v1.97.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "fmt"
+    "io/ioutil"
+    "path/filepath"
+    "testing"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func TestDedup(t *testing.T) {
+    //Groups "a" and "b" of 2 and 3 records, the last lacking its terminator, and "c" of one
+    const input = "b\t1\n" + "a\t1\n" + "b\t2\n" + "c\t1\n" + "a\t2\n" + "b\t3"
+    cases := []struct {
+        policy           DedupPolicy
+        sortAsc          bool
+        output, dups     string
+    }{
+        {KeepFirst, true, "a\t1\nb\t1\nc\t1\n", "a\t2\nb\t2\nb\t3\n"},
+        {KeepLast, true, "a\t2\nb\t3\nc\t1\n", "a\t1\nb\t1\nb\t2\n"},
+        {KeepFirst, false, "c\t1\nb\t1\na\t1\n", "b\t2\nb\t3\na\t2\n"},
+        {KeepLast, false, "c\t1\nb\t3\na\t2\n", "b\t1\nb\t2\na\t1\n"},
+    }
+    for _, test := range cases {
+        for _, keysPerSort := range []int{2, 100} {                 //merged runs, or a single one
+            name     := fmt.Sprintf("policy %d, asc %v, keysPerSort %d", test.policy, test.sortAsc, keysPerSort)
+            dupsFile := filepath.Join(t.TempDir(), "dups.txt")
+            var stats SortStats
+            output   := sortBytes(t, input, WithFields("1"), WithSeparator("\t"), WithAscending(test.sortAsc),
+                                  WithKeysPerSort(keysPerSort), WithDedup(test.policy), WithDuplicatesFile(dupsFile),
+                                  WithStats(&stats))
+            if string(output) != test.output { t.Errorf("%s: output %q, expected %q", name, output, test.output) }
+            dups, err := ioutil.ReadFile(dupsFile)
+            if err != nil { t.Fatal(err) }
+            if string(dups) != test.dups { t.Errorf("%s: duplicates %q, expected %q", name, dups, test.dups) }
+            if stats.OutputRecords != 3 || stats.SuppressedRecords != 3 {
+                t.Errorf("%s: %d records output and %d suppressed, expected 3 and 3", name, stats.OutputRecords,
+                         stats.SuppressedRecords)
+            }
+        }
+    }
+    //Every record kept by default, and a duplicates file refused without a policy
+    if output := sortBytes(t, input, WithFields("1"), WithSeparator("\t")); len(output) != len(input) + 1 {
+        t.Errorf("output %q, expected every record", output)
+    }
+    if _, err := NewSorter(WithFields("1"), WithDuplicatesFile(filepath.Join(t.TempDir(), "dups.txt"))); err == nil {
+        t.Error("duplicates file accepted without a policy of WithDedup")
+    }
+} //end func TestDedup
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file dedup_test.go
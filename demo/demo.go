@@ -47,5 +47,5 @@ func main() {
     if err := fh.Sync();  err != nil { panic(err) }
     if err := fh.Close(); err != nil { panic(err) }
     //Sort the data with the last field as the primary key and the first as a secondary key
-    mergesort.Sort(inFile, outFile, sortAsc, usingFields, sep, keysPerSort, verbose)
+    if err := mergesort.Sort(inFile, outFile, sortAsc, usingFields, sep, keysPerSort, verbose); err != nil { panic(err) }
 }
@@ -0,0 +1,58 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     derived.go
+ * Overview:
+ *     sort key computed from the fields of each record, e.g. a product or a difference of numeric fields, rather than read
+ *     from a single field.
+ * Functions:
+ *     WithDerivedKey(fn func(fields []string) (float64, error)) Option
+ *         Option sorting on a number computed from the fields of each record, the index fields breaking its ties.
+ * History:
+ *     v1.70.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "errors"
+    "fmt"
+    "math"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func WithDerivedKey(fn func(fields []string) (float64, error)) Option {
+/*         Purpose : Sorts on a number computed from the fields of each record, the index fields breaking its ties.
+ *       Arguments : fn = function computing the key of a record from all its fields, e.g. price * quantity or end - start.
+ *                        nil for none, the default.
+ *         Returns : The option.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : None.
+ *         Remarks : The number enters the composite key ahead of the index fields, encoded as a numeric field is, so that
+ *                   the records are ordered on it first and on the index fields of WithFields next, which then become
+ *                   optional. Being of a fixed width, it is left out of the scan of the field widths. fn is given the fields
+ *                   of the record once trimmed and split, as the index fields are, and is called from a single goroutine per
+ *                   sort. A record for which fn returns an error or NaN is malformed: it fails the sort, located, unless
+ *                   skipped per WithRecordErrorHandler, WithMaxErrors or WithMaxErrorRate, in the category "derived value".
+ *                   The master records of MergeInto and the records pushed into a SpillQueue are not screened, so that fn
+ *                   failing on one of them fails the merge or the queue. Records with equal keys keep their input order.
+ *         History : v1.70.0 - October 15, 2026 - Original release.
+ */
+    return func(s *Sorter) { s.derive = fn }
+} //end func WithDerivedKey
+//Private ----------------------------------------------------------------------------------------------------------------------
+type keyDeriver func(fields []string) (float64, error)
+func derivedPart(spec keyParams, fields []string) string {
+    //Returns the part of the composite key computed from a record's fields, halting on a malformed record
+    number, err := spec.DERIVE(fields)
+    if err == nil && math.IsNaN(number) { err = errors.New("the result is NaN") }
+    if err != nil {
+        panic(haltError{&malformedError{category:"derived value",
+                                        err:fmt.Errorf("mergesort: the derived key cannot be computed - %w", err)}})
+    }
+    value := encodeFloat(number)
+    if spec.HASHED { return hashDigest(spec.SEED, value) + value }
+    return value
+} //end func derivedPart
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file derived.go
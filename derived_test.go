@@ -0,0 +1,80 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     derived_test.go
+ * Overview:
+ *     tests of the sorts on a key derived from the fields of each record.
+ * Functions:
+ *     TestDerivedKeyOrder(t *testing.T)
+ *         Checks the order of negative, zero, fractional and infinite keys, their ties broken by the index fields.
+ *     TestDerivedKeyErrors(t *testing.T)
+ *         Checks that the records whose key cannot be computed, or is NaN, fail the sort or go to the record handler.
+ * History:
+ *     v1.70.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "errors"
+    "path/filepath"
+    "strconv"
+    "strings"
+    "testing"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func TestDerivedKeyOrder(t *testing.T) {
+    //Keys of the second field, the ties of "b" broken by the first field in either direction
+    input  := "a\t3\n" + "z\t-1\n" + "b\t0.25\n" + "c\t+Inf\n" + "y\t-2.5\n" + "x\t0\n" + "w\t0.001\n" + "d\t-Inf\n" +
+              "b\t0.25\n" + "a\t0.25\n" + "v\t-0.5\n"
+    cases  := []struct {
+        usingFields string
+        sortAsc     bool
+        expected    string
+    }{
+        {"", true, "d\t-Inf\n" + "y\t-2.5\n" + "z\t-1\n" + "v\t-0.5\n" + "x\t0\n" + "w\t0.001\n" + "b\t0.25\n" + "b\t0.25\n" +
+                   "a\t0.25\n" + "a\t3\n" + "c\t+Inf\n"},
+        {"1", true, "d\t-Inf\n" + "y\t-2.5\n" + "z\t-1\n" + "v\t-0.5\n" + "x\t0\n" + "w\t0.001\n" + "a\t0.25\n" + "b\t0.25\n" +
+                    "b\t0.25\n" + "a\t3\n" + "c\t+Inf\n"},
+        {"1", false, "c\t+Inf\n" + "a\t3\n" + "b\t0.25\n" + "b\t0.25\n" + "a\t0.25\n" + "w\t0.001\n" + "x\t0\n" + "v\t-0.5\n" +
+                     "z\t-1\n" + "y\t-2.5\n" + "d\t-Inf\n"},
+    }
+    for _, test := range cases {
+        opts := []Option{WithSeparator("\t"), WithAscending(test.sortAsc), WithDerivedKey(secondField)}
+        if test.usingFields != "" { opts = append(opts, WithFields(test.usingFields)) }
+        if output := sortBytes(t, input, opts...); string(output) != test.expected {
+            t.Errorf("fields %q, ascending %v: output %q, expected %q", test.usingFields, test.sortAsc, output,
+                     test.expected)
+        }
+    }
+} //end func TestDerivedKeyOrder
+func TestDerivedKeyErrors(t *testing.T) {
+    //A key that does not parse on line 2 and a NaN on line 4
+    input := "b\t2\n" + "x\toops\n" + "a\t1\n" + "y\tNaN\n"
+    sorter, err := NewSorter(WithSeparator("\t"), WithDerivedKey(secondField), WithTempDir(t.TempDir()))
+    if err != nil { t.Fatal(err) }
+    err = sorter.Run(writeInput(t, input), filepath.Join(t.TempDir(), "out.txt"))
+    var at *RecordError
+    if !errors.Is(err, ErrMalformedRecord) || !errors.As(err, &at) || at.Line != 2 {
+        t.Fatalf("error %v, expected ErrMalformedRecord at line 2", err)
+    }
+    //The same records skipped by the handler
+    var lines []string
+    handler := func(line int64, record string, err error) error {
+        if !errors.Is(err, ErrMalformedRecord) { t.Errorf("line %d: handler given %v, expected ErrMalformedRecord", line, err) }
+        lines = append(lines, strconv.FormatInt(line, 10) + ":" + record)
+        return nil
+    }
+    output := sortBytes(t, input, WithSeparator("\t"), WithDerivedKey(secondField), WithRecordErrorHandler(handler))
+    if string(output) != "a\t1\nb\t2\n" { t.Errorf("output %q, expected the records of keys 1 and 2", output) }
+    if handled := strings.Join(lines, ","); handled != "2:x\toops,4:y\tNaN" {
+        t.Errorf("handler called for %q, expected lines 2 and 4", handled)
+    }
+} //end func TestDerivedKeyErrors
+//Private ----------------------------------------------------------------------------------------------------------------------
+func secondField(fields []string) (float64, error) {
+    //Returns the number held by the second field of a record
+    return strconv.ParseFloat(fields[1], 64)
+} //end func secondField
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file derived_test.go
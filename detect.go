@@ -0,0 +1,93 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     detect.go
+ * Overview:
+ *     detection of the field separator of a text file from the consistency of its field counts.
+ * Functions:
+ *     DetectSeparator(inFile string, candidates []string) (string, error)
+ *         Returns the candidate separator splitting the first records of a file into a consistent number of fields.
+ * History:
+ *     v1.50.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "bufio"
+    "fmt"
+    "io"
+    "os"
+    "strings"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func DetectSeparator(inFile string, candidates []string) (sep string, err error) {
+/*         Purpose : Returns the candidate separator splitting the first records of a file into a consistent number of
+ *                   fields.
+ *       Arguments : inFile     = path of the file.
+ *                   candidates = the separators to consider, in order of preference. If empty, _defaultSeparators.
+ *         Returns : The separator, and any error encountered, matching ErrNoSeparator if no candidate qualifies.
+ * Externals -  In : _defaultSeparators, _detectConsistency, _detectRecords
+ * Externals - Out : None.
+ *       Functions : catch, detectSeparator, halt, haltKind
+ *         Remarks : The first _detectRecords non-blank records are sampled. For each candidate, the usual number of
+ *                   fields is the most frequent one among them; the candidate qualifies if it is at least 2 and at
+ *                   least _detectConsistency of the records have it. The qualifying candidate with the highest share
+ *                   wins, then the one with the most fields, then the first one listed. Separators quoted within
+ *                   fields, as CSV allows, are counted like the others. Sort, Run and the other file sorts detect the
+ *                   separator themselves when it is given as "auto".
+ *         History : v1.50.0 - October 15, 2026 - Original release.
+ */
+    defer catch(&err)
+    if inFile == "" { halt("the input file was not specified") }
+    fh, err := os.Open(inFile)
+    if err != nil { haltKind(ErrInputNotFound, "the input file cannot be located", err) }
+    defer fh.Close()
+    return detectSeparator(bufio.NewReader(fh), candidates), nil
+} //end func DetectSeparator
+//Private ----------------------------------------------------------------------------------------------------------------------
+const(
+    _autoSeparator     = "auto"  //separator requesting its detection
+    _detectConsistency = 0.9     //share of the sampled records that must have the usual number of fields
+    _detectRecords     = 500     //number of records sampled
+)
+var _defaultSeparators = []string{"\t", ",", ";", "|"} //candidates of DetectSeparator if none is given
+func detectSeparator(reader *bufio.Reader, candidates []string) string {
+    //Returns the candidate splitting the first records read into a consistent number of fields
+    if len(candidates) == 0 { candidates = _defaultSeparators }
+    records := []string{}
+    for len(records) < _detectRecords {
+        record, err := readString(reader)
+        if trimmed := strings.Trim(record, " \r\n"); trimmed != "" { records = append(records, trimmed) }
+        if err == io.EOF { break }
+    }
+    if len(records) == 0 { haltKind(ErrEmptyInput, "the input file is empty", nil) }
+    best, bestShare, bestFields := "", 0.0, 0
+    for _, sep := range candidates {
+        if sep == "" { continue }
+        counts := map[int]int{}                                   //number of records by number of fields
+        for _, record := range records { counts[strings.Count(record, sep) + 1]++ }
+        usual, freq := 0, 0
+        for numFields, n := range counts {
+            if n > freq || n == freq && numFields > usual { usual, freq = numFields, n }
+        }
+        share := float64(freq) / float64(len(records))
+        if usual < 2 || share < _detectConsistency { continue }
+        if share > bestShare || share == bestShare && usual > bestFields { best, bestShare, bestFields = sep, share, usual }
+    }
+    if best == "" { haltKind(ErrNoSeparator, "no candidate separator splits the records consistently", nil) }
+    return best
+} //end func detectSeparator
+func (r *sortRun) resolveSeparator(inFile string) {
+    //Replaces the "auto" separator of the run by the one detected in its input, leaving the sorter untouched
+    if r.sep != _autoSeparator { return }
+    fhIn, _ := r.openInput(inFile)
+    defer fhIn.Close()
+    sorter             := *r.Sorter
+    sorter.sep          = detectSeparator(bufio.NewReader(fhIn), nil)
+    r.Sorter            = &sorter
+    r.stats.Separator   = sorter.sep
+    if r.verbose { fmt.Printf("func Sort - detected separator = %q\n", r.sep) }
+} //end func resolveSeparator
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file detect.go
@@ -0,0 +1,83 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     deterministic.go
+ * Overview:
+ *     reproducible execution of a sort, for debugging: sequential temporary file names and merges in a fixed order.
+ * Functions:
+ *     WithDeterministic() Option
+ *         Option making the course of a sort, and not just its output, the same from run to run.
+ * History:
+ *     v1.40.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "fmt"
+    "os"
+    "path/filepath"
+    "strings"
+    "sync/atomic"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func WithDeterministic() Option {
+/*         Purpose : Makes the course of a sort, and not just its output, the same from run to run.
+ *       Arguments : None.
+ *         Returns : The option.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : None.
+ *         Remarks : The temporary files are named after their prefix and a sequence number of the run, e.g.
+ *                   "keys_4242-1_000003", instead of a random suffix. Custom storages, which can only be asked for a
+ *                   prefix, are given the prefix followed by the number. Each merge task runs at once, in the
+ *                   goroutine creating the runs, so that the runs and merges are numbered and carried out in the same
+ *                   order every time. The verbose echo names the temporary files without the process id and run
+ *                   number of their prefix. Together with a seeded WithHashOrder, the echo and the contents of the
+ *                   temporary files are then reproducible. The merges no longer overlap the creation of the runs.
+ *         History : v1.40.0 - October 15, 2026 - Original release.
+ */
+    return func(s *Sorter) { s.deterministic = true }
+} //end func WithDeterministic
+//Private ----------------------------------------------------------------------------------------------------------------------
+const _maxTempSeqTries = 10000 //sequence numbers tried before failing to create a temporary file
+type namedCreator interface {
+    createNamed(name string) (TempFile, error) //creates a new file named exactly name, failing if it exists
+}
+func (r *sortRun) createSequential(prefix string) (fh TempFile, err error) {
+    //Creates a temporary file named after prefix and the next sequence number of the run, skipping the numbers already taken,
+    //e.g. by another run with the same prefix
+    creator, named := r.storage.(namedCreator)
+    for k := 0; k < _maxTempSeqTries; k++ {
+        name := fmt.Sprintf("%s%06d", prefix, atomic.AddUint64(&r.tempSeq, 1))
+        if !named { return r.storage.CreateTemp(name + "_") }
+        if fh, err = creator.createNamed(name); !os.IsExist(err) { return }
+    }
+    return
+} //end func createSequential
+func (r *sortRun) tempLabel(name string) string {
+    //Returns the base name of a temporary file for the verbose echo, without the process id & run number if deterministic
+    base := filepath.Base(name)
+    if !r.deterministic || !strings.HasPrefix(base, "keys_") { return base }
+    if k := strings.Index(base[len("keys_"):], "_"); k >= 0 { return "keys_" + base[len("keys_") + k + 1:] }
+    return base
+} //end func tempLabel
+func (o osStorage) createNamed(name string) (TempFile, error) {
+    if fh, ok, err := o.anon.create(o.dir, name); ok { return fh, err }
+    return os.OpenFile(filepath.Join(o.dir, name), os.O_RDWR | os.O_CREATE | os.O_EXCL, 0600)
+} //end func createNamed
+func (m *MemStorage) createNamed(name string) (TempFile, error) {
+    m.mutex.Lock()
+    defer m.mutex.Unlock()
+    if _, ok := m.files[name]; ok { return nil, &os.PathError{Op:"create", Path:name, Err:os.ErrExist} }
+    data         := &memData{}
+    m.files[name] = data
+    return &memFile{name:name, data:data}, nil
+} //end func createNamed
+func (t *tieredStorage) createNamed(name string) (TempFile, error) {
+    fh, err := t.mem.createNamed(name)
+    if err != nil { return nil, err }
+    return &tieredFile{TempFile:fh, storage:t, name:name, prefix:name + "_", inMem:true}, nil
+} //end func createNamed
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file deterministic.go
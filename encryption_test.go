@@ -0,0 +1,42 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     encryption_test.go
+ * Overview:
+ *     benchmarks of the overhead of the encryption of the temporary files, alone and under a compression codec.
+ * Functions:
+ *     BenchmarkTempEncryption(b *testing.B)
+ *         Times sorts with plain, encrypted, compressed, and compressed then encrypted temporary files.
+ * History:
+ *     v1.76.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "math/rand"
+    "testing"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func BenchmarkTempEncryption(b *testing.B) {
+    //Sub-benchmarks "temp=plain", "temp=encrypted", "temp=gzip" and "temp=gzip+encrypted", over runs of 20000 keys merged in
+    //two passes so that every temporary byte is written and read back several times
+    inFile := writeInput(b, randomInput(rand.New(rand.NewSource(13)), 200000))
+    cases  := []struct {
+        name string
+        opts []Option
+    }{
+        {"plain", nil},
+        {"encrypted", []Option{WithTempEncryption()}},
+        {"gzip", []Option{WithTempCodec(GzipCodec{})}},
+        {"gzip+encrypted", []Option{WithTempCodec(GzipCodec{}), WithTempEncryption()}},
+    }
+    for _, test := range cases {
+        b.Run("temp=" + test.name, func(b *testing.B) {
+            benchSort(b, inFile, append([]Option{WithFields("2,1"), WithKeysPerSort(20000), WithMergeFanIn(4)},
+                                        test.opts...)...)
+        })
+    }
+} //end func BenchmarkTempEncryption
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file encryption_test.go
@@ -0,0 +1,33 @@
+//go:build !plan9
+// +build !plan9
+
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     errno.go
+ * Overview:
+ *     classification of the system errors, on the systems reporting them as errno values.
+ * History:
+ *     v1.33.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "errors"
+    "syscall"
+)
+//Private ----------------------------------------------------------------------------------------------------------------------
+func isTransientErrno(err error) bool {
+    //Returns true if the error is a system error that may not recur
+    var errno syscall.Errno
+    if !errors.As(err, &errno) { return false }
+    return errno == syscall.EIO || errno == syscall.ETIMEDOUT || errno == syscall.EAGAIN || errno == syscall.EINTR
+} //end func isTransientErrno
+func isNoSpace(err error) bool {
+    //Returns true if the error reports a full device or an exceeded quota
+    var errno syscall.Errno
+    return errors.As(err, &errno) && (errno == syscall.ENOSPC || errno == syscall.EDQUOT)
+} //end func isNoSpace
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file errno.go
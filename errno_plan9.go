@@ -0,0 +1,30 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     errno_plan9.go
+ * Overview:
+ *     classification of the system errors on Plan 9, whose errors are strings.
+ * History:
+ *     v1.33.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "errors"
+    "strings"
+    "syscall"
+)
+//Private ----------------------------------------------------------------------------------------------------------------------
+func isTransientErrno(err error) bool {
+    //Returns true if the error is a system error that may not recur
+    var note syscall.ErrorString
+    return errors.As(err, &note) && (strings.Contains(string(note), "i/o error") || note.Timeout() || note.Temporary())
+} //end func isTransientErrno
+func isNoSpace(err error) bool {
+    //Returns true if the error reports a full device
+    var note syscall.ErrorString
+    return errors.As(err, &note) && strings.Contains(string(note), "no space")
+} //end func isNoSpace
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file errno_plan9.go
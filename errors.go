@@ -0,0 +1,106 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     errors.go
+ * Overview:
+ *     sentinel errors classifying the failures of the package, for use with errors.Is, and their location.
+ * Variables:
+ *     ErrInputNotFound, ErrEmptyInput, ErrBadFieldSpec, ErrTempSpace, ErrInterrupted, ErrIndexMismatch, ErrNotPermutation,
+ *     ErrOutputBusy, ErrNoSeparator, ErrRunMismatch, ErrCorruptKeys, ErrCodecMismatch, ErrMalformedRecord,
+ *     ErrTooManyErrors, ErrDuplicateKeys
+ *         Classes of the errors returned by the package.
+ * Types:
+ *     RecordError
+ *         Failure located at an input record or at a composite key of a temporary file.
+ * History:
+ *     v1.33.0 - October 15, 2026 - Original release.
+ *     v1.34.0 - October 15, 2026 - Added RecordError.
+ *     v1.35.0 - October 15, 2026 - Added ErrOutputBusy.
+ *     v1.57.0 - October 15, 2026 - Added ErrRunMismatch.
+ *     v1.58.0 - October 15, 2026 - Added ErrCorruptKeys.
+ *     v1.63.0 - October 15, 2026 - Added ErrCodecMismatch.
+ *     v1.66.0 - October 15, 2026 - Added ErrMalformedRecord.
+ *     v1.67.0 - October 15, 2026 - Added ErrTooManyErrors.
+ *     v1.96.0 - October 15, 2026 - Added ErrDuplicateKeys.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "errors"
+    "fmt"
+    "strconv"
+    "strings"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+var(
+    ErrInputNotFound   = errors.New("mergesort: input not found")             //input file missing or unreadable
+    ErrEmptyInput      = errors.New("mergesort: empty input")                 //input file without any data
+    ErrBadFieldSpec    = errors.New("mergesort: bad field specification")     //index fields missing, malformed or absent
+    ErrTempSpace       = errors.New("mergesort: temporary space exhausted")   //no space left for the temporary files
+    ErrInterrupted     = errors.New("mergesort: interrupted")                 //sort aborted by its context
+    ErrIndexMismatch   = errors.New("mergesort: index does not match input")  //index file invalid, foreign or of other options
+    ErrNotPermutation  = errors.New("mergesort: output not a permutation")    //failed check of WithVerifyOutput
+    ErrOutputBusy      = errors.New("mergesort: output busy")                 //output file locked by another sort
+    ErrNoSeparator     = errors.New("mergesort: separator not detected")      //no consistent separator for "auto"
+    ErrRunMismatch     = errors.New("mergesort: runs do not match input")     //run manifests missing, overlapping or foreign
+    ErrCorruptKeys     = errors.New("mergesort: key file corrupt")            //key file truncated or altered since written
+    ErrCodecMismatch   = errors.New("mergesort: key file codec mismatch")     //key file encoded with another codec or none
+    ErrMalformedRecord = errors.New("mergesort: malformed record")            //record that cannot be keyed
+    ErrTooManyErrors   = errors.New("mergesort: too many malformed records")  //limit of WithMaxErrors or WithMaxErrorRate
+    ErrDuplicateKeys   = errors.New("mergesort: duplicate keys")              //failed check of WithExpectUnique
+)
+type RecordError struct {
+    Line   int    //1-based input line of the record, 0 if unknown
+    Offset int64  //byte offset of the record in the input, -1 if unknown
+    Record string //the record, without its terminator, if read
+    File   string //temporary or index file holding the key, for the failures of the merges and of the output
+    Key    string //the composite key being processed, if any
+    Err    error  //the failure
+}
+
+func (e *RecordError) Error() string {
+    var where []string
+    if e.Line   >  0  { where = append(where, fmt.Sprintf("line %d", e.Line)) }
+    if e.Offset >= 0  { where = append(where, fmt.Sprintf("offset %d", e.Offset)) }
+    if e.File   != "" { where = append(where, "file " + e.File) }
+    if e.Key    != "" { where = append(where, "key " + strconv.Quote(e.Key)) }
+    return fmt.Sprintf("mergesort: at %s: %v", strings.Join(where, ", "), e.Err)
+} //end func Error
+func (e *RecordError) Unwrap() error { return e.Err }
+//Private ----------------------------------------------------------------------------------------------------------------------
+type kindError struct {
+    kind error                                                    //sentinel matched by errors.Is
+    err  error                                                    //the error itself, wrapping any underlying one
+}
+func (e *kindError) Error() string        { return e.err.Error() }
+func (e *kindError) Unwrap() error        { return e.err }
+func (e *kindError) Is(target error) bool { return target == e.kind }
+func blame(at *RecordError) {
+    //Converts a halt into one located by at, if at was set; to be deferred over the processing of records or keys
+    if p := recover(); p != nil {
+        if h, ok := p.(haltError); ok && (at.Line > 0 || at.File != "" || at.Key != "") {
+            located    := *at
+            located.Err = h.err
+            p           = haltError{&located}
+        }
+        panic(p)
+    }
+} //end func blame
+func haltKind(kind error, msg string, cause error) {
+    //Aborts the current operation as halt does, with an error matching kind and wrapping any cause
+    panic(haltError{newHaltError(kind, msg, cause)})
+} //end func haltKind
+func haltTemp(msg string, err error) {
+    //Aborts the current operation on a failed write of a temporary file, classed as ErrTempSpace if the space ran out
+    var kind error
+    if isNoSpace(err) { kind = ErrTempSpace }
+    panic(haltError{newHaltError(kind, msg, err)})
+} //end func haltTemp
+func interrupted(err error) error {
+    //Classes the error of a cancelled context as ErrInterrupted
+    if err == nil { return nil }
+    return &kindError{kind:ErrInterrupted, err:fmt.Errorf("mergesort: %w", err)}
+} //end func interrupted
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file errors.go
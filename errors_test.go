@@ -0,0 +1,85 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     errors_test.go
+ * Overview:
+ *     tests of the classes of the errors returned by the package, and of the location of the failures at a record.
+ * Functions:
+ *     TestErrorClasses(t *testing.T)
+ *         Checks that each failure triggered matches its sentinel, and the underlying error if any, under errors.Is.
+ *     TestRecordError(t *testing.T)
+ *         Checks that the error of a record handler is returned within a RecordError locating the record.
+ * History:
+ *     v1.33.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "context"
+    "errors"
+    "io/fs"
+    "math/rand"
+    "path/filepath"
+    "testing"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func TestErrorClasses(t *testing.T) {
+    input := randomInput(rand.New(rand.NewSource(22)), 2000)
+    sort  := func(inFile, usingFields string, opts ...Option) func(t *testing.T) error {
+        return func(t *testing.T) error {
+            return Sort(inFile, filepath.Join(t.TempDir(), "out.txt"), true, usingFields, "\t", 100, false,
+                        append([]Option{WithTempDir(t.TempDir())}, opts...)...)
+        }
+    }
+    cases := []struct {
+        name       string
+        run        func(t *testing.T) error
+        sentinel   error
+        underlying error                                            //wrapped error also matched, nil for none
+    }{
+        {"missing input", sort(filepath.Join(t.TempDir(), "missing.txt"), "2,1"), ErrInputNotFound, fs.ErrNotExist},
+        {"empty input", sort(writeInput(t, ""), "2,1"), ErrEmptyInput, nil},
+        {"malformed fields", sort(writeInput(t, input), "2,x"), ErrBadFieldSpec, nil},
+        {"field past the records", sort(writeInput(t, input), "9"), ErrBadFieldSpec, nil},
+        {"temporary space", sort(writeInput(t, input), "2,1", WithMaxTempSpace(1000)), ErrTempSpace, nil},
+        {"cancelled context", func(t *testing.T) error {
+            ctx, cancel := context.WithCancel(context.Background())
+            cancel()
+            in := make(chan string)                                 //never fed nor closed, the sort awaiting the context
+            chanOut, chanErr := SortChan(ctx, in, true, "2,1", "\t", 100, false, WithTempDir(t.TempDir()))
+            for range chanOut {}
+            return <-chanErr
+        }, ErrInterrupted, context.Canceled},
+    }
+    for _, test := range cases {
+        t.Run(test.name, func(t *testing.T) {
+            err := test.run(t)
+            if !errors.Is(err, test.sentinel) { t.Fatalf("error %v, expected %v", err, test.sentinel) }
+            if test.underlying != nil && !errors.Is(err, test.underlying) {
+                t.Errorf("error %v, expected to wrap %v", err, test.underlying)
+            }
+        })
+    }
+} //end func TestErrorClasses
+func TestRecordError(t *testing.T) {
+    //The third record lacking the second field, its line and offset reported with the handler's error
+    records  := []string{"b\t2\n", "a\t1\n", "c\n", "d\t4\n"}
+    inFile   := writeInput(t, records[0] + records[1] + records[2] + records[3])
+    errAbort := errors.New("aborted by the handler")
+    handler  := func(line int64, record string, err error) error {
+        if !errors.Is(err, ErrMalformedRecord) { t.Errorf("line %d: handler given %v, expected ErrMalformedRecord", line, err) }
+        return errAbort
+    }
+    err := Sort(inFile, filepath.Join(t.TempDir(), "out.txt"), true, "2,1", "\t", 100, false, WithTempDir(t.TempDir()),
+                WithRecordErrorHandler(handler))
+    if !errors.Is(err, errAbort) { t.Fatalf("error %v, expected the handler's", err) }
+    var at *RecordError
+    if !errors.As(err, &at) { t.Fatalf("error %v, expected a RecordError", err) }
+    if offset := int64(len(records[0] + records[1])); at.Line != 3 || at.Offset != offset || at.Record != "c" {
+        t.Errorf("failure at line %d, offset %d, record %q, expected line 3, offset %d, record \"c\"", at.Line, at.Offset,
+                 at.Record, offset)
+    }
+} //end func TestRecordError
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file errors_test.go
@@ -0,0 +1,246 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     estimate.go
+ * Overview:
+ *     planning of a sort before it is run: the runs, merge passes, temporary space and I/O it needs, worked out from the
+ *     size of the input and the lengths of its records and keys, given or sampled from the input.
+ * Functions:
+ *     Estimate(input EstimateInput, opts ...Option) (SortEstimate, error)
+ *         Works out the runs, merge passes, temporary space and I/O of a sort without running it.
+ * Types:
+ *     EstimateInput
+ *         Input of a sort to be estimated, by its file or its size and lengths.
+ *     SortEstimate
+ *         Figures of a sort worked out by Estimate.
+ * History:
+ *     v1.78.0 - October 15, 2026 - Original release.
+ *     v1.87.0 - October 15, 2026 - The last pass merges the smallest files first, as Sort does.
+ *     v1.88.0 - October 15, 2026 - Plans the merges of WithContinuousMerge & WithMaxLiveRuns.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "bufio"
+    "bytes"
+    "io"
+    "strconv"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+type EstimateInput struct {
+    File      string                                              //path of the input, "" if not at hand
+    Size      int64                                               //size of the input in bytes, 0 for that of File
+    RecordLen int                                                 //mean length of a record with its line feed, 0 to sample
+    KeyLen    int                                                 //mean length of the index fields of a key, 0 to sample
+}
+type SortEstimate struct {
+    Records        int64                                          //records keyed
+    KeyBytes       int64                                          //bytes of their composite keys, line feeds included
+    InMemory       bool                                           //input sorted in memory, within the spill threshold
+    Runs           int                                            //initial runs of keysPerSort keys
+    MergePasses    int                                            //passes of merges over the key files, as in SortStats
+    PeakTempBytes  int64                                          //most bytes held at once by the temporary files
+    TempBytes      int64                                          //bytes written to the temporary files, as in SortStats
+    TempReadBytes  int64                                          //bytes read back from the temporary files
+    InputReadBytes int64                                          //bytes read from the input
+    OutputBytes    int64                                          //bytes written to the output
+}
+
+func Estimate(input EstimateInput, opts ...Option) (estimate SortEstimate, err error) {
+/*         Purpose : Works out the runs, merge passes, temporary space and I/O of a sort without running it.
+ *       Arguments : input = the input, by its file or by its size and the mean lengths of its records and index fields.
+ *                   opts  = options of the sort, see NewSorter.
+ *         Returns : The estimate, and any error encountered.
+ * Externals -  In : _estimateSample
+ * Externals - Out : None.
+ *       Functions : NewSorter, catch, estimate
+ *         Remarks : The lengths not given are sampled by keying the records of the first _estimateSample bytes of input.File
+ *                   with the options of the sort, which yields the share of the records keyed, e.g. past the filters and
+ *                   blank lines, and the exact length of their keys, embedded records and line numbers included. A given
+ *                   KeyLen counts the index fields only, the offset and length of the record being added. The files of the
+ *                   runs and of the merges are then sized merge by merge as Sort creates them, so that Runs and MergePasses
+ *                   match those of the sort for inputs whose records resemble the sample, and TempBytes comes within a few
+ *                   percent of SortStats.TempBytes. PeakTempBytes assumes that all the runs are written before the first
+ *                   merge ends, but with WithContinuousMerge and WithMaxLiveRuns, whose merges are planned as they would run
+ *                   between the runs in WithDeterministic's single goroutine, and counts the files held in memory per
+ *                   WithInMemorySpillThreshold. The figures are upper bounds with WithTempCodec, whose compression is not
+ *                   estimated, and lower ones with WithFrequencyOrder, whose second sort of the keys is not. The input is
+ *                   not read at all when the record and key lengths are both given, and the estimate fails on any record of
+ *                   the sample that would fail the sort.
+ *         History : v1.78.0 - October 15, 2026 - Original release.
+ *                   v1.87.0 - October 15, 2026 - The last pass merges the smallest files first, as Sort does.
+ *                   v1.88.0 - October 15, 2026 - Plans the merges of WithContinuousMerge & WithMaxLiveRuns.
+ */
+    defer catch(&err)
+    sorter, err := NewSorter(opts...)
+    if err != nil { panic(haltError{err}) }
+    return sorter.newRun().estimate(input), nil
+} //end func Estimate
+//Private ----------------------------------------------------------------------------------------------------------------------
+const _estimateSample = 1 << 20 //bytes at the start of the input keyed to sample the lengths of its records and keys
+func (r *sortRun) estimate(input EstimateInput) SortEstimate {
+    //Works out the figures of a sort of the input from its size and the lengths of its records and keys, sampling those not
+    //given
+    var(
+        estimate   SortEstimate
+        recsByByte float64                                        //records keyed per byte of the input
+        keyLen     float64                                        //mean length of a key, with its line feed
+    )
+    size := input.Size
+    if size == 0 && input.File != "" {
+        fhIn, fileSize := r.openInput(input.File)
+        fhIn.Close()
+        size = fileSize
+    }
+    if size < 0 || input.RecordLen < 0 || input.KeyLen < 0 {
+        halt("the size of the input and the lengths of its records and keys cannot be negative")
+    }
+    if size == 0 { return estimate }
+    if input.RecordLen == 0 || input.KeyLen == 0 {
+        if input.File == "" { halt("the lengths of the records and keys must be given when no input file is sampled") }
+        records, keyBytes, sampleLen := r.sampleKeys(input.File, size)
+        recsByByte = float64(records) / float64(sampleLen)
+        if records > 0 { keyLen = float64(keyBytes) / float64(records) }
+    }
+    if input.RecordLen > 0 { recsByByte = 1 / float64(input.RecordLen) }
+    estimate.Records = int64(float64(size) * recsByByte + 0.5)
+    if input.KeyLen > 0 { keyLen = float64(input.KeyLen + r.keySuffixLen(size, estimate.Records)) }
+    estimate.KeyBytes, estimate.OutputBytes = int64(float64(estimate.Records) * keyLen + 0.5), size
+    if 2 * size <= r.memBudget {                                  //input and keys fit in the memory budget
+        estimate.InMemory, estimate.InputReadBytes = true, size
+        return estimate
+    }
+    estimate.InputReadBytes = r.scanLen(size) + 2 * size          //width scan, key generation & output
+    r.planMerges(&estimate, keyLen)
+    return estimate
+} //end func estimate
+func (r *sortRun) planMerges(estimate *SortEstimate, keyLen float64) {
+    //Sizes the run files and the files merged from them merge by merge, as externalSort creates them
+    var stored int64                                              //bytes of the key files present
+    fileLen := func(keys int64) int64 {
+        return int64(float64(keys) * keyLen + 0.5) + int64(len(_trailerMagic) + len(strconv.FormatInt(keys, 10)) + 11)
+    }
+    files := []int64{}                                            //numbers of keys of the files pending
+    for left := estimate.Records; left > 0; left -= int64(r.keysPerSort) {
+        if left < int64(r.keysPerSort) { files = append(files, left) } else { files = append(files, int64(r.keysPerSort)) }
+    }
+    if len(files) == 0 { files = []int64{0} }                     //no keys: an empty key file
+    estimate.Runs = len(files)
+    merge := func(group []int64) int64 {
+        //Merges a group of files into one, returning its number of keys
+        var keys, read int64
+        for _, k := range group {
+            keys += k
+            read += fileLen(k)
+        }
+        written                := fileLen(keys)
+        estimate.TempBytes     += written
+        estimate.TempReadBytes += read
+        if stored + written > estimate.PeakTempBytes { estimate.PeakTempBytes = stored + written }
+        stored += written - read
+        return keys
+    }
+    fanIn := r.mergeFanIn()
+    if r.maxLiveRuns > 0 || r.continuous && !r.deterministic {    //merges of a pool, as the runs are written
+        pool  := &mergePool{run:r, limit:r.maxLiveRuns}
+        drain := func() {
+            for group, _ := pool.take(); group != nil; group, _ = pool.take() {
+                keys := make([]int64, len(group))
+                for k, file := range group { keys[k] = file.size }
+                merge(keys)
+                pool.present -= len(group) - 1
+                pool.queue.addMerged("", group)
+            }
+        }
+        for _, keys := range files {
+            drain()                                               //room made for the run
+            written                := fileLen(keys)
+            estimate.TempBytes     += written
+            stored                 += written
+            if stored > estimate.PeakTempBytes { estimate.PeakTempBytes = stored }
+            pool.queue.add("", keys)
+            pool.present++
+            drain()
+        }
+        pool.closed = true
+        drain()
+        if pool.queue.added > len(files) { estimate.MergePasses = 1 }
+        estimate.TempReadBytes += stored                          //sorted keys, read for the output
+        return
+    }
+    for _, keys := range files { stored += fileLen(keys) }
+    estimate.TempBytes, estimate.PeakTempBytes = stored, stored
+    if len(files) >= fanIn {                                      //first pass, overlapping the runs
+        estimate.MergePasses++
+        merged := []int64{}
+        for ; len(files) >= fanIn; files = files[fanIn:] { merged = append(merged, merge(files[:fanIn])) }
+        files = append(merged, files...)
+    }
+    if len(files) > 1 { estimate.MergePasses++ }                  //last pass, the smallest files first
+    queue := mergeQueue{}
+    for _, keys := range files { queue.add("", keys) }
+    for queue.Len() > 1 {
+        group, _, _ := queue.take(nextFanIn(queue.Len(), fanIn))
+        keys        := make([]int64, len(group))
+        for k, file := range group { keys[k] = file.size }
+        queue.add("", merge(keys))
+    }
+    estimate.TempReadBytes += stored                              //sorted keys, read for the output
+} //end func planMerges
+func (r *sortRun) sampleKeys(inFile string, size int64) (records, keyBytes, sampleLen int64) {
+    //Keys the records of the start of the input as a sort would, without echo or handler of the malformed records, and
+    //returns the number of records keyed, the bytes of their keys with their line feeds and the bytes sampled
+    var at RecordError                                            //record being keyed
+
+    r.resolveSeparator(inFile)
+    sorter := *r.Sorter
+    sorter.verbose, sorter.sampled, sorter.lineRange      = false, false, false
+    sorter.onRecordError, sorter.errorCap, sorter.rateCap = nil, false, false
+    sorter.onRead                                         = nil
+    sample := &sortRun{Sorter:&sorter, fsys:r.fsys, spooled:r.spooled, counters:&runCounters{}}
+    fhIn, _ := sample.openInput(inFile)
+    defer fhIn.Close()
+    data := make([]byte, _estimateSample)
+    n, err := io.ReadFull(fhIn, data)
+    if err != nil && err != io.ErrUnexpectedEOF { halt("io.ReadFull - " + err.Error()) }
+    data = data[:n]
+    if int64(n) < size {                                          //sample cut short of the end: drop its partial record
+        if k := bytes.LastIndexByte(data, '\n'); k >= 0 { data = data[:k + 1] }
+    }
+    input          := bytes.NewReader(data)
+    readerIn       := bufio.NewReader(input)
+    keySpecs, _    := sample.scanFields(input, readerIn, false)
+    compositeKeyFn := makeCompositeKeyFn(sample.keyFields(), keySpecs, len(strconv.FormatInt(size, 10)))
+    errIn          := resetReader(input, readerIn)
+    defer blame(&at)
+    for lineNum, offset := 1, int64(0); errIn != io.EOF; lineNum++ {
+        var record string
+        at             = RecordError{Line:lineNum, Offset:offset}
+        record, errIn  = sample.readRecord(readerIn)
+        trimmed       := sample.trimRecord(record)
+        if sample.admits(record, trimmed, lineNum) {
+            if key, ok := sample.keyRecord(compositeKeyFn, &at, record, trimmed); ok {
+                keyBytes += int64(len(sample.payloadKey(sample.numberKey(key, lineNum), record)) + 1)
+                records++
+            }
+        }
+        offset += int64(len(record))
+    }
+    return records, keyBytes, int64(len(data))
+} //end func sampleKeys
+func (r *sortRun) keySuffixLen(size, records int64) int {
+    //Returns the length of the parts of a key following its index fields: offset, length, line number and line feed
+    suffixLen := 2 * (1 + len(strconv.FormatInt(size, 10))) + 1
+    if r.lineNumbers { suffixLen += 1 + len(strconv.FormatInt(records, 10)) }
+    return suffixLen
+} //end func keySuffixLen
+func (r *sortRun) scanLen(size int64) int64 {
+    //Returns the bytes of the input read to measure the widths of its index fields
+    if !r.sampled { return size }
+    scanned := r.sampleHead + int64(r.sampleBlocks) * _sampleBlockLen
+    if scanned > size { return size }
+    return scanned
+} //end func scanLen
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file estimate.go
@@ -0,0 +1,286 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     fieldtypes.go
+ * Overview:
+ *     typed index fields, encoded in the composite keys so that their string order is their natural order.
+ * Functions:
+ *     WithFieldType(column int, kind FieldType) Option
+ *         Option comparing an index field per its type rather than as text.
+ *     WithInvalidValuesLast(last bool) Option
+ *         Option placing the typed values that do not parse at the end or the start of the output.
+ * Types:
+ *     FieldType
+ *         Type of an index field.
+ * History:
+ *     v1.19.0 - October 15, 2026 - Original release.
+ *     v1.20.0 - October 15, 2026 - Added FieldHex.
+ *     v1.21.0 - October 15, 2026 - Added FieldDuration and WithInvalidValuesLast.
+ *     v1.22.0 - October 15, 2026 - Added FieldSemver.
+ *     v1.51.0 - October 15, 2026 - Numeric fields honour their thousands separator.
+ *     v1.52.0 - October 15, 2026 - Added FieldBoolean.
+ *     v1.53.0 - October 15, 2026 - Numeric fields honour their decimal separator.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "fmt"
+    "math"
+    "strconv"
+    "strings"
+    "time"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+type FieldType int
+const(
+    FieldText    FieldType = iota //right-aligned text, the default
+    FieldNumeric                  //signed decimal number
+    FieldHex                      //unsigned 64-bit hexadecimal number
+    FieldDuration                 //Go duration, e.g. "1h32m10s"
+    FieldSemver                   //semantic version, e.g. "v1.2.10-rc.1"
+    FieldBoolean                  //true or false, e.g. "yes" or "N"
+)
+
+func WithFieldType(column int, kind FieldType) Option {
+/*         Purpose : Compares an index field per its type rather than as text.
+ *       Arguments : column = number of the field, the first being 1. Must be one of the index fields.
+ *                   kind   = the type of the field.
+ *         Returns : The option.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : None.
+ *         Remarks : A numeric field is parsed with strconv.ParseFloat once trimmed of spaces, so signs, fractions and
+ *                   exponents are honoured and "-0", "+0" and "0" compare equal. Values are exact for integers of up to
+ *                   2^53 in magnitude. It enters the composite key as the 16 hex digits of its float64 bits, reordered
+ *                   so that negatives precede positives. Values that do not parse, empty ones included, compare equal
+ *                   to one another and ahead of every number, i.e. first in ascending sorts and last in descending ones.
+ *                   A hex field is parsed with strconv.ParseUint in base 16 once trimmed of spaces and of an optional
+ *                   "0x" or "0X" prefix, in any case, and enters the key as its 16 hex digits, zero-padded. Values
+ *                   that do not parse or exceed 64 bits are placed as the unparsed numeric ones. A duration field is
+ *                   parsed with time.ParseDuration, so "2m5s" precedes "10m1s" and negative or fractional values are
+ *                   honoured, and enters the key as its nanoseconds, offset to be unsigned, in 16 hex digits. A semver
+ *                   field follows the precedence rules of semver.org: an optional leading "v" and any build metadata
+ *                   are disregarded, a pre-release precedes its release and its identifiers compare numerically when
+ *                   all digits, and as ASCII text otherwise. The pre-release identifiers are compared on their first 64
+ *                   encoded characters. A boolean field holds a true or false token, matched whatever its case once
+ *                   trimmed of spaces, false preceding true; the tokens are those of WithBooleanTokens, by default
+ *                   "true", "t", "yes", "y", "on" and "1" against "false", "f", "no", "n", "off" and "0". Other values are
+ *                   placed as the unparsed numeric ones. Equal values keep their input order as for text fields. The
+ *                   number of values that did not parse is reported by SortStats, and WithInvalidValuesLast can move
+ *                   them to either end of the output. WithThousandsSeparator lets a numeric field hold grouped values,
+ *                   e.g. "1,234,567".
+ *         History : v1.19.0 - October 15, 2026 - Original release.
+ *                   v1.20.0 - October 15, 2026 - Added FieldHex.
+ *                   v1.21.0 - October 15, 2026 - Added FieldDuration.
+ *                   v1.22.0 - October 15, 2026 - Added FieldSemver.
+ *                   v1.52.0 - October 15, 2026 - Added FieldBoolean.
+ */
+    return func(s *Sorter) {
+        if s.fieldTypes == nil { s.fieldTypes = map[int]FieldType{} }
+        s.fieldTypes[column - 1] = kind
+    }
+} //end func WithFieldType
+func WithInvalidValuesLast(last bool) Option {
+    //Places the typed values that do not parse at the end of the output if last is true, at its start otherwise, whatever
+    //the sort direction. Without this option, they precede all others in ascending sorts and follow them in descending ones.
+    return func(s *Sorter) { s.invalidPlaced, s.invalidLast = true, last }
+} //end func WithInvalidValuesLast
+//Private ----------------------------------------------------------------------------------------------------------------------
+const(
+    _numericKeyLen = 16                                     //length of an encoded numeric, hex or duration field
+    _semverPreLen  = 64                                     //length of the encoded pre-release identifiers
+    _semverKeyLen  = 3 * _numericKeyLen + 1 + _semverPreLen //length of an encoded semver field
+    _semverIdChars = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz-"
+)
+func (s *Sorter) checkFieldTypes() {
+    //Halts on a typed, folded, normalized, truncated or null-placed field that is not an index field, or on an invalid type,
+    //normalizer or key length
+    for colIdx, kind := range s.fieldTypes {
+        if !s.isIndexField(colIdx) { haltKind(ErrBadFieldSpec, fmt.Sprintf("the typed column %d is not an index field",
+                                                                          colIdx + 1), nil) }
+        if kind < FieldText || kind > FieldBoolean { halt(fmt.Sprintf("the type of column %d is unknown", colIdx + 1)) }
+    }
+    for colIdx := range s.folds {
+        if !s.isIndexField(colIdx) { haltKind(ErrBadFieldSpec, fmt.Sprintf("the folded column %d is not an index field",
+                                                                          colIdx + 1), nil) }
+    }
+    for colIdx := range s.nulls {
+        if !s.isIndexField(colIdx) { haltKind(ErrBadFieldSpec, fmt.Sprintf("the null-placed column %d is not an index field",
+                                                                          colIdx + 1), nil) }
+    }
+    for colIdx, fn := range s.normalizers {
+        if !s.isIndexField(colIdx) { haltKind(ErrBadFieldSpec, fmt.Sprintf("the normalized column %d is not an index field",
+                                                                          colIdx + 1), nil) }
+        if fn == nil               { halt(fmt.Sprintf("the normalizer of column %d is nil", colIdx + 1)) }
+    }
+    for colIdx, maxLen := range s.keyLens {
+        if !s.isIndexField(colIdx) { haltKind(ErrBadFieldSpec, fmt.Sprintf("the truncated column %d is not an index field",
+                                                                          colIdx + 1), nil) }
+        if maxLen < 1              { halt(fmt.Sprintf("the key length of column %d must be at least 1", colIdx + 1)) }
+    }
+} //end func checkFieldTypes
+func (s *Sorter) isIndexField(colIdx int) bool {
+    for _, v := range s.colIdxs {
+        if v == colIdx { return true }
+    }
+    return false
+} //end func isIndexField
+func (s *Sorter) typeKeySpecs(keySpecs []keyParams) {
+    //Sets the types, directions, transformations, alignment & padding of the key specs, the encoded values of typed fields
+    //having fixed widths
+    for k := range keySpecs {
+        asc                                     := s.fieldAsc(k)
+        keySpecs[k].TYPE, keySpecs[k].INVHIGH    = s.fieldTypes[keySpecs[k].COLIDX], s.invalidHigh(asc)
+        keySpecs[k].PREPARE, keySpecs[k].NULLPRE = s.prepareFn(keySpecs[k].COLIDX), s.nullPrefix(keySpecs[k].COLIDX, asc)
+        keySpecs[k].DESC                         = asc != s.sortAsc
+        keySpecs[k].PARSING                      = s.parsing(keySpecs[k].COLIDX)
+        if keySpecs[k].TYPE != FieldText { keySpecs[k].FORMAT = "%s" }
+        if s.aligns[keySpecs[k].COLIDX] == AlignLeft { keySpecs[k].FORMAT = strings.Replace(keySpecs[k].FORMAT, "%", "%-", 1) }
+        if strict, padded := s.zeroPads[keySpecs[k].COLIDX]; padded {
+            keySpecs[k].ZEROPAD, keySpecs[k].STRICT = strings.Replace(keySpecs[k].FORMAT, "%", "%0", 1), strict
+        }
+    }
+} //end func typeKeySpecs
+func (s *Sorter) prepareFn(colIdx int) func(value string) string {
+    //Returns the transformation of a field's values before their encoding, i.e. its normalization, its folding then its
+    //truncation, or nil
+    normalize               := s.normalizers[colIdx]
+    stripDiacritics, folded := s.folds[colIdx]
+    maxLen, truncated       := s.keyLens[colIdx]
+    if normalize == nil && !folded && !truncated { return nil }
+    return func(value string) string {
+        if normalize != nil { value = normalize(value) }
+        if folded           { value = foldValue(value, stripDiacritics) }
+        if truncated        { value = truncateValue(value, maxLen) }
+        return value
+    }
+} //end func prepareFn
+func (s *Sorter) prepare(colIdx int, value string) string {
+    //Applies the transformation of a field to one of its values
+    if fn := s.prepareFn(colIdx); fn != nil { return fn(value) }
+    return value
+} //end func prepare
+type fieldParsing struct {
+    group   Grouping                 //thousands separator of a numeric field
+    decimal rune                     //decimal separator of a numeric field
+    tokens  map[string]string        //encodings of the lowercased tokens of a boolean field
+}
+func (s *Sorter) parsing(colIdx int) fieldParsing {
+    //Returns the settings of the parsing of a field's typed values
+    tokens := s.boolTokens[colIdx]
+    if tokens == nil { tokens = _defaultBoolTokens }
+    return fieldParsing{group:s.groupings[colIdx], decimal:s.decimalSeparator(colIdx), tokens:tokens}
+} //end func parsing
+func (s *Sorter) invalidHigh(asc bool) bool {
+    //Returns true if the values that do not parse of a field ordered in the given direction are to follow all others in
+    //ascending order
+    return s.invalidPlaced && s.invalidLast == asc
+} //end func invalidHigh
+func encodeField(kind FieldType, parsing fieldParsing, invalidHigh bool, value string) string {
+    //Returns the representation of a field value in the composite key, that of a value not parsing being all low or all high
+    encoded, ok := parseField(kind, parsing, value)
+    if ok { return encoded }
+    keyLen := _numericKeyLen
+    switch kind {
+        case FieldSemver:  keyLen = _semverKeyLen
+        case FieldBoolean: keyLen = 1
+    }
+    if invalidHigh { return strings.Repeat("~", keyLen) }
+    return strings.Repeat(" ", keyLen)
+} //end func encodeField
+func parseField(kind FieldType, parsing fieldParsing, value string) (string, bool) {
+    //Returns the representation of a field value in the composite key, and false if it does not parse per its type and
+    //parsing settings
+    switch kind {
+        case FieldNumeric:  return encodeNumeric(value, parsing.group, parsing.decimal)
+        case FieldHex:      return encodeHex(value)
+        case FieldDuration: return encodeDuration(value)
+        case FieldSemver:   return encodeSemver(value)
+        case FieldBoolean:  return encodeBoolean(value, parsing.tokens)
+    }
+    return value, true
+} //end func parseField
+func encodeNumeric(value string, group Grouping, decimal rune) (string, bool) {
+    //Maps a number, once stripped of its thousands separators and given a decimal point, onto 16 hex digits ordered as the
+    //numbers are
+    value, ok   := ungroup(strings.TrimSpace(value), group, decimal)
+    if !ok { return "", false }
+    if decimal == ',' {
+        if strings.Contains(value, ".") { return "", false }
+        value = strings.Replace(value, ",", ".", 1)
+    }
+    number, err := strconv.ParseFloat(value, 64)
+    if err != nil && !isRangeError(err) || math.IsNaN(number) { return "", false }
+    return encodeFloat(number), true
+} //end func encodeNumeric
+func encodeFloat(number float64) string {
+    //Maps a number other than NaN onto the 16 hex digits of its bits, reordered so that negatives precede positives
+    if number == 0 { number = 0 }                                 //-0 & +0
+    bits := math.Float64bits(number)
+    if bits >> 63 == 1 { bits = ^bits } else { bits |= 1 << 63 }
+    return fmt.Sprintf("%016x", bits)
+} //end func encodeFloat
+func encodeHex(value string) (string, bool) {
+    //Maps a hex number onto its 16 zero-padded digits
+    value = strings.TrimSpace(value)
+    if strings.HasPrefix(value, "0x") || strings.HasPrefix(value, "0X") { value = value[2:] }
+    number, err := strconv.ParseUint(value, 16, 64)
+    if err != nil { return "", false }
+    return fmt.Sprintf("%016x", number), true
+} //end func encodeHex
+func encodeDuration(value string) (string, bool) {
+    //Maps a duration onto the 16 hex digits of its nanoseconds offset by 2^63
+    duration, err := time.ParseDuration(strings.TrimSpace(value))
+    if err != nil { return "", false }
+    return fmt.Sprintf("%016x", uint64(duration) ^ 1 << 63), true
+} //end func encodeDuration
+func encodeSemver(value string) (string, bool) {
+    //Maps a semantic version onto its major, minor & patch numbers in 16 hex digits each, followed by "1" for a release or
+    //by "0" and its pre-release identifiers for a pre-release. Each identifier is encoded as "0", its number of digits &
+    //its digits if numeric, as "1" & itself otherwise, and is terminated by \x01. The identifiers are space-padded or cut.
+    value = strings.TrimSpace(value)
+    if strings.HasPrefix(value, "v") || strings.HasPrefix(value, "V") { value = value[1:] }
+    if k := strings.Index(value, "+"); k >= 0 { value = value[:k] }     //build metadata
+    var(
+        key     string                                            //encoded numbers
+        ids     string                                            //encoded pre-release identifiers
+        pre     string
+        release = true
+    )
+    if k := strings.Index(value, "-"); k >= 0 { value, pre, release = value[:k], value[k + 1:], false }
+    parts := strings.Split(value, ".")
+    if len(parts) != 3 { return "", false }
+    for _, v := range parts {
+        number, err := strconv.ParseUint(v, 10, 64)
+        if err != nil { return "", false }
+        key += fmt.Sprintf("%016x", number)
+    }
+    if release { return key + "1" + strings.Repeat(" ", _semverPreLen), true }
+    for _, id := range strings.Split(pre, ".") {
+        if id == "" || strings.Trim(id, _semverIdChars) != "" { return "", false }
+        if strings.Trim(id, "0123456789") == "" {
+            digits := strings.TrimLeft(id, "0")
+            if len(digits) > 99 { return "", false }
+            ids    += fmt.Sprintf("0%02d%s\x01", len(digits), digits)
+        } else {
+            ids += "1" + id + "\x01"
+        }
+    }
+    return key + "0" + fmt.Sprintf("%-*.*s", _semverPreLen, _semverPreLen, ids), true
+} //end func encodeSemver
+func (r *sortRun) countInvalid(fields []string) {
+    //Counts the typed index-field values of a record that do not parse
+    for colIdx, kind := range r.fieldTypes {
+        var value string
+        if colIdx < len(fields) { value = fields[colIdx] }
+        if _, ok := parseField(kind, r.parsing(colIdx), r.prepare(colIdx, value)); !ok { r.stats.InvalidValues++ }
+    }
+} //end func countInvalid
+func isRangeError(err error) bool {
+    //Returns true for the error of a number beyond the float64 range, which ParseFloat returns as ±Inf
+    numErr, ok := err.(*strconv.NumError)
+    return ok && numErr.Err == strconv.ErrRange
+} //end func isRangeError
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file fieldtypes.go
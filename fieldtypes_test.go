@@ -0,0 +1,76 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     fieldtypes_test.go
+ * Overview:
+ *     table-driven tests of the order of signed numeric fields, zero-padded upstream or grouped by thousands, in both
+ *     directions.
+ * Functions:
+ *     TestNumericOrder(t *testing.T)
+ *         Checks the order of columns of mixed signs, including negatives among mostly positive values.
+ * History:
+ *     v1.19.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "fmt"
+    "strings"
+    "testing"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func TestNumericOrder(t *testing.T) {
+    tests := []struct {
+        name   string
+        values []string                                           //values of the numeric field, in input order
+        opts   []Option
+        asc    []int                                              //indexes of the values in ascending order
+    }{
+        {"signs", []string{"3", "-2", "-10", "0", "-0", "+0", "10", "2.5", "-2.5"}, nil,
+         []int{2, 8, 1, 3, 4, 5, 7, 0, 6}},
+        {"zero-padded", []string{"007", "012", "-003", "100", "-010", "000", "-000", "099"}, nil,
+         []int{4, 2, 5, 6, 0, 1, 7, 3}},
+        {"mostly positive", []string{"5", "17", "3", "-1", "250", "42", "8", "-300", "0"}, nil,
+         []int{7, 3, 8, 2, 0, 6, 1, 5, 4}},
+        {"exponents", []string{"1e3", "-1e-3", "999.5", "-1E3", "0.001", "+1e0"}, nil,
+         []int{3, 1, 4, 5, 2, 0}},
+        {"thousands", []string{"1,234", "-1,234", "999", "-12,345.5", "12", "-0.5", "+1,000,000", "-999"},
+         []Option{WithThousandsSeparator(1, GroupComma)}, []int{3, 1, 7, 5, 4, 2, 0, 6}},
+        {"decimal comma", []string{"1.234,5", "-1.234", "-0,5", "12", "1.234", "-1.234,5"},
+         []Option{WithDecimalSeparator(1, ',', true)}, []int{5, 1, 2, 3, 4, 0}},
+    }
+    for _, test := range tests {
+        var input strings.Builder
+        for k, value := range test.values { fmt.Fprintf(&input, "%s\t%d\n", value, k) }
+        for _, sortAsc := range []bool{true, false} {
+            name     := fmt.Sprintf("%s/asc=%v", test.name, sortAsc)
+            opts     := append([]Option{WithFields("1"), WithFieldType(1, FieldNumeric), WithAscending(sortAsc)}, test.opts...)
+            expected := expectedOrder(test.values, test.asc, sortAsc)
+            if output := string(sortBytes(t, input.String(), opts...)); output != expected {
+                t.Errorf("%s: output\n%s\nexpected\n%s", name, output, expected)
+            }
+        }
+    }
+} //end func TestNumericOrder
+//Private ----------------------------------------------------------------------------------------------------------------------
+func expectedOrder(values []string, asc []int, sortAsc bool) string {
+    //Returns the records of the values in ascending order or, for a descending sort, in its reverse but for the runs of
+    //equal values, which keep their input order in both directions
+    equal := func(i, j int) bool { return strings.Trim(values[i], "+-0") == "" && strings.Trim(values[j], "+-0") == "" }
+    order := append([]int(nil), asc...)
+    if !sortAsc {
+        for i, j := 0, len(order) - 1; i < j; i, j = i + 1, j - 1 { order[i], order[j] = order[j], order[i] }
+        for start := 0; start < len(order); {
+            end := start + 1
+            for end < len(order) && equal(order[start], order[end]) { end++ }
+            for i, j := start, end - 1; i < j; i, j = i + 1, j - 1 { order[i], order[j] = order[j], order[i] }
+            start = end
+        }
+    }
+    var records strings.Builder
+    for _, k := range order { fmt.Fprintf(&records, "%s\t%d\n", values[k], k) }
+    return records.String()
+} //end func expectedOrder
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file fieldtypes_test.go
@@ -0,0 +1,100 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     filter.go
+ * Overview:
+ *     filtering of the records to be sorted on lists of field values read from files.
+ * Functions:
+ *     WithKeyFilterFile(path string, mode FilterMode, field int) Option
+ *         Option keeping only, or dropping, the records whose field holds a value listed in a file.
+ * Types:
+ *     FilterMode
+ *         Effect of a key filter on the records whose field value it lists.
+ *     KeyFilterStats
+ *         Outcome of a key filter.
+ * History:
+ *     v1.27.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "bufio"
+    "os"
+    "strings"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+type FilterMode int
+const(
+    FilterAllow FilterMode = iota //keeps only the records whose value is listed
+    FilterBlock                   //drops the records whose value is listed
+)
+type KeyFilterStats struct {
+    Path     string //file of the filter values
+    Retained int    //records that passed the filter
+    Removed  int    //records that the filter dropped
+}
+
+func WithKeyFilterFile(path string, mode FilterMode, field int) Option {
+/*         Purpose : Keeps only, or drops, the records whose field holds a value listed in a file.
+ *       Arguments : path  = path of the file listing the values, one per line.
+ *                   mode  = FilterAllow to keep only the records whose value is listed, FilterBlock to drop them.
+ *                   field = number of the field holding the value, the first being 1. Need not be an index field.
+ *         Returns : The option.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : None.
+ *         Remarks : NewSorter loads the values into a set, trimmed of spaces and line terminators, blank lines being
+ *                   skipped, so the list must fit in memory. The record values are compared exactly once the records
+ *                   are trimmed per WithTrimming, a missing field being empty. Several filters apply in the order of
+ *                   their options, a record dropped by one not reaching the next. The filtered records produce neither
+ *                   keys nor output, and SortStats reports how many records each filter retained and removed. Only
+ *                   the file sorts are filtered.
+ *         History : v1.27.0 - October 15, 2026 - Original release.
+ */
+    return func(s *Sorter) { s.filters = append(s.filters, &keyFilter{path:path, mode:mode, colIdx:field - 1}) }
+} //end func WithKeyFilterFile
+//Private ----------------------------------------------------------------------------------------------------------------------
+type keyFilter struct {
+    path   string
+    mode   FilterMode
+    colIdx int                    //0-based column of the value
+    values map[string]struct{}    //listed values, loaded by NewSorter
+}
+func (s *Sorter) loadFilters() {
+    //Reads the values of the key filters, halting on an invalid filter
+    for _, f := range s.filters {
+        if f.colIdx < 0 { haltKind(ErrBadFieldSpec, "the field of the key filter " + f.path + " is invalid", nil) }
+        if f.mode != FilterAllow && f.mode != FilterBlock { halt("the mode of the key filter " + f.path + " is unknown") }
+        fh, err := os.Open(f.path)
+        if err != nil { halt("os.Open - " + err.Error()) }
+        f.values = map[string]struct{}{}
+        scanner := bufio.NewScanner(fh)
+        for scanner.Scan() {
+            if value := strings.Trim(scanner.Text(), " \r\n"); value != "" { f.values[value] = struct{}{} }
+        }
+        fh.Close()
+        if err := scanner.Err(); err != nil { halt("scanner.Scan - " + err.Error()) }
+    }
+} //end func loadFilters
+func (r *sortRun) passesFilters(trimmed string) bool {
+    //Applies the key filters to a trimmed record, counting their outcomes
+    if len(r.filters) == 0 { return true }
+    if r.stats.KeyFilters == nil {
+        r.stats.KeyFilters = make([]KeyFilterStats, len(r.filters))
+        for k, f := range r.filters { r.stats.KeyFilters[k].Path = f.path }
+    }
+    fields := r.splitFields(trimmed, -1)
+    for k, f := range r.filters {
+        var value string
+        if f.colIdx < len(fields) { value = fields[f.colIdx] }
+        if _, listed := f.values[value]; listed != (f.mode == FilterAllow) {
+            r.stats.KeyFilters[k].Removed++
+            return false
+        }
+        r.stats.KeyFilters[k].Retained++
+    }
+    return true
+} //end func passesFilters
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file filter.go
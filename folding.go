@@ -0,0 +1,102 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     folding.go
+ * Overview:
+ *     normalization, Unicode case folding and diacritic stripping of index fields before they enter the composite keys.
+ * Functions:
+ *     WithFolding(column int, stripDiacritics bool) Option
+ *         Option comparing an index field on its case-folded value, optionally stripped of its diacritics.
+ *     WithKeyNormalizer(column int, fn func(string) string) Option
+ *         Option comparing an index field on its values as transformed by fn.
+ * History:
+ *     v1.23.0 - October 15, 2026 - Original release.
+ *     v1.24.0 - October 15, 2026 - Added WithKeyNormalizer.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "strings"
+    "unicode"
+
+    "golang.org/x/text/runes"
+    "golang.org/x/text/transform"
+    "golang.org/x/text/unicode/norm"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func WithFolding(column int, stripDiacritics bool) Option {
+/*         Purpose : Compares an index field on its case-folded value, optionally stripped of its diacritics.
+ *       Arguments : column          = number of the field, the first being 1. Must be one of the index fields.
+ *                   stripDiacritics = boolean flag for also removing the accents, cedillas, etc. of the letters.
+ *         Returns : The option.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : None.
+ *         Remarks : Folding follows the full case folding of Unicode for the letters that expand, e.g. "ß" and "ẞ" become
+ *                   "ss" and the Turkish dotted "İ" becomes "i" followed by a combining dot, while the dotless "ı" stays
+ *                   distinct from "i". Other letters fold as their lower case of their upper case. Stripping decomposes
+ *                   the folded value per NFD, removes its nonspacing marks, i.e. category Mn, and recomposes it per NFC,
+ *                   so that "É", "é" and "e" compare equal, as do "ș" and "s", "ǎ" and "a", "ơ" and "o", "ư" and "u" or
+ *                   "ế" and "e". Letters with no canonical decomposition, e.g. "Ø", "Ł" or "Œ", are kept. The records
+ *                   are output unchanged.
+ *         History : v1.23.0 - October 15, 2026 - Original release.
+ */
+    return func(s *Sorter) {
+        if s.folds == nil { s.folds = map[int]bool{} }
+        s.folds[column - 1] = stripDiacritics
+    }
+} //end func WithFolding
+func WithKeyNormalizer(column int, fn func(string) string) Option {
+/*         Purpose : Compares an index field on its values as transformed by fn.
+ *       Arguments : column = number of the field, the first being 1. Must be one of the index fields.
+ *                   fn     = the transformation, e.g. stripping a known prefix or mapping synonyms. Must not be nil.
+ *         Returns : The option.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : None.
+ *         Remarks : fn receives each value as split from its record and its result is measured by the width scan, then
+ *                   padded, typed or folded as the raw value would have been. Several normalizers of the same field
+ *                   are applied in the order of their options, ahead of any folding. fn must be deterministic and safe
+ *                   for concurrent use if the sorter is. The records are output unchanged.
+ *         History : v1.24.0 - October 15, 2026 - Original release.
+ */
+    return func(s *Sorter) {
+        if s.normalizers == nil { s.normalizers = map[int]func(string) string{} }
+        colIdx := column - 1
+        if prev := s.normalizers[colIdx]; prev != nil && fn != nil {
+            s.normalizers[colIdx] = func(value string) string { return fn(prev(value)) }
+        } else {
+            s.normalizers[colIdx] = fn
+        }
+    }
+} //end func WithKeyNormalizer
+//Private ----------------------------------------------------------------------------------------------------------------------
+var _fullFolds = map[rune]string{'ß':"ss", 'ẞ':"ss", 'İ':"i̇", 'ŉ':"ʼn"} //folds expanding a letter
+func foldValue(value string, stripDiacritics bool) string {
+    //Returns the case-folded value, without its diacritics if requested
+    var folded strings.Builder
+    for _, r := range value {
+        if full, ok := _fullFolds[r]; ok {
+            folded.WriteString(full)
+        } else if r == 'ı' {                                      //dotless i, whose upper case is I
+            folded.WriteRune(r)
+        } else {
+            folded.WriteRune(unicode.ToLower(unicode.ToUpper(r)))
+        }
+    }
+    if !stripDiacritics { return folded.String() }
+    return stripMarks(folded.String())
+} //end func foldValue
+func stripMarks(value string) string {
+    //Returns the value without its nonspacing marks, decomposed beforehand and recomposed afterwards
+    ascii := true
+    for k := 0; k < len(value) && ascii; k++ { ascii = value[k] < 0x80 }
+    if ascii { return value }                                     //no mark to strip
+    //A chain holds the state of its transformers, hence one per call for the concurrent key generation
+    stripped, _, err := transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), value)
+    if err != nil { return value }                                //cannot happen with an in-memory string
+    return stripped
+} //end func stripMarks
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file folding.go
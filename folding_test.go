@@ -0,0 +1,88 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     folding_test.go
+ * Overview:
+ *     tests of the case folding and diacritic stripping of index fields, with German, French, Turkish, Romanian and
+ *     Vietnamese names.
+ * Functions:
+ *     TestFoldValue(t *testing.T)
+ *         Checks which values fold together, with and without stripping.
+ *     TestFoldingSort(t *testing.T)
+ *         Checks that a sort groups the folded names in their input order and outputs the records unchanged.
+ * History:
+ *     v1.23.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "fmt"
+    "testing"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func TestFoldValue(t *testing.T) {
+    tests := []struct {
+        a, b  string
+        strip bool
+        equal bool
+    }{
+        //German: the sharp s expands, the umlauts only match their base letters once stripped
+        {"Straße", "STRASSE", false, true},
+        {"STRAẞE", "strasse", false, true},
+        {"Müller", "MÜLLER", false, true},
+        {"Müller", "Muller", false, false},
+        {"Müller", "MULLER", true, true},
+        //French: accents and cedillas, the ligature œ having no decomposition
+        {"Élodie", "élodie", false, true},
+        {"Élodie", "Elodie", false, false},
+        {"Élodie", "ELODIE", true, true},
+        {"Garçon", "GARCON", true, true},
+        {"Hélène", "HELENE", true, true},
+        {"cœur", "coeur", true, false},
+        //Turkish: the dotted İ folds to i with a combining dot, the dotless ı never matches i
+        {"İstanbul", "istanbul", false, false},
+        {"İstanbul", "ISTANBUL", true, true},
+        {"ışık", "IŞIK", false, false},
+        {"ışık", "isik", true, false},
+        {"ışık", "ıṣık", true, true},
+        {"Iğdır", "igdir", true, false},
+        //Letters outside Latin-1 and Latin Extended-A
+        {"Ștefan", "stefan", true, true},
+        {"Ǎ", "a", true, true},
+        {"Phơ", "pho", true, true},
+        {"Hưng", "hung", true, true},
+        {"Nguyễn Thị Thế", "nguyen thi the", true, true},
+        {"Nguyễn", "nguyen", false, false},
+        {"Łódź", "lodz", true, false},
+        {"Łódź", "łodz", true, true},
+    }
+    for _, test := range tests {
+        a, b := foldValue(test.a, test.strip), foldValue(test.b, test.strip)
+        if (a == b) != test.equal {
+            t.Errorf("%q and %q with stripping %v fold to %q and %q, expected equal = %v", test.a, test.b, test.strip, a,
+                     b, test.equal)
+        }
+    }
+    if folded := foldValue("Ab1-", true); folded != "ab1-" { t.Errorf("ASCII %q folds to %q", "Ab1-", folded) }
+} //end func TestFoldValue
+func TestFoldingSort(t *testing.T) {
+    input := "Zoë\t1\nÉlodie\t2\nSTRASSE\t3\nelodie\t4\nİstanbul\t5\nStraße\t6\nısık\t7\nELODIE\t8\nistanbul\t9\n"
+    tests := []struct {
+        strip   bool
+        sortAsc bool
+        output  string
+    }{
+        {true, true, "Élodie\t2\nelodie\t4\nELODIE\t8\nİstanbul\t5\nistanbul\t9\nSTRASSE\t3\nStraße\t6\nZoë\t1\nısık\t7\n"},
+        {true, false, "ısık\t7\nZoë\t1\nSTRASSE\t3\nStraße\t6\nİstanbul\t5\nistanbul\t9\nÉlodie\t2\nelodie\t4\nELODIE\t8\n"},
+        {false, true, "elodie\t4\nELODIE\t8\nistanbul\t9\nİstanbul\t5\nSTRASSE\t3\nStraße\t6\nZoë\t1\nÉlodie\t2\nısık\t7\n"},
+    }
+    for _, test := range tests {
+        name   := fmt.Sprintf("strip=%v/asc=%v", test.strip, test.sortAsc)
+        output := sortBytes(t, input, WithFields("1"), WithAscending(test.sortAsc), WithFolding(1, test.strip),
+                            WithAlignment(1, AlignLeft))
+        if string(output) != test.output { t.Errorf("%s: output\n%s\nexpected\n%s", name, output, test.output) }
+    }
+} //end func TestFoldingSort
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file folding_test.go
@@ -0,0 +1,27 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux
+// +build !darwin,!dragonfly,!freebsd,!linux
+
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     freespace_other.go
+ * Overview:
+ *     free space and device of the volume of a directory, on the systems not reporting them through statfs and stat.
+ * History:
+ *     v1.80.0 - October 15, 2026 - Original release.
+ *     v1.81.0 - October 15, 2026 - Added deviceOf.
+ *============================================================================================================================*/
+package mergesort
+
+//Private ----------------------------------------------------------------------------------------------------------------------
+func freeSpace(dir string) (int64, bool) {
+    //Returns that the free space of the volume of a directory is unknown
+    return 0, false
+} //end func freeSpace
+func deviceOf(dir string) (uint64, bool) {
+    //Returns that the device of the volume of a directory is unknown
+    return 0, false
+} //end func deviceOf
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file freespace_other.go
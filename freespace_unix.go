@@ -0,0 +1,34 @@
+//go:build darwin || dragonfly || freebsd || linux
+// +build darwin dragonfly freebsd linux
+
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     freespace_unix.go
+ * Overview:
+ *     free space and device of the volume of a directory, on the systems reporting them through statfs and stat.
+ * History:
+ *     v1.80.0 - October 15, 2026 - Original release.
+ *     v1.81.0 - October 15, 2026 - Added deviceOf.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "syscall"
+)
+//Private ----------------------------------------------------------------------------------------------------------------------
+func freeSpace(dir string) (int64, bool) {
+    //Returns the bytes of the volume of a directory available to the process, if known
+    var stat syscall.Statfs_t
+    if err := syscall.Statfs(dir, &stat); err != nil { return 0, false }
+    return int64(stat.Bavail) * int64(stat.Bsize), true
+} //end func freeSpace
+func deviceOf(dir string) (uint64, bool) {
+    //Returns the device number of the volume of a directory, if known
+    var stat syscall.Stat_t
+    if err := syscall.Stat(dir, &stat); err != nil { return 0, false }
+    return uint64(stat.Dev), true
+} //end func deviceOf
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file freespace_unix.go
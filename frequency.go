@@ -0,0 +1,78 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     frequency.go
+ * Overview:
+ *     ordering of the groups of records with equal index fields on their sizes, the most frequent first.
+ * Functions:
+ *     WithFrequencyOrder() Option
+ *         Option outputting the groups of records with equal index fields by decreasing size.
+ * History:
+ *     v1.28.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "fmt"
+    "math"
+    "sort"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func WithFrequencyOrder() Option {
+/*         Purpose : Outputs the groups of records with equal index fields by decreasing size.
+ *       Arguments : None.
+ *         Returns : The option.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : None.
+ *         Remarks : The keys are first sorted on the index fields as usual. A counting pass over the sorted keys then
+ *                   prefixes each key with the size of its group, and the prefixed keys are sorted anew, with the same
+ *                   runs and merges, so that the number of distinct keys need not fit in memory. Groups of equal size
+ *                   follow the sort direction and the records of a group keep their input order. Applies to all the
+ *                   sorts but SortStrings.
+ *         History : v1.28.0 - October 15, 2026 - Original release.
+ */
+    return func(s *Sorter) { s.freqOrder = true }
+} //end func WithFrequencyOrder
+//Private ----------------------------------------------------------------------------------------------------------------------
+func (r *sortRun) orderByFrequency(sortedKeysFile string) string {
+    //Sorts anew the keys of a file sorted on the index fields, on the sizes of their groups then on the index fields
+    stage       := *r                                             //same settings & statistics, own temporary files
+    stage.prefix = r.prefix + "freq_"
+    fhGroups, groups := r.openKeys(sortedKeysFile)                //reads ahead to size the groups
+    defer fhGroups.Close()
+    fhKeys, keys     := r.openKeys(sortedKeysFile)                //reads the keys of the sized groups
+    defer fhKeys.Close()
+    sorted := stage.externalSort(func(emit func(key string)) {
+        haveKey := groups.Scan()
+        for haveKey {
+            first, count := groups.Text(), 1
+            for haveKey = groups.Scan(); haveKey && sameGroup(first, groups.Text()); haveKey = groups.Scan() { count++ }
+            prefix := r.frequencyPrefix(count)
+            for k := 0; k < count && keys.Scan(); k++ { emit(prefix + keys.Text()) }
+        }
+        if err := groups.Err(); err != nil { haltKind(nil, "groups.Scan", err) }
+        if err := keys.Err();   err != nil { haltKind(nil, "keys.Scan", err) }
+    })
+    fhGroups.Close()
+    fhKeys.Close()
+    r.removeTemp(sortedKeysFile)
+    return sorted
+} //end func orderByFrequency
+func (r *sortRun) frequencyOrder(keys []string) {
+    //Sorts anew a slice of keys sorted on the index fields, on the sizes of their groups then on the index fields
+    for start, end := 0, 0; start < len(keys); start = end {
+        for end = start + 1; end < len(keys) && sameGroup(keys[start], keys[end]); end++ {}
+        prefix := r.frequencyPrefix(end - start)
+        for k := start; k < end; k++ { keys[k] = prefix + keys[k] }
+    }
+    sort.Slice(keys, func(i, j int) bool { return keyPrecedes(keys[i], keys[j], r.sortAsc) })
+} //end func frequencyOrder
+func (r *sortRun) frequencyPrefix(count int) string {
+    //Returns the key prefix of a group size, decreasing with the size in ascending sorts & increasing in descending ones
+    if r.sortAsc { return fmt.Sprintf("%019d", math.MaxInt64 - int64(count)) }
+    return fmt.Sprintf("%019d", count)
+} //end func frequencyPrefix
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file frequency.go
@@ -0,0 +1,55 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     frequency_test.go
+ * Overview:
+ *     tests of the output of the groups of equal index fields by decreasing size.
+ * Functions:
+ *     TestFrequencyOrder(t *testing.T)
+ *         Checks the groups output by decreasing size, ties in the sort direction and records in input order.
+ * History:
+ *     v1.28.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "fmt"
+    "testing"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func TestFrequencyOrder(t *testing.T) {
+    //Groups "b" of 3 records, "c" and "d" of 2 and "a" of one, interleaved
+    const input = "d\t1\n" + "b\t1\n" + "a\t1\n" + "c\t1\n" + "b\t2\n" + "d\t2\n" + "c\t2\n" + "b\t3\n"
+    cases := []struct {
+        sortAsc  bool
+        expected string
+    }{
+        {true, "b\t1\nb\t2\nb\t3\n" + "c\t1\nc\t2\n" + "d\t1\nd\t2\n" + "a\t1\n"},
+        {false, "b\t1\nb\t2\nb\t3\n" + "d\t1\nd\t2\n" + "c\t1\nc\t2\n" + "a\t1\n"},
+    }
+    for _, test := range cases {
+        for _, keysPerSort := range []int{3, 100} {                 //merged runs, or a single one
+            output := sortBytes(t, input, WithFields("1"), WithSeparator("\t"), WithAscending(test.sortAsc),
+                                WithKeysPerSort(keysPerSort), WithFrequencyOrder())
+            if string(output) != test.expected {
+                t.Errorf("asc %v, keysPerSort %d: output %q, expected %q", test.sortAsc, keysPerSort, output,
+                         test.expected)
+            }
+        }
+    }
+    //The keys of more groups than a run holds
+    var many, expected string
+    for k := 0; k < 30; k++ {
+        many += fmt.Sprintf("k%02d\t0\n", k)
+        if k % 3 == 0 { many += fmt.Sprintf("k%02d\t1\n", k) }
+    }
+    for k := 0; k < 30; k += 3 { expected += fmt.Sprintf("k%02d\t0\nk%02d\t1\n", k, k) }
+    for k := 0; k < 30; k++ {
+        if k % 3 != 0 { expected += fmt.Sprintf("k%02d\t0\n", k) }
+    }
+    output := sortBytes(t, many, WithFields("1"), WithSeparator("\t"), WithKeysPerSort(4), WithFrequencyOrder())
+    if string(output) != expected { t.Errorf("output %q, expected %q", output, expected) }
+} //end func TestFrequencyOrder
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file frequency_test.go
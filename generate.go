@@ -0,0 +1,164 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     generate.go
+ * Overview:
+ *     generation of reproducible random test files, for benchmarks and fuzzing.
+ * Functions:
+ *     GenerateTestFile(path string, spec GenSpec) error
+ *         Writes a file of random records per spec, the same seed always giving the same bytes.
+ * Types:
+ *     GenSpec
+ *         Specification of a test file.
+ *     GenField
+ *         Specification of a field of a test file.
+ *     GenType
+ *         Kind of the values of a generated field.
+ * History:
+ *     v1.39.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "bufio"
+    "os"
+    "strconv"
+    "strings"
+    "time"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+type GenType int
+const(
+    GenString GenType = iota         //string of characters of the field's alphabet, the default
+    GenInt                           //unsigned decimal integer
+    GenFloat                         //unsigned decimal number with two decimals
+    GenDate                          //date formatted as "2006-01-02", from 1970 to 2099
+)
+type GenField struct {
+    Type     GenType                 //kind of the values
+    Width    int                     //most characters of a string or digits of an integer part, 8 if 0; not used by dates
+    Alphabet string                  //characters of the strings, the ascii letters if empty
+}
+type GenSpec struct {
+    Records   int64                  //number of records
+    Fields    []GenField             //specifications of the fields, in order
+    Sep       string                 //field separator, a tab if empty
+    KeyFields int                    //number of leading fields repeated by duplicate keys, 1 if 0
+    DupRate   float64                //probability, from 0 to 1, that a record repeats the key of a recent one
+    Seed      uint64                 //seed of the pseudo-random generator
+}
+
+func GenerateTestFile(path string, spec GenSpec) (err error) {
+/*         Purpose : Writes a file of random records per spec, the same seed always giving the same bytes.
+ *       Arguments : path = path of the file to create, overwritten if it exists.
+ *                   spec = the specification of the file. It must hold at least one field.
+ *         Returns : Any error encountered.
+ * Externals -  In : _genRecentKeys
+ * Externals - Out : None.
+ *       Functions : catch, halt, newGenRand
+ *         Remarks : Each record holds the fields of spec, joined by its separator and ended by a line feed. Strings and
+ *                   integers have from 1 to Width characters or digits, integers without leading zeros, floats an
+ *                   integer part as the integers followed by a point and two digits. A duplicate record repeats the
+ *                   KeyFields leading fields of one of the last _genRecentKeys records, its other fields being drawn
+ *                   anew. The generator is a SplitMix64 seeded by spec.Seed, independent of math/rand, so the bytes
+ *                   are the same on every machine and Go release. Records are written as they are drawn, through a
+ *                   buffer, so the memory taken does not grow with the size of the file.
+ *         History : v1.39.0 - October 15, 2026 - Original release.
+ */
+    defer catch(&err)
+    if path            == "" { halt("the test file was not specified") }
+    if len(spec.Fields) == 0 { halt("the test file has no fields") }
+    if spec.Records     < 0  { halt("the number of records cannot be negative") }
+    if spec.DupRate < 0 || spec.DupRate > 1 { halt("the duplicate-key rate must be from 0 to 1") }
+    var(
+        sep       = spec.Sep
+        keyFields = spec.KeyFields
+        alphabets = make([][]rune, len(spec.Fields))
+        recent    = make([][]string, 0, _genRecentKeys)           //keys of the last records, as a ring
+        rnd       = newGenRand(spec.Seed)
+    )
+    if sep       == "" { sep = "\t" }
+    if keyFields <= 0 || keyFields > len(spec.Fields) { keyFields = 1 }
+    for k, field := range spec.Fields {
+        alphabets[k] = []rune(field.Alphabet)
+        if len(alphabets[k]) == 0 { alphabets[k] = []rune(_genLetters) }
+    }
+    fh, err := os.Create(path)
+    if err != nil { halt("os.Create - " + err.Error()) }
+    defer fh.Close()
+    writer := bufio.NewWriterSize(fh, 1 << 20)
+    fields := make([]string, len(spec.Fields))
+    for recNum := int64(0); recNum < spec.Records; recNum++ {
+        dup := len(recent) > 0 && spec.DupRate > 0 && rnd.float() < spec.DupRate
+        if dup { copy(fields, recent[rnd.intn(len(recent))]) }
+        for k, field := range spec.Fields {
+            if dup && k < keyFields { continue }
+            fields[k] = rnd.value(field, alphabets[k])
+        }
+        key := append([]string(nil), fields[:keyFields]...)
+        if len(recent) < _genRecentKeys { recent = append(recent, key) } else { recent[recNum % _genRecentKeys] = key }
+        if _, err := writer.WriteString(strings.Join(fields, sep) + "\n"); err != nil {
+            halt("writer.WriteString - " + err.Error())
+        }
+    }
+    if err := writer.Flush(); err != nil { halt("writer.Flush - " + err.Error()) }
+    if err := fh.Sync();      err != nil { halt("fh.Sync - " + err.Error()) }
+    if err := fh.Close();     err != nil { halt("fh.Close - " + err.Error()) }
+    return
+} //end func GenerateTestFile
+//Private ----------------------------------------------------------------------------------------------------------------------
+const(
+    _genRecentKeys   = 1024                                       //recent keys among which duplicates are drawn
+    _genDefaultWidth = 8                                          //width of the fields whose width is 0
+    _genLetters      = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+    _genDays         = 47482                                      //days from 1970-01-01 to 2099-12-31, inclusive
+)
+type genRand struct {
+    state uint64
+}
+func newGenRand(seed uint64) *genRand {
+    return &genRand{state:seed}
+} //end func newGenRand
+func (g *genRand) next() uint64 {
+    //Returns the next 64 bits of the SplitMix64 sequence
+    g.state += 0x9e3779b97f4a7c15
+    z := g.state
+    z   = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+    z   = (z ^ (z >> 27)) * 0x94d049bb133111eb
+    return z ^ (z >> 31)
+} //end func next
+func (g *genRand) intn(n int) int {
+    return int(g.next() % uint64(n))
+} //end func intn
+func (g *genRand) float() float64 {
+    //Returns a number from 0 up to, but excluding, 1
+    return float64(g.next() >> 11) / (1 << 53)
+} //end func float
+func (g *genRand) digits(width int) string {
+    //Returns an integer of 1 to width digits, without leading zeros
+    numDigits := 1 + g.intn(width)
+    if numDigits == 1 { return strconv.Itoa(g.intn(10)) }
+    value := []byte{byte('1' + g.intn(9))}
+    for k := 1; k < numDigits; k++ { value = append(value, byte('0' + g.intn(10))) }
+    return string(value)
+} //end func digits
+func (g *genRand) value(field GenField, alphabet []rune) string {
+    //Returns a random value of a field
+    width := field.Width
+    if width <= 0 { width = _genDefaultWidth }
+    switch field.Type {
+        case GenInt:
+            return g.digits(width)
+        case GenFloat:
+            return g.digits(width) + "." + strconv.Itoa(g.intn(10)) + strconv.Itoa(g.intn(10))
+        case GenDate:
+            return time.Unix(int64(g.intn(_genDays)) * 86400, 0).UTC().Format("2006-01-02")
+        default:
+            value := make([]rune, 1 + g.intn(width))
+            for k := range value { value[k] = alphabet[g.intn(len(alphabet))] }
+            return string(value)
+    }
+} //end func value
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file generate.go
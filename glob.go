@@ -0,0 +1,117 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     glob.go
+ * Overview:
+ *     batch sort of all the files matching a glob pattern, with the same settings.
+ * Functions:
+ *     SortGlob(pattern, outDirOrTemplate string, opts ...Option) ([]GlobResult, error)
+ *         Sorts every file matching a pattern to its own output file, several at once.
+ * Types:
+ *     GlobResult
+ *         Outcome of the sort of one of the files of SortGlob.
+ * History:
+ *     v1.42.0 - October 15, 2026 - Original release.
+ *     v1.74.0 - October 15, 2026 - Inputs removed per WithRemoveInput once all are sorted.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "fmt"
+    "os"
+    "path/filepath"
+    "strings"
+    "sync"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+type GlobResult struct {
+    InFile  string                   //path of the file sorted
+    OutFile string                   //path of its sorted copy
+    Err     error                    //failure of its sort, nil if it succeeded
+}
+
+func SortGlob(pattern, outDirOrTemplate string, opts ...Option) (results []GlobResult, err error) {
+/*         Purpose : Sorts every file matching a pattern to its own output file, several at once.
+ *       Arguments : pattern          = the glob pattern, per filepath.Match.
+ *                   outDirOrTemplate = the output directory, or a template of the output paths holding one or more of
+ *                                      the placeholders {dir}, {base}, {name} and {ext}, i.e. the directory, the base
+ *                                      name, the base name without its extension and the extension of an input file.
+ *                                      If empty, each output file sits next to its input. Outside a template, the
+ *                                      output file is named after its input's base name followed by ".sorted".
+ *                   opts             = options, see NewSorter. WithFields is mandatory.
+ *         Returns : The outcome of each file, in the lexical order of their paths, and any error in the pattern or the
+ *                   options, in which case no file is sorted.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : NewSorter, catch, globOutput, halt, removeGlobInputs
+ *         Remarks : The files are sorted by a single sorter, WithParallelism files at a time, so that they share its cap on
+ *                   open temporary files and its in-memory budget. The cap of WithMaxTempSpace applies to each file and
+ *                   to the temporary files of all the files sorted at once, the file writing past it failing with
+ *                   ErrTempSpace. A failed file does not stop the others: its error is reported in its result.
+ *                   Directories matching the pattern are skipped, as are the files whose output path would be their own.
+ *                   With WithRemoveInput, the inputs are removed only once all of them are sorted, and none if any fails.
+ *         History : v1.42.0 - October 15, 2026 - Original release.
+ *                   v1.74.0 - October 15, 2026 - Inputs removed per WithRemoveInput once all are sorted.
+ */
+    var(
+        sorter    *Sorter
+        sync4Sort sync.WaitGroup                                   //completion of the sorts
+    )
+
+    defer catch(&err)
+    if pattern == "" { halt("the glob pattern was not specified") }
+    matches, err := filepath.Glob(pattern)
+    if err != nil { halt("filepath.Glob - " + err.Error()) }
+    if sorter, err = NewSorter(opts...); err != nil { panic(haltError{err}) }
+    if sorter.maxTemp > 0 {                                         //temporary space shared by the files
+        sorter.poolSpace = &tempSpace{limit:sorter.maxTemp, files:map[string]int64{},
+                                      owner:"WithMaxTempSpace, shared by the files of SortGlob"}
+    }
+    for _, inFile := range matches {
+        if fi, err := os.Stat(inFile); err == nil && fi.IsDir() { continue }
+        outFile := globOutput(inFile, outDirOrTemplate)
+        if filepath.Clean(outFile) == filepath.Clean(inFile) { continue }
+        results = append(results, GlobResult{InFile:inFile, OutFile:outFile})
+    }
+    removeInput       := sorter.removeInput
+    sorter.removeInput = false                                      //inputs removed once all are sorted
+    slots             := make(chan struct{}, sorter.parallelism)    //sorts in progress
+    for k := range results {
+        slots<- struct{}{}
+        sync4Sort.Add(1)
+        go func(result *GlobResult) {
+            defer sync4Sort.Done()
+            result.Err = sorter.Run(result.InFile, result.OutFile)
+            <-slots
+        }(&results[k])
+    }
+    sync4Sort.Wait()
+    if removeInput { removeGlobInputs(results) }
+    return results, nil
+} //end func SortGlob
+//Private ----------------------------------------------------------------------------------------------------------------------
+func removeGlobInputs(results []GlobResult) {
+    //Removes the inputs of SortGlob if all were sorted, reporting the failures to remove them in their results
+    for _, result := range results {
+        if result.Err != nil { return }
+    }
+    for k, result := range results {
+        if err := os.Remove(result.InFile); err != nil {
+            results[k].Err = fmt.Errorf("mergesort.SortGlob: the input was sorted but not removed - %w", err)
+        }
+    }
+} //end func removeGlobInputs
+func globOutput(inFile, outDirOrTemplate string) string {
+    //Returns the output path of an input file of SortGlob
+    dir, base := filepath.Dir(inFile), filepath.Base(inFile)
+    if !strings.Contains(outDirOrTemplate, "{") {
+        if outDirOrTemplate != "" { dir = outDirOrTemplate }
+        return filepath.Join(dir, base + ".sorted")
+    }
+    ext := filepath.Ext(base)
+    return strings.NewReplacer("{dir}", dir, "{base}", base, "{name}", strings.TrimSuffix(base, ext),
+                               "{ext}", ext).Replace(outDirOrTemplate)
+} //end func globOutput
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file glob.go
@@ -0,0 +1,129 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     glob_test.go
+ * Overview:
+ *     tests of the batch sorts of SortGlob: the paths of their outputs, their failures, the removal of their inputs and the
+ *     temporary space they share.
+ * Functions:
+ *     TestSortGlobOutputs(t *testing.T)
+ *         Checks the output paths given by a template, an output directory or none, the directories matched skipped.
+ *     TestSortGlobFailures(t *testing.T)
+ *         Checks that a failed file leaves the others sorted and, with WithRemoveInput, every input in place.
+ *     TestSortGlobTempSpace(t *testing.T)
+ *         Checks that the files sorted at once count their temporary files against a single cap.
+ * History:
+ *     v1.42.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "errors"
+    "io/ioutil"
+    "math/rand"
+    "os"
+    "path/filepath"
+    "testing"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func TestSortGlobOutputs(t *testing.T) {
+    //Inputs "a.txt" and "b.log" beside a directory also matching the pattern
+    rng    := rand.New(rand.NewSource(19))
+    outDir := t.TempDir()
+    cases  := []struct {
+        outDirOrTemplate string
+        outputs          func(dir string) []string
+    }{
+        {"{dir}/out/{name}.sorted{ext}", func(dir string) []string {
+            return []string{filepath.Join(dir, "out", "a.sorted.txt"), filepath.Join(dir, "out", "b.sorted.log")}
+        }},
+        {"", func(dir string) []string {
+            return []string{filepath.Join(dir, "a.txt.sorted"), filepath.Join(dir, "b.log.sorted")}
+        }},
+        {outDir, func(dir string) []string {
+            return []string{filepath.Join(outDir, "a.txt.sorted"), filepath.Join(outDir, "b.log.sorted")}
+        }},
+    }
+    for _, test := range cases {
+        dir    := t.TempDir()
+        inputs := map[string]string{}
+        for _, name := range []string{"a.txt", "b.log"} {
+            inputs[name] = randomInput(rng, 500)
+            if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(inputs[name]), 0644); err != nil { t.Fatal(err) }
+        }
+        if err := os.Mkdir(filepath.Join(dir, "out"), 0755); err != nil { t.Fatal(err) }
+        results, err := SortGlob(filepath.Join(dir, "*"), test.outDirOrTemplate, WithFields("2,1"), WithKeysPerSort(100),
+                                 WithTempDir(t.TempDir()))
+        if err != nil { t.Fatal(err) }
+        outputs := test.outputs(dir)
+        if len(results) != len(outputs) {
+            t.Fatalf("template %q: %d results, expected %d: %v", test.outDirOrTemplate, len(results), len(outputs), results)
+        }
+        for k, result := range results {
+            if result.Err != nil { t.Fatalf("template %q, %s: %v", test.outDirOrTemplate, result.InFile, result.Err) }
+            if result.OutFile != outputs[k] {
+                t.Errorf("template %q: %s sorted to %s, expected %s", test.outDirOrTemplate, result.InFile, result.OutFile,
+                         outputs[k])
+            }
+            checkSorted(t, inputs[filepath.Base(result.InFile)], result.OutFile, "2,1")
+        }
+    }
+} //end func TestSortGlobOutputs
+func TestSortGlobFailures(t *testing.T) {
+    //Inputs "a.txt" and "c.txt" sorted around the empty "b.txt", their inputs kept since one failed
+    rng    := rand.New(rand.NewSource(20))
+    dir    := t.TempDir()
+    outDir := t.TempDir()
+    inputs := map[string]string{"a.txt":randomInput(rng, 500), "b.txt":"", "c.txt":randomInput(rng, 500)}
+    for name, input := range inputs {
+        if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(input), 0644); err != nil { t.Fatal(err) }
+    }
+    opts := []Option{WithFields("2,1"), WithKeysPerSort(100), WithTempDir(t.TempDir()), WithRemoveInput()}
+    results, err := SortGlob(filepath.Join(dir, "*.txt"), outDir, opts...)
+    if err != nil { t.Fatal(err) }
+    if len(results) != 3 { t.Fatalf("%d results, expected 3: %v", len(results), results) }
+    for _, result := range results {
+        name := filepath.Base(result.InFile)
+        if name == "b.txt" {
+            if !errors.Is(result.Err, ErrEmptyInput) { t.Errorf("%s: error %v, expected ErrEmptyInput", name, result.Err) }
+        } else {
+            if result.Err != nil { t.Fatalf("%s: %v", name, result.Err) }
+            checkSorted(t, inputs[name], result.OutFile, "2,1")
+        }
+        if _, err := os.Stat(result.InFile); err != nil { t.Errorf("%s removed though b.txt failed (%v)", name, err) }
+    }
+    //The inputs removed once all are sorted
+    if err := os.Remove(filepath.Join(dir, "b.txt")); err != nil { t.Fatal(err) }
+    if results, err = SortGlob(filepath.Join(dir, "*.txt"), outDir, opts...); err != nil { t.Fatal(err) }
+    if len(results) != 2 { t.Fatalf("%d results, expected 2: %v", len(results), results) }
+    for _, result := range results {
+        if result.Err != nil { t.Fatalf("%s: %v", result.InFile, result.Err) }
+        if _, err := os.Stat(result.InFile); !os.IsNotExist(err) { t.Errorf("%s kept once all are sorted", result.InFile) }
+        checkSorted(t, inputs[filepath.Base(result.InFile)], result.OutFile, "2,1")
+    }
+} //end func TestSortGlobFailures
+func TestSortGlobTempSpace(t *testing.T) {
+    const limit = 1 << 30
+    rng := rand.New(rand.NewSource(21))
+    dir := t.TempDir()
+    for _, name := range []string{"a.txt", "b.txt", "c.txt", "d.txt"} {
+        if err := ioutil.WriteFile(filepath.Join(dir, name), []byte(randomInput(rng, 2000)), 0644); err != nil { t.Fatal(err) }
+    }
+    var sorter *Sorter
+    capture := func(s *Sorter) { sorter = s }                     //the sorter of SortGlob, as built by NewSorter
+    results, err := SortGlob(filepath.Join(dir, "*.txt"), t.TempDir(), WithFields("2,1"), WithKeysPerSort(100),
+                             WithParallelism(4), WithMaxTempSpace(limit), WithTempDir(t.TempDir()), capture)
+    if err != nil { t.Fatal(err) }
+    for _, result := range results {
+        if result.Err != nil { t.Fatalf("%s: %v", result.InFile, result.Err) }
+    }
+    shared := sorter.poolSpace
+    if shared == nil || shared.limit != limit { t.Fatalf("shared space %+v, expected a cap of %d bytes", shared, limit) }
+    if used, files := shared.present(); used != 0 || files != 0 {
+        t.Errorf("%d bytes in %d temporary files still counted after the sorts", used, files)
+    }
+    if peak, _ := shared.peaks(); peak == 0 { t.Error("no temporary bytes counted against the shared cap") }
+} //end func TestSortGlobTempSpace
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file glob_test.go
@@ -0,0 +1,91 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     hashorder.go
+ * Overview:
+ *     seeded pseudo-random ordering of the records on a keyed hash of their index fields.
+ * Functions:
+ *     WithHashOrder(seed uint64) Option
+ *         Option ordering the records on a keyed hash of each index field rather than on its value.
+ * History:
+ *     v1.14.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "encoding/binary"
+    "fmt"
+    "math/bits"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func WithHashOrder(seed uint64) Option {
+/*         Purpose : Orders the records on a keyed hash of each index field rather than on its value.
+ *       Arguments : seed = the key of the hash.
+ *         Returns : The option.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : None.
+ *         Remarks : Each index field is represented in the composite key by its SipHash-2-4 digest followed by its value,
+ *                   so that records with equal fields stay adjacent and in their input order, like "sort -R". The same
+ *                   seed and input always yield the same output. Files so ordered cannot be searched by Lookup or Join.
+ *         History : v1.14.0 - October 15, 2026 - Original release.
+ */
+    return func(s *Sorter) { s.hashOrder, s.hashSeed = true, seed }
+} //end func WithHashOrder
+//Private ----------------------------------------------------------------------------------------------------------------------
+func (s *Sorter) orderKey(record string) []string {
+    //Returns the index-field values of a record as compared by the sorter, each preceded by its digest in hash order, by its
+    //null marker if its empty values are placed and by its class of value if zero-padded, after the derived key if any
+    fields  := s.splitFields(s.trimRecord(record), -1)
+    values  := fieldValues(fields, s.colIdxs)
+    ordered := make([]string, 0, 4 * len(values) + 1)
+    if s.derive != nil { ordered = append(ordered, derivedPart(keyParams{DERIVE:s.derive}, fields)) }
+    for k, colIdx := range s.colIdxs {
+        value := s.prepare(colIdx, values[k])
+        asc   := s.fieldAsc(k)
+        if nullPrefix := s.nullPrefix(colIdx, asc); nullPrefix != "" {
+            ordered = append(ordered, nullMarked(nullPrefix, value))
+        }
+        value  = encodeField(s.fieldTypes[colIdx], s.parsing(colIdx), s.invalidHigh(asc), value)
+        class := ""
+        if strict, padded := s.zeroPads[colIdx]; padded { class, value = zeroPadded(colIdx + 1, strict, value) }
+        if s.hashOrder { ordered = append(ordered, hashDigest(s.hashSeed, value)) }
+        if class != "" { ordered = append(ordered, class) }
+        ordered = append(ordered, value)
+    }
+    return ordered
+} //end func orderKey
+func hashDigest(seed uint64, value string) string {
+    return fmt.Sprintf("%016x", sipHash24(seed, 0, []byte(value)))
+} //end func hashDigest
+func sipHash24(k0, k1 uint64, msg []byte) uint64 {
+    //SipHash-2-4 per Aumasson & Bernstein, "SipHash: a fast short-input PRF"
+    v0, v1 := k0 ^ 0x736f6d6570736575, k1 ^ 0x646f72616e646f6d
+    v2, v3 := k0 ^ 0x6c7967656e657261, k1 ^ 0x7465646279746573
+    round  := func() {
+        v0 += v1; v1 = bits.RotateLeft64(v1, 13); v1 ^= v0; v0 = bits.RotateLeft64(v0, 32)
+        v2 += v3; v3 = bits.RotateLeft64(v3, 16); v3 ^= v2
+        v0 += v3; v3 = bits.RotateLeft64(v3, 21); v3 ^= v0
+        v2 += v1; v1 = bits.RotateLeft64(v1, 17); v1 ^= v2; v2 = bits.RotateLeft64(v2, 32)
+    }
+    length := len(msg)
+    for ; len(msg) >= 8; msg = msg[8:] {
+        m   := binary.LittleEndian.Uint64(msg)
+        v3 ^= m
+        round(); round()
+        v0 ^= m
+    }
+    var last [8]byte
+    copy(last[:], msg)
+    last[7] = byte(length)
+    m      := binary.LittleEndian.Uint64(last[:])
+    v3     ^= m
+    round(); round()
+    v0     ^= m
+    v2     ^= 0xff
+    round(); round(); round(); round()
+    return v0 ^ v1 ^ v2 ^ v3
+} //end func sipHash24
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file hashorder.go
@@ -0,0 +1,53 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     hashorder_test.go
+ * Overview:
+ *     tests of the ordering of the records on a seeded hash of their index fields.
+ * Functions:
+ *     TestHashOrder(t *testing.T)
+ *         Checks that the order depends on the seed alone, and keeps equal index fields adjacent and in input order.
+ * History:
+ *     v1.14.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "fmt"
+    "sort"
+    "strings"
+    "testing"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func TestHashOrder(t *testing.T) {
+    //50 keys of 3 records each, interleaved, the second field giving the input order of the records of a key
+    var input strings.Builder
+    for pass := 0; pass < 3; pass++ {
+        for k := 0; k < 50; k++ { fmt.Fprintf(&input, "k%02d\t%d\n", k, pass) }
+    }
+    hashSort := func(seed uint64, keysPerSort int) string {
+        return string(sortBytes(t, input.String(), WithFields("1"), WithSeparator("\t"), WithKeysPerSort(keysPerSort),
+                                WithHashOrder(seed)))
+    }
+    output := hashSort(7, 1000)
+    if again := hashSort(7, 16); again != output { t.Error("same seed, other output once the runs are merged") }
+    if other := hashSort(8, 1000); other == output { t.Error("other seed, same output") }
+    //Each key's records adjacent and in input order, the keys out of their value order
+    records := strings.SplitAfter(output, "\n")
+    records  = records[:len(records) - 1]
+    if len(records) != 150 { t.Fatalf("%d records output, expected 150", len(records)) }
+    var keys []string
+    for k := 0; k < len(records); k += 3 {
+        key := strings.SplitN(records[k], "\t", 2)[0]
+        for pass := 0; pass < 3; pass++ {
+            if expected := fmt.Sprintf("%s\t%d\n", key, pass); records[k + pass] != expected {
+                t.Fatalf("record %d: %q, expected %q", k + pass, records[k + pass], expected)
+            }
+        }
+        keys = append(keys, key)
+    }
+    if sort.StringsAreSorted(keys) { t.Error("keys output in their value order") }
+} //end func TestHashOrder
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file hashorder_test.go
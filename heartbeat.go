@@ -0,0 +1,95 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     heartbeat.go
+ * Overview:
+ *     periodic "still alive" log lines of a sort with its cumulative counters, logged whenever it has reported no progress
+ *     for an interval, so that log-based alerting can tell a long silent phase from a hung process.
+ * Functions:
+ *     WithHeartbeat(interval time.Duration) Option
+ *         Option logging a heartbeat line whenever a sort has reported no progress for an interval.
+ * History:
+ *     v1.99.0 - October 15, 2026 - Original release.
+ *     v1.100.0 - October 15, 2026 - Covers SortOffsets.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "log"
+    "sync/atomic"
+    "time"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func WithHeartbeat(interval time.Duration) Option {
+/*         Purpose : Logs a heartbeat line whenever a sort has reported no progress for an interval.
+ *       Arguments : interval = the longest silence of a sort, 0 for no heartbeat, the default.
+ *         Returns : The option.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : None.
+ *         Remarks : A goroutine started with the sort logs a line through the standard logger of package log, so that
+ *                   log.SetOutput, log.SetFlags and log.SetPrefix route and stamp it, e.g. "mergesort: heartbeat - phase key
+ *                   generation, 120000 records read, 0 records output, 5242880 temporary bytes written, 3 temporary files
+ *                   present holding 2097152 bytes, elapsed 1m0s". The phase is the last one started as named by
+ *                   SortStats.Phases, "starting" before the first, and the counters are cumulative from the start of the
+ *                   sort. A line is logged once the interval has passed since the start of the sort, its last progress
+ *                   report or its last heartbeat, whichever is latest, the progress reports being the calls of the callbacks
+ *                   of WithReadProgress and WithMergeProgress and the verbose echoes of the progress of the reads and merges.
+ *                   The goroutine is stopped, and has returned, before the sort returns, whether it completes, fails or is
+ *                   cancelled. The heartbeat covers the sorts of files, i.e. the Sort family, Run, RunFS, SortAndReduce,
+ *                   SortChan, SortOffsets and Pool.Sort, the latter from the time its sort leaves the queue.
+ *         History : v1.99.0 - October 15, 2026 - Original release.
+ *                   v1.100.0 - October 15, 2026 - Covers SortOffsets.
+ */
+    return func(s *Sorter) { s.heartbeat = interval }
+} //end func WithHeartbeat
+//Private ----------------------------------------------------------------------------------------------------------------------
+func (r *sortRun) startHeartbeat() func() {
+    //Starts the goroutine logging the heartbeat of the run, returning the function stopping it and waiting for its return;
+    //does nothing without a heartbeat
+    if r.heartbeat == 0 { return func() {} }
+    stop, stopped := make(chan struct{}), make(chan struct{})
+    stamp(&r.counters.spoke)
+    go func() {
+        defer close(stopped)
+        timer := time.NewTimer(r.heartbeat)
+        defer timer.Stop()
+        for {
+            select {
+                case <-stop:
+                    return
+                case <-timer.C:
+            }
+            quiet := time.Since(time.Unix(0, atomic.LoadInt64(&r.counters.spoke)))
+            if quiet >= r.heartbeat {
+                r.beat()
+                quiet = 0
+            }
+            timer.Reset(r.heartbeat - quiet)                      //fires as the silence reaches the interval
+        }
+    }()
+    return func() {
+        close(stop)
+        <-stopped
+    }
+} //end func startHeartbeat
+func (r *sortRun) beat() {
+    //Logs the heartbeat line of the run with its counters so far
+    stamp(&r.counters.spoke)
+    r.counters.mutex.Lock()
+    phase := r.counters.phase
+    r.counters.mutex.Unlock()
+    if phase == "" { phase = "starting" }
+    bytes, files := r.space.present()
+    log.Printf("mergesort: heartbeat - phase %s, %d records read, %d records output, %d temporary bytes written, " +
+               "%d temporary files present holding %d bytes, elapsed %v", phase, atomic.LoadInt64(&r.counters.read),
+               atomic.LoadInt64(&r.counters.output), atomic.LoadInt64(&r.counters.tempBytes), files, bytes,
+               time.Since(r.start).Round(time.Millisecond))
+} //end func beat
+func stamp(at *int64) {
+    //Records the time of a progress report in a counter, if any
+    if at != nil { atomic.StoreInt64(at, time.Now().UnixNano()) }
+} //end func stamp
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file heartbeat.go
@@ -0,0 +1,63 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     intern.go
+ * Overview:
+ *     interning of the encoded index-field values of the composite keys, so that the values repeated throughout an input
+ *     are formatted once and share their bytes.
+ * History:
+ *     v1.54.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "container/list"
+)
+//Private ----------------------------------------------------------------------------------------------------------------------
+const(
+    _internEntries = 1024    //number of values of an index field kept by its cache
+    _internProbe   = 1 << 16 //number of lookups after which a field whose values seldom repeat is no longer cached
+)
+type keyCache struct {
+    entries map[string]*list.Element //cached pieces by raw field value
+    order   *list.List               //cached pieces, the most recently used first
+    lookups int                      //number of lookups so far
+    hits    int                      //number of lookups answered by the cache
+    bypass  bool                     //field whose values seldom repeat, no longer cached
+}
+type cachedPiece struct {
+    value string                     //raw field value
+    piece string                     //its part of the composite key
+}
+func newKeyCache() *keyCache {
+    return &keyCache{entries:map[string]*list.Element{}, order:list.New()}
+} //end func newKeyCache
+func (c *keyCache) piece(value string, build func(value string) string) string {
+    //Returns the part of the composite key of a raw field value, built only if the value was not used recently. The cache
+    //holds the _internEntries values used last, and is dropped if less than half of its first _internProbe lookups hit.
+    if c.bypass { return build(value) }
+    c.lookups++
+    elem, hit := c.entries[value]
+    if hit { c.hits++ }
+    if c.lookups == _internProbe && 2 * c.hits < c.lookups {       //probe over, whether its last lookup hit or not
+        c.entries, c.order, c.bypass = nil, nil, true
+        if hit { return elem.Value.(*cachedPiece).piece }
+        return build(value)
+    }
+    if hit {
+        c.order.MoveToFront(elem)
+        return elem.Value.(*cachedPiece).piece
+    }
+    piece := build(value)
+    if c.order.Len() == _internEntries {
+        oldest := c.order.Back()
+        delete(c.entries, oldest.Value.(*cachedPiece).value)
+        c.order.Remove(oldest)
+    }
+    value = string([]byte(value))                                 //not pinning the record it was split from
+    c.entries[value] = c.order.PushFront(&cachedPiece{value:value, piece:piece})
+    return piece
+} //end func piece
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file intern.go
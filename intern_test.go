@@ -0,0 +1,107 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     intern_test.go
+ * Overview:
+ *     tests and benchmarks of the interning of the index-field values of the composite keys, on values repeated throughout
+ *     the input or all distinct.
+ * Functions:
+ *     TestInternedKeys(t *testing.T)
+ *         Checks that the keys built through the caches equal those of a key builder used once per record.
+ *     BenchmarkCompositeKeys(b *testing.B)
+ *         Builds the keys of records of few or of distinct values, reporting the allocations per key.
+ * History:
+ *     v1.54.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "fmt"
+    "math/rand"
+    "testing"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func TestInternedKeys(t *testing.T) {
+    //Values repeated and distinct, past the probe of the caches and their eviction
+    rng     := rand.New(rand.NewSource(11))
+    records := make([]string, _internProbe + 4 * _internEntries)
+    for k := range records {
+        records[k] = fmt.Sprintf("%s\t%d\t%d\n", _regions[k % len(_regions)], rng.Intn(3 * _internEntries), rng.Int63())
+    }
+    for _, fields := range []string{"1,2", "2,3", "3,1"} {
+        newKeyFn := keyBuilder(t, records, WithFields(fields))
+        keyFn    := newKeyFn()
+        for k, record := range records {
+            if key, fresh := keyFn(record, int64(k), len(record)), newKeyFn()(record, int64(k), len(record)); key != fresh {
+                t.Fatalf("fields %s, record %d %q: interned key %q, expected %q", fields, k, record, key, fresh)
+            }
+        }
+    }
+    //The caches of the fields of few values kept, those of distinct values dropped after the probe
+    cache := newKeyCache()
+    build := func(value string) string { return "<" + value + ">" }
+    for k := 0; k < _internProbe; k++ { cache.piece(_regions[k % len(_regions)], build) }
+    if cache.bypass || cache.order.Len() != len(_regions) {
+        t.Errorf("cache of %d values: bypass %v, expected %d entries", len(_regions), cache.bypass, len(_regions))
+    }
+    cache = newKeyCache()
+    for k := 0; k < _internProbe; k++ { cache.piece(fmt.Sprint(k), build) }
+    if !cache.bypass { t.Error("cache of distinct values kept after the probe") }
+    cache = newKeyCache()                                           //the last lookup of the probe a hit
+    for k := 0; k < _internProbe - 1; k++ { cache.piece(fmt.Sprint(k), build) }
+    if piece := cache.piece(fmt.Sprint(_internProbe - 2), build); piece != fmt.Sprintf("<%d>", _internProbe - 2) {
+        t.Errorf("hit ending the probe returns %q", piece)
+    }
+    if !cache.bypass { t.Error("cache of distinct values kept after a probe ending on a hit") }
+    if piece := cache.piece("x", build); piece != "<x>" { t.Errorf("bypassed cache returns %q, expected <x>", piece) }
+} //end func TestInternedKeys
+func BenchmarkCompositeKeys(b *testing.B) {
+    //Sub-benchmarks "values=8" and "values=unique", keyed on two text fields, reporting the allocations per key
+    const numRecords = 200000
+    rng := rand.New(rand.NewSource(12))
+    for _, unique := range []bool{false, true} {
+        records := make([]string, numRecords)
+        for k := range records {
+            region, status := _regions[rng.Intn(len(_regions))], _statuses[rng.Intn(len(_statuses))]
+            if unique { region, status = fmt.Sprintf("r%d", rng.Int63()), fmt.Sprintf("s%d", rng.Int63()) }
+            records[k] = fmt.Sprintf("%s\t%s\t%d\n", region, status, rng.Int63())
+        }
+        name := "values=unique"
+        if !unique { name = fmt.Sprintf("values=%d", len(_regions)) }
+        b.Run(name, func(b *testing.B) {
+            newKeyFn := keyBuilder(b, records, WithFields("1,2"))
+            b.ReportAllocs()
+            b.ResetTimer()
+            for k := 0; k < b.N; k++ {
+                keyFn := newKeyFn()
+                for j, record := range records { keyFn(record, int64(j), len(record)) }
+            }
+            b.StopTimer()
+            allocs := testing.AllocsPerRun(1, func() {
+                keyFn := newKeyFn()
+                for j, record := range records { keyFn(record, int64(j), len(record)) }
+            })
+            b.ReportMetric(allocs / numRecords, "allocs/key")
+        })
+    }
+} //end func BenchmarkCompositeKeys
+//Private ----------------------------------------------------------------------------------------------------------------------
+var(
+    _regions  = []string{"north", "south", "east", "west", "centre", "pacific", "atlantic", "arctic"}
+    _statuses = []string{"open", "closed", "pending", "void"}
+)
+func keyBuilder(t testing.TB, records []string, opts ...Option) func() func(string, int64, int) string {
+    //Returns the function making the key builders of a tab-separated sort of records, their widths measured on all of them
+    //as Sort does
+    s, err := NewSorter(append(opts, WithSeparator("\t"))...)
+    if err != nil { t.Fatal(err) }
+    r      := s.newRun()
+    widths := make([]float64, r.lastIndexField() + 1)
+    for _, record := range records { r.measureFields(widths, r.trimRecord(record)) }
+    return func() func(string, int64, int) string {
+        return makeCompositeKeyFn(r.keyFields(), r.sortSpecs(widths), 1)
+    }
+} //end func keyBuilder
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file intern_test.go
@@ -0,0 +1,91 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     iorate.go
+ * Overview:
+ *     cap on the I/O bandwidth of a sorter, a token bucket shared by the reads of its inputs, the reads and writes of its
+ *     temporary files and the writes of its outputs.
+ * Functions:
+ *     WithMaxIORate(bytesPerSec int64) Option
+ *         Option capping the bytes per second the sorter reads and writes.
+ * History:
+ *     v1.84.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "io"
+    "sync"
+    "time"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func WithMaxIORate(bytesPerSec int64) Option {
+/*         Purpose : Caps the bytes per second the sorter reads and writes.
+ *       Arguments : bytesPerSec = the cap, 0 for none, the default.
+ *         Returns : The option.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : None.
+ *         Remarks : The bytes read from the inputs and read from or written to the temporary files, and those written to
+ *                   the outputs, are drawn from one token bucket refilled at bytesPerSec and holding at most _ioBurst
+ *                   of them, so that the sort's total bandwidth stays under the cap, e.g. beside a latency-sensitive
+ *                   service on the same disk. Each transfer is made first and paid for after, the goroutine sleeping
+ *                   until the bucket covers it; over a second or more, the rate is thus within a few percent of the
+ *                   cap. The bucket is shared by all the runs of the sorter. The temporary files held in memory per
+ *                   WithInMemorySpillThreshold or on a MemStorage count too, as in SortStats.TempBytes, while an input
+ *                   is not mapped per WithMemoryMappedInput but read through the bucket, and the files of the run
+ *                   directories of GenerateRuns and MergeRuns, the reject files and the index files of ApplyIndex are
+ *                   not counted. Without the option, the I/O pays a nil check.
+ *         History : v1.84.0 - October 15, 2026 - Original release.
+ */
+    return func(s *Sorter) { s.ioRate = bytesPerSec }
+} //end func WithMaxIORate
+//Private ----------------------------------------------------------------------------------------------------------------------
+const _ioBurst = 20 * time.Millisecond //transfers of the bucket when full, in time at the capped rate
+type ioThrottle struct {
+    mutex   sync.Mutex
+    perByte float64                                               //nanoseconds of the cap per byte
+    due     time.Time                                             //time at which the bytes paid for are covered
+}
+type throttledInput struct {
+    inputFile
+    throttle *ioThrottle
+}
+type throttledInputAt struct {
+    *throttledInput
+    at io.ReaderAt                                                //the input, for its positioned reads
+}
+func newIOThrottle(bytesPerSec int64) *ioThrottle {
+    return &ioThrottle{perByte:float64(time.Second) / float64(bytesPerSec)}
+} //end func newIOThrottle
+func (t *ioThrottle) pay(n int) {
+    //Draws the bytes of a transfer from the bucket, sleeping until they are covered; does nothing without a cap
+    if t == nil || n <= 0 { return }
+    t.mutex.Lock()
+    now := time.Now()
+    if full := now.Add(-_ioBurst); t.due.Before(full) { t.due = full } //bucket full after an idle spell
+    t.due  = t.due.Add(time.Duration(float64(n) * t.perByte))
+    delay := t.due.Sub(now)
+    t.mutex.Unlock()
+    if delay > 0 { time.Sleep(delay) }
+} //end func pay
+func (r *sortRun) throttleInput(fhIn inputFile) inputFile {
+    //Wraps an input so that its reads are drawn from the bucket, keeping its positioned reads if any
+    if r.throttle == nil { return fhIn }
+    throttled := &throttledInput{inputFile:fhIn, throttle:r.throttle}
+    if at, ok := fhIn.(io.ReaderAt); ok { return &throttledInputAt{throttledInput:throttled, at:at} }
+    return throttled
+} //end func throttleInput
+func (f *throttledInput) Read(p []byte) (int, error) {
+    n, err := f.inputFile.Read(p)
+    f.throttle.pay(n)
+    return n, err
+} //end func Read
+func (f *throttledInputAt) ReadAt(p []byte, offset int64) (int, error) {
+    n, err := f.at.ReadAt(p, offset)
+    f.throttle.pay(n)
+    return n, err
+} //end func ReadAt
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file iorate.go
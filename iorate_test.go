@@ -0,0 +1,85 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     iorate_test.go
+ * Overview:
+ *     timing tests of the cap on the I/O bandwidth of a sorter, against caps small enough for a run of a second or two.
+ * Functions:
+ *     TestIOThrottle(t *testing.T)
+ *         Checks that concurrent transfers drawn from the bucket take the time of their bytes at the cap.
+ *     TestMaxIORate(t *testing.T)
+ *         Checks that a capped sort takes the time of the bytes it reads and writes at the cap.
+ * History:
+ *     v1.84.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "io/ioutil"
+    "math/rand"
+    "path/filepath"
+    "sync"
+    "testing"
+    "time"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func TestIOThrottle(t *testing.T) {
+    if testing.Short() { t.Skip("timing test skipped in short mode") }
+    const rate, goroutines, perGoroutine = 256 << 10, 4, 96 << 10 //1.5s of transfers at the cap
+    throttle := newIOThrottle(rate)
+    start    := time.Now()
+    var wg sync.WaitGroup
+    for k := 0; k < goroutines; k++ {
+        wg.Add(1)
+        go func(seed int64) {
+            defer wg.Done()
+            rng := rand.New(rand.NewSource(seed))
+            for left := perGoroutine; left > 0; {
+                n := 1 + rng.Intn(8 << 10)
+                if n > left { n = left }
+                throttle.pay(n)
+                left -= n
+            }
+        }(int64(k))
+    }
+    wg.Wait()
+    expected := time.Duration(float64(goroutines * perGoroutine) / rate * float64(time.Second)) - _ioBurst
+    checkRate(t, time.Since(start), expected)
+} //end func TestIOThrottle
+func TestMaxIORate(t *testing.T) {
+    if testing.Short() { t.Skip("timing test skipped in short mode") }
+    const rate = 384 << 10
+    dir    := t.TempDir()
+    input  := randomInput(rand.New(rand.NewSource(5)), 1500)
+    inFile := filepath.Join(dir, "in.txt")
+    if err := ioutil.WriteFile(inFile, []byte(input), 0644); err != nil { t.Fatal(err) }
+    var stats SortStats
+    sorter, err := NewSorter(WithFields("2,1"), WithKeysPerSort(200), WithTempDir(dir), WithMaxIORate(rate),
+                             WithStats(&stats))
+    if err != nil { t.Fatal(err) }
+    start := time.Now()
+    if err := sorter.Run(inFile, filepath.Join(dir, "out.txt")); err != nil { t.Fatal(err) }
+    elapsed := time.Since(start)
+    //Bytes paid for, from the time the bucket covers them, and at least those the phases read or wrote
+    sorter.throttle.mutex.Lock()
+    paid := sorter.throttle.due.Sub(start) + _ioBurst
+    sorter.throttle.mutex.Unlock()
+    var phases int64
+    for _, phase := range stats.Phases { phases += phase.Bytes }
+    if paidBytes := int64(float64(paid) / sorter.throttle.perByte); paidBytes < phases {
+        t.Errorf("%d bytes paid for, but the phases read or wrote %d", paidBytes, phases)
+    }
+    if paid < time.Second { t.Fatalf("only %v of transfers at the cap, too short to time", paid) }
+    checkRate(t, elapsed, paid - _ioBurst)
+} //end func TestMaxIORate
+//Private ----------------------------------------------------------------------------------------------------------------------
+func checkRate(t *testing.T, elapsed, expected time.Duration) {
+    //Fails the test unless the time elapsed is within 5% of the one expected at the cap
+    t.Helper()
+    if ratio := float64(elapsed) / float64(expected); ratio < 0.95 || ratio > 1.05 {
+        t.Errorf("took %v, expected %v at the cap (ratio %.3f)", elapsed, expected, ratio)
+    }
+} //end func checkRate
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file iorate_test.go
@@ -0,0 +1,290 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     join.go
+ * Overview:
+ *     streaming sort-merge equi-join of two text files sorted by the package.
+ * Functions:
+ *     Join(leftFile, rightFile, outFile string, leftFields, rightFields, sep string, joinType string, opts ...Option) error
+ *         Does a streaming sort-merge equi-join of two sorted text files.
+ *     WithJoinLayout(outColumns, outSep string) Option
+ *         Option setting the output columns and separator of Join.
+ * History:
+ *     v1.4.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "bufio"
+    "errors"
+    "fmt"
+    "io"
+    "os"
+    "strconv"
+    "strings"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func Join(leftFile, rightFile, outFile string, leftFields, rightFields, sep string, joinType string,
+          opts ...Option) (err error) {
+/*         Purpose : Does a streaming sort-merge equi-join of two sorted text files.
+ *       Arguments : leftFile    = path of the left file, sorted by the package on leftFields.
+ *                   rightFile   = path of the right file, sorted by the package on rightFields.
+ *                   outFile     = path of the file for the joined records.
+ *                   leftFields  = CSV of the left file's field numbers forming the join key, with the first field
+ *                                 referenced as 1.
+ *                   rightFields = CSV of the right file's field numbers forming the join key. It must hold as many fields
+ *                                 as leftFields.
+ *                   sep         = the field separator of both files.
+ *                   joinType    = "inner", "left" or "outer".
+ *                   opts        = options, see NewSorter. WithAscending gives the order of both files, ascending by
+ *                                 default, and WithJoinLayout the output columns and separator. The temporary storage
+ *                                 options apply to the spill files.
+ *         Returns : Any error encountered, including finding that an input is not sorted.
+ * Externals -  In : _joinGroupLimit
+ * Externals - Out : None.
+ *       Functions : NewSorter, catch, joinGroups, newSortedReader, parseColumns, parseOutColumns
+ *         Remarks : The keys are compared as Sort does, i.e. on values right-aligned to a common width, and the order of
+ *                   each file is checked as it is read. For many-to-many matches, both key groups are read alternately
+ *                   until one of them ends. That smaller group is then held in memory while the other is streamed. If
+ *                   both groups exceed _joinGroupLimit records, the left one is spilled to a temporary file of the
+ *                   sorter's temporary storage instead, counted against WithMaxTempSpace. Fields missing from an
+ *                   unmatched side are output as empty values.
+ *         History : v1.4.0 - October 15, 2026 - Original release.
+ */
+    defer catch(&err)
+    if leftFile == "" || rightFile == "" { return errors.New("mergesort: the input files were not specified") }
+    if outFile  == "" { return errors.New("mergesort: the output file was not specified") }
+    if joinType != "inner" && joinType != "left" && joinType != "outer" {
+        return errors.New("mergesort: the join type must be inner, left or outer")
+    }
+    leftIdxs, err := parseColumns(leftFields)
+    if err != nil { return fmt.Errorf("mergesort: left fields - %w", err) }
+    rightIdxs, err := parseColumns(rightFields)
+    if err != nil { return fmt.Errorf("mergesort: right fields - %w", err) }
+    if len(leftIdxs) != len(rightIdxs) { return errors.New("mergesort: the left and right keys differ in length") }
+    sorter, err := NewSorter(append(opts[:len(opts):len(opts)], WithFields(leftFields), WithSeparator(sep))...)
+    if err != nil { return err }
+    columns, err := parseOutColumns(sorter.joinColumns)
+    if err != nil { return err }
+    sortAsc, outSep := sorter.sortAsc, sorter.joinSep
+    if outSep == "" { outSep = sep }
+    run := sorter.newRun()
+
+    left, err := newSortedReader(leftFile, leftIdxs, sep, sortAsc)
+    if err != nil { return err }
+    defer left.close()
+    right, err := newSortedReader(rightFile, rightIdxs, sep, sortAsc)
+    if err != nil { return err }
+    defer right.close()
+    fhOut, err := os.Create(outFile)
+    if err != nil { return err }
+    defer func() {
+        if errClose := fhOut.Close(); err == nil { err = errClose }
+    }()
+    writer := bufio.NewWriter(fhOut)
+    emit   := func(leftRecord, rightRecord []string) {
+        fmt.Fprintln(writer, strings.Join(joinColumns(columns, leftRecord, rightRecord, left.numFields, right.numFields),
+                                          outSep))
+    }
+    //Walk both files in key order
+    for left.err == nil && right.err == nil && (!left.eof || !right.eof) {
+        var cmp int
+        switch {
+            case left.eof:  cmp = 1
+            case right.eof: cmp = -1
+            default:
+                cmp = compareKeys(left.key, right.key)
+                if !sortAsc { cmp = -cmp }
+        }
+        switch {
+            case cmp < 0:
+                if joinType != "inner" { emit(left.fields, nil) }
+                left.advance()
+            case cmp > 0:
+                if joinType == "outer" { emit(nil, right.fields) }
+                right.advance()
+            default:
+                if err = run.joinGroups(left, right, emit); err != nil { return }
+        }
+    }
+    if left.err  != nil { return left.err }
+    if right.err != nil { return right.err }
+    if err = writer.Flush(); err != nil { return }
+    return fhOut.Sync()
+} //end func Join
+func WithJoinLayout(outColumns, outSep string) Option {
+/*         Purpose : Sets the output columns and separator of Join.
+ *       Arguments : outColumns = CSV of the output columns, each being "L" or "R" followed by a field number of the left or
+ *                                right file respectively, e.g. "L1,L3,R2". If empty, all the left fields are followed by all
+ *                                the right fields.
+ *                   outSep     = the output field separator. If empty, the separator of the input files is used.
+ *         Returns : The option.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : None.
+ *         Remarks : The option has no effect on the other functions.
+ *         History : v1.4.0 - October 15, 2026 - Original release.
+ */
+    return func(s *Sorter) { s.joinColumns, s.joinSep = outColumns, outSep }
+} //end func WithJoinLayout
+//Private ----------------------------------------------------------------------------------------------------------------------
+const _joinGroupLimit = 100000 //maximum number of records of a key group held in memory by Join
+////Sorted-file reader
+type sortedReader struct {
+    file      string
+    fh        *os.File
+    reader    *bufio.Reader
+    colIdxs   []int
+    sep       string
+    sortAsc   bool
+    offset    int64    //offset of the current record
+    next      int64    //offset of the next record
+    record    string   //current record, including its terminator
+    fields    []string //fields of the current record
+    key       []string //index-field values of the current record
+    numFields int      //number of fields of the first record
+    eof       bool
+    err       error
+}
+func newSortedReader(file string, colIdxs []int, sep string, sortAsc bool) (*sortedReader, error) {
+    fh, err := os.Open(file)
+    if err != nil { return nil, err }
+    sr := &sortedReader{file:file, fh:fh, reader:bufio.NewReader(fh), colIdxs:colIdxs, sep:sep, sortAsc:sortAsc}
+    sr.advance()
+    if sr.err != nil {
+        fh.Close()
+        return nil, sr.err
+    }
+    sr.numFields = len(sr.fields)
+    return sr, nil
+} //end func newSortedReader
+func (sr *sortedReader) advance() {
+    //Reads the next non-blank record, checking that it does not precede the current one
+    prevKey := sr.key
+    for !sr.eof {
+        record, err := sr.reader.ReadString('\n')
+        if err != nil && err != io.EOF {
+            sr.err, sr.eof = err, true
+            return
+        }
+        sr.offset  = sr.next
+        sr.next   += int64(len(record))
+        if err == io.EOF && len(record) == 0 {
+            sr.record, sr.fields, sr.key, sr.eof = "", nil, nil, true
+            return
+        }
+        if trimmed := strings.Trim(record, " \r\n"); len(trimmed) > 0 {
+            sr.record = record
+            sr.fields = strings.Split(trimmed, sr.sep)
+            sr.key    = make([]string, len(sr.colIdxs))
+            for k, colIdx := range sr.colIdxs {
+                if colIdx < len(sr.fields) { sr.key[k] = sr.fields[colIdx] }
+            }
+            if prevKey != nil && precedes(compareKeys(sr.key, prevKey), sr.sortAsc) {
+                sr.err, sr.eof = fmt.Errorf("mergesort: %s is not sorted at offset %d", sr.file, sr.offset), true
+            }
+            return
+        }
+    }
+} //end func advance
+func (sr *sortedReader) close() { sr.fh.Close() }
+////Join helpers
+type outColumn struct {
+    right  bool
+    colIdx int
+}
+func parseOutColumns(outColumns string) ([]outColumn, error) {
+    if outColumns == "" { return nil, nil }
+    columns := []outColumn{}
+    for _, v := range strings.Split(outColumns, ",") {
+        v = strings.TrimSpace(v)
+        if len(v) < 2 || (v[0] != 'L' && v[0] != 'R') {
+            return nil, errors.New("mergesort: the output column " + strconv.Quote(v) + " is not of the form L<n> or R<n>")
+        }
+        colNum, err := strconv.Atoi(v[1:])
+        if err != nil || colNum < 1 {
+            return nil, errors.New("mergesort: the output column " + strconv.Quote(v) + " is not of the form L<n> or R<n>")
+        }
+        columns = append(columns, outColumn{right:v[0] == 'R', colIdx:colNum - 1})
+    }
+    return columns, nil
+} //end func parseOutColumns
+func joinColumns(columns []outColumn, leftRecord, rightRecord []string, leftNum, rightNum int) []string {
+    field := func(fields []string, colIdx int) string {
+        if colIdx < len(fields) { return fields[colIdx] }
+        return ""
+    }
+    values := []string{}
+    if columns == nil {
+        for k := 0; k < leftNum || k < len(leftRecord); k++ { values = append(values, field(leftRecord, k)) }
+        for k := 0; k < rightNum || k < len(rightRecord); k++ { values = append(values, field(rightRecord, k)) }
+        return values
+    }
+    for _, v := range columns {
+        if v.right { values = append(values, field(rightRecord, v.colIdx)) } else { values = append(values, field(leftRecord, v.colIdx)) }
+    }
+    return values
+} //end func joinColumns
+func (r *sortRun) joinGroups(left, right *sortedReader, emit func(leftRecord, rightRecord []string)) error {
+    var(
+        key         = left.key
+        inGroup     = func(sr *sortedReader) bool { return !sr.eof && compareKeys(sr.key, key) == 0 }
+        leftGroup   = [][]string{}
+        rightGroup  = [][]string{}
+    )
+    //Read both groups alternately until one of them ends or both reach the memory limit
+    for inGroup(left) && inGroup(right) && (len(leftGroup) < _joinGroupLimit || len(rightGroup) < _joinGroupLimit) {
+        if len(leftGroup) < _joinGroupLimit {
+            leftGroup = append(leftGroup, left.fields)
+            left.advance()
+        }
+        if len(rightGroup) < _joinGroupLimit {
+            rightGroup = append(rightGroup, right.fields)
+            right.advance()
+        }
+    }
+    switch {
+        case !inGroup(left):                                      //left group is complete: stream the right one
+            for _, r := range rightGroup {
+                for _, l := range leftGroup { emit(l, r) }
+            }
+            for ; inGroup(right); right.advance() {
+                for _, l := range leftGroup { emit(l, right.fields) }
+            }
+        case !inGroup(right):                                     //right group is complete: stream the left one
+            for _, l := range leftGroup {
+                for _, r := range rightGroup { emit(l, r) }
+            }
+            for ; inGroup(left); left.advance() {
+                for _, r := range rightGroup { emit(left.fields, r) }
+            }
+        default:                                                  //both groups are too large: spill the left one
+            fhSpill, spillFile := r.createTemp("join_")
+            defer r.removeTemp(spillFile)
+            writer := bufio.NewWriter(fhSpill)
+            for _, l := range leftGroup { fmt.Fprintln(writer, strings.Join(l, left.sep)) }
+            for ; inGroup(left); left.advance() { fmt.Fprintln(writer, strings.Join(left.fields, left.sep)) }
+            err := writer.Flush()
+            if errClose := fhSpill.Close(); err == nil { err = errClose }
+            if err != nil { return err }
+            fhSpill = r.openTemp(spillFile)
+            defer fhSpill.Close()
+            replay := func(r []string) error {
+                if _, err := fhSpill.Seek(0, 0); err != nil { return err }
+                scanner := bufio.NewScanner(fhSpill)
+                scanner.Buffer(nil, bufio.MaxScanTokenSize << 8)
+                for scanner.Scan() { emit(strings.Split(scanner.Text(), left.sep), r) }
+                return scanner.Err()
+            }
+            for _, r := range rightGroup {
+                if err = replay(r); err != nil { return err }
+            }
+            for ; inGroup(right); right.advance() {
+                if err = replay(right.fields); err != nil { return err }
+            }
+    }
+    return nil
+} //end func joinGroups
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file join.go
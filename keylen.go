@@ -0,0 +1,48 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     keylen.go
+ * Overview:
+ *     truncation of index fields to their leading characters before they enter the composite keys.
+ * Functions:
+ *     WithMaxKeyLen(column, maxLen int) Option
+ *         Option comparing an index field on its first maxLen characters only.
+ * History:
+ *     v1.47.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func WithMaxKeyLen(column, maxLen int) Option {
+/*         Purpose : Compares an index field on its first maxLen characters only.
+ *       Arguments : column = number of the field, the first being 1. Must be one of the index fields.
+ *                   maxLen = number of characters kept. Must be at least 1.
+ *         Returns : The option.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : None.
+ *         Remarks : The values are cut on a character boundary, after any normalization and folding, both in the width
+ *                   scan and in the composite keys, so that a few long outliers no longer widen every key of the input.
+ *                   Records equal over the kept characters keep their input order, as do all equal ones. The records
+ *                   are output unchanged. A later option for the same field replaces an earlier one.
+ *         History : v1.47.0 - October 15, 2026 - Original release.
+ */
+    return func(s *Sorter) {
+        if s.keyLens == nil { s.keyLens = map[int]int{} }
+        s.keyLens[column - 1] = maxLen
+    }
+} //end func WithMaxKeyLen
+//Private ----------------------------------------------------------------------------------------------------------------------
+func truncateValue(value string, maxLen int) string {
+    //Returns the first maxLen characters of a value
+    if len(value) <= maxLen { return value }
+    count := 0
+    for k := range value {
+        if count == maxLen { return value[:k] }
+        count++
+    }
+    return value
+} //end func truncateValue
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file keylen.go
@@ -0,0 +1,138 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     keyspec.go
+ * Overview:
+ *     typed specification of the index fields, into which the CSV of WithFields is parsed, with a direction per field.
+ * Functions:
+ *     CompareRecords(a, b string, specs []KeySpec, sep string) (int, error)
+ *         Compares two records on their index fields as Sort orders them.
+ *     ParseFieldSpec(spec string) ([]KeySpec, error)
+ *         Parses a CSV of field numbers into key specifications.
+ *     WithKeys(keys ...KeySpec) Option
+ *         Option setting the index fields from their specifications.
+ * Types:
+ *     KeySpec
+ *         Specification of an index field.
+ * History:
+ *     v1.71.0 - October 15, 2026 - Original release.
+ *     v1.94.0 - October 15, 2026 - Added CompareRecords & ParseFieldSpec.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "fmt"
+    "strings"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+type KeySpec struct {
+    Field int                                                     //number of the field, the first being 1
+    Desc  bool                                                    //field ordered against the sort direction
+    Type  FieldType                                               //type of the field, FieldText leaving it to WithFieldType
+}
+
+func CompareRecords(a, b string, specs []KeySpec, sep string) (order int, err error) {
+/*         Purpose : Compares two records on their index fields as an ascending Sort orders them.
+ *       Arguments : a, b  = the records, with or without their line feeds.
+ *                   specs = the specifications of the index fields, e.g. as parsed by ParseFieldSpec.
+ *                   sep   = the field separator, which may not be "auto".
+ *         Returns : -1 if a precedes b, 1 if it follows it, 0 if their index fields are equal, and any error in the
+ *                   specifications or the separator.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : catch, halt, keyIndexPart, makeCompositeKeyFn, NewSorter
+ *         Remarks : The records are trimmed, split and keyed by the very functions Sort uses, the widths of their index
+ *                   fields being measured on the two of them, which orders them as the widths measured on a whole file
+ *                   would. Records with equal index fields compare as 0, Sort keeping them in input order, and a
+ *                   descending sort reverses the order of all others. The Desc and Type of each spec apply, the typed
+ *                   fields being parsed with the default settings. Being pure and deterministic, the function suits
+ *                   property tests and fuzzing, e.g. of its antisymmetry and transitivity.
+ *         History : v1.94.0 - October 15, 2026 - Original release.
+ */
+    defer catch(&err)
+    if sep == _autoSeparator { halt("the separator of two records cannot be detected") }
+    s, err := NewSorter(WithKeys(specs...), WithSeparator(sep))
+    if err != nil { return 0, err }
+    r       := s.newRun()
+    records := []string{r.trimRecord(a), r.trimRecord(b)}
+    widths  := make([]float64, r.lastIndexField() + 1)
+    for _, record := range records { r.measureFields(widths, record) }
+    compositeKeyFn := makeCompositeKeyFn(r.keyFields(), r.sortSpecs(widths), 1)
+    return strings.Compare(keyIndexPart(compositeKeyFn(records[0], 0, 0)), keyIndexPart(compositeKeyFn(records[1], 0, 0))),
+           nil
+} //end func CompareRecords
+func ParseFieldSpec(spec string) (keys []KeySpec, err error) {
+/*         Purpose : Parses a CSV of field numbers into key specifications.
+ *       Arguments : spec = the CSV of field numbers, ordered as primary, secondary, etc., with the first field referenced as
+ *                          1, e.g. "7,2".
+ *         Returns : The key specifications, ascending and untyped, and any error matching ErrBadFieldSpec.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : catch, haltKind, parseColumns
+ *         Remarks : This is the parser NewSorter applies to WithFields and the positional usingFields of Sort, so that
+ *                   WithFields(spec) and WithKeys(ParseFieldSpec(spec)) are the same. Blanks around the numbers are
+ *                   ignored; an empty spec, an empty item or a number below 1 is an error.
+ *         History : v1.94.0 - October 15, 2026 - Original release.
+ */
+    defer catch(&err)
+    colIdxs, err := parseColumns(spec)
+    if err != nil { haltKind(ErrBadFieldSpec, err.Error(), nil) }
+    keys = make([]KeySpec, len(colIdxs))
+    for k, colIdx := range colIdxs { keys[k].Field = colIdx + 1 }
+    return keys, nil
+} //end func ParseFieldSpec
+func WithKeys(keys ...KeySpec) Option {
+/*         Purpose : Sets the index fields from their specifications.
+ *       Arguments : keys = the specifications of the index fields, ordered as primary, secondary, etc.
+ *         Returns : The option.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : None.
+ *         Remarks : This is the typed form of WithFields, which is parsed into key specifications, so that
+ *                   WithFields("7,2") and WithKeys(KeySpec{Field:7}, KeySpec{Field:2}) are the same; the later of the
+ *                   two options applies. A field with Desc set is ordered against the sort direction, i.e. descending
+ *                   in an ascending sort and ascending in a descending one, its empty and invalid values being placed
+ *                   per its own direction. Its part of the composite key is stored as the complement of its bytes in
+ *                   hex, twice its length plus one. A Type other than FieldText types the field as WithFieldType does.
+ *                   Errors in the specifications are reported by NewSorter with their index in keys, e.g. "keys[1]".
+ *         History : v1.71.0 - October 15, 2026 - Original release.
+ */
+    return func(s *Sorter) { s.keys, s.usingFields = append([]KeySpec(nil), keys...), "" }
+} //end func WithKeys
+//Private ----------------------------------------------------------------------------------------------------------------------
+const _descendingEnd = "~" //terminator of the descending parts of the composite keys, following every hex digit
+func (s *Sorter) checkKeys() {
+    //Halts on an invalid key spec, naming its index, then derives the index columns & field types from the key specs
+    s.colIdxs = make([]int, len(s.keys))
+    for k, key := range s.keys {
+        if key.Field < 1 {
+            haltKind(ErrBadFieldSpec, fmt.Sprintf("keys[%d]: the field number %d must be at least 1", k, key.Field), nil)
+        }
+        if key.Type < FieldText || key.Type > FieldBoolean {
+            haltKind(ErrBadFieldSpec, fmt.Sprintf("keys[%d]: the type of field %d is unknown", k, key.Field), nil)
+        }
+        s.colIdxs[k] = key.Field - 1
+        if key.Type == FieldText { continue }
+        if kind, typed := s.fieldTypes[key.Field - 1]; typed && kind != key.Type {
+            haltKind(ErrBadFieldSpec, fmt.Sprintf("keys[%d]: field %d is given another type elsewhere", k, key.Field), nil)
+        }
+        if s.fieldTypes == nil { s.fieldTypes = map[int]FieldType{} }
+        s.fieldTypes[key.Field - 1] = key.Type
+    }
+} //end func checkKeys
+func (s *Sorter) fieldAsc(k int) bool {
+    //Returns whether the k-th index field is ordered in ascending order
+    return s.sortAsc != (k < len(s.keys) && s.keys[k].Desc)
+} //end func fieldAsc
+func descending(part string) string {
+    //Returns the part of a composite key ordering its values in reverse, i.e. the hex digits of the complements of its bytes
+    //followed by a terminator, so that a part that is a prefix of another now follows it
+    var reversed strings.Builder
+    reversed.Grow(2 * len(part) + len(_descendingEnd))
+    for k := 0; k < len(part); k++ { fmt.Fprintf(&reversed, "%02x", ^part[k]) }
+    reversed.WriteString(_descendingEnd)
+    return reversed.String()
+} //end func descending
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file keyspec.go
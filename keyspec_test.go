@@ -0,0 +1,117 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     keyspec_test.go
+ * Overview:
+ *     fuzz targets of the parsing of the field specifications and of the ordering of records by CompareRecords.
+ * Functions:
+ *     FuzzParseFieldSpec(f *testing.F)
+ *         Checks that the specs parsed are valid, round-trip and agree with WithFields.
+ *     FuzzCompareRecords(f *testing.F)
+ *         Checks that CompareRecords is antisymmetric and transitive, and agrees with the order of the composite keys.
+ * History:
+ *     v1.94.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "errors"
+    "strconv"
+    "strings"
+    "testing"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func FuzzParseFieldSpec(f *testing.F) {
+    for _, seed := range []string{"1", "7,2", " 3 , 1 ", "", ",", "0", "-1", "1,,2", "x", "2147483648"} { f.Add(seed) }
+    f.Fuzz(func(t *testing.T, spec string) {
+        keys, err := ParseFieldSpec(spec)
+        _, errSorter := NewSorter(WithFields(spec))
+        if err != nil {
+            if !errors.Is(err, ErrBadFieldSpec) {
+                t.Fatalf("ParseFieldSpec(%q): error %v does not match ErrBadFieldSpec", spec, err)
+            }
+            if errSorter == nil { t.Fatalf("ParseFieldSpec(%q) fails with %v but WithFields is accepted", spec, err) }
+            return
+        }
+        if len(keys) == 0 { t.Fatalf("ParseFieldSpec(%q): no key and no error", spec) }
+        fields := make([]string, len(keys))
+        for k, key := range keys {
+            if key.Field < 1 || key.Desc || key.Type != FieldText { t.Fatalf("ParseFieldSpec(%q): invalid key %+v", spec, key) }
+            fields[k] = strconv.Itoa(key.Field)
+        }
+        again, err := ParseFieldSpec(strings.Join(fields, ","))
+        if err != nil || len(again) != len(keys) {
+            t.Fatalf("ParseFieldSpec(%q): %v does not round-trip (%v)", spec, keys, err)
+        }
+        for k := range keys {
+            if again[k] != keys[k] { t.Fatalf("ParseFieldSpec(%q): %v round-trips to %v", spec, keys, again) }
+        }
+        if errSorter != nil { t.Fatalf("ParseFieldSpec(%q) succeeds but WithFields fails with %v", spec, errSorter) }
+    })
+} //end func FuzzParseFieldSpec
+func FuzzCompareRecords(f *testing.F) {
+    f.Add("b\t2\tx", "a\t10\ty", "b\t1\tz", "1", false, false)
+    f.Add("b\t2\tx", "a\t10\ty", "b\t1\tz", "2,1", true, true)
+    f.Add("10\t", "9", "-3.5\tq", "1", false, true)
+    f.Add("abc", "ab", "abcd", "1", true, false)
+    f.Add(" x\ty", "x\ty ", "\tx", "2,1", false, false)
+    f.Fuzz(func(t *testing.T, a, b, c, spec string, desc, numeric bool) {
+        specs, err := ParseFieldSpec(spec)
+        if err != nil || len(specs) > 4 { return }
+        for k := range specs {
+            if specs[k].Field > 4 { return }                          //keeps the records' own fields in play
+            specs[k].Desc = desc && k % 2 == 0
+            if numeric && k == 0 { specs[k].Type = FieldNumeric }
+        }
+        records := []string{a, b, c}
+        for _, record := range records {
+            if strings.ContainsAny(record, "\r\n" + _asciiGS) { return }
+        }
+        //Pairwise orders, each measured on its own two records as CompareRecords does
+        order := [3][3]int{}
+        for i := range records {
+            for j := range records {
+                if order[i][j], err = CompareRecords(records[i], records[j], specs, "\t"); err != nil {
+                    t.Skipf("CompareRecords(%q, %q): %v", records[i], records[j], err)
+                }
+            }
+        }
+        for i := range records {
+            if order[i][i] != 0 { t.Fatalf("CompareRecords(%q, %q) = %d", records[i], records[i], order[i][i]) }
+            for j := range records {
+                if order[i][j] != -order[j][i] {
+                    t.Fatalf("not antisymmetric: %q vs %q = %d, reversed = %d", records[i], records[j], order[i][j],
+                             order[j][i])
+                }
+                for k := range records {
+                    if order[i][j] <= 0 && order[j][k] <= 0 && order[i][k] > 0 {
+                        t.Fatalf("not transitive: %q <= %q <= %q but %q > %q", records[i], records[j], records[k],
+                                 records[i], records[k])
+                    }
+                }
+            }
+        }
+        //Order of the composite keys of the three records, their widths measured on all of them as Sort does
+        s, err := NewSorter(WithKeys(specs...), WithSeparator("\t"))
+        if err != nil { t.Fatal(err) }
+        r      := s.newRun()
+        widths := make([]float64, r.lastIndexField() + 1)
+        for k := range records {
+            records[k] = r.trimRecord(records[k])
+            r.measureFields(widths, records[k])
+        }
+        keyFn := makeCompositeKeyFn(r.keyFields(), r.sortSpecs(widths), 1)
+        for i := range records {
+            for j := range records {
+                encoded := strings.Compare(keyIndexPart(keyFn(records[i], 0, 0)), keyIndexPart(keyFn(records[j], 0, 0)))
+                if encoded != order[i][j] {
+                    t.Fatalf("keys of %q and %q compare as %d, CompareRecords as %d", records[i], records[j], encoded,
+                             order[i][j])
+                }
+            }
+        }
+    })
+} //end func FuzzCompareRecords
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file keyspec_test.go
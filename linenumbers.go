@@ -0,0 +1,53 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     linenumbers.go
+ * Overview:
+ *     tagging of the output records with the line numbers they occupied in the input.
+ * Functions:
+ *     WithOriginalLineNumbers(prepend bool, sep string) Option
+ *         Option adding to each output record its 1-based line number in the input.
+ * History:
+ *     v1.17.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "strconv"
+    "strings"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func WithOriginalLineNumbers(prepend bool, sep string) Option {
+/*         Purpose : Adds to each output record its 1-based line number in the input.
+ *       Arguments : prepend = boolean flag for placing the number before the record's fields. If false, it is appended.
+ *                   sep     = separator between the number and the record. If empty, the field separator is used.
+ *         Returns : The option.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : None.
+ *         Remarks : The lines are counted during key generation, blank ones included, so that the numbers match those
+ *                   an editor shows for the input. Each number travels as a fourth component of its composite key. The
+ *                   records passed to the reducer of SortAndReduce are not tagged.
+ *         History : v1.17.0 - October 15, 2026 - Original release.
+ */
+    return func(s *Sorter) { s.lineNumbers, s.lineNumPre, s.lineNumSep = true, prepend, sep }
+} //end func WithOriginalLineNumbers
+//Private ----------------------------------------------------------------------------------------------------------------------
+func (r *sortRun) numberKey(key string, lineNum int) string {
+    //Appends the line number to a composite key when requested
+    if !r.lineNumbers { return key }
+    return key + _asciiGS + strconv.Itoa(lineNum)
+} //end func numberKey
+func (r *sortRun) numberRecord(key, record string) string {
+    //Adds the line number carried by its key to a record, before its terminator if appended
+    parts := strings.Split(key, _asciiGS)
+    if !r.lineNumbers || len(parts) < 4 { return record }
+    sep   := r.lineNumSep
+    if sep == "" { sep = r.sep }
+    body  := strings.TrimRight(record, "\r\n")
+    if r.lineNumPre { return parts[3] + sep + record }
+    return body + sep + parts[3] + record[len(body):]
+} //end func numberRecord
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file linenumbers.go
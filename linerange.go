@@ -0,0 +1,59 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     linerange.go
+ * Overview:
+ *     restriction of a sort to a range of input lines, the lines outside it being copied verbatim.
+ * Functions:
+ *     WithLineRange(from, to int64) Option
+ *         Option sorting only the input lines from..to.
+ * History:
+ *     v1.18.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "io"
+    "math"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func WithLineRange(from, to int64) Option {
+/*         Purpose : Sorts only the input lines from..to, both 1-based and inclusive.
+ *       Arguments : from = number of the first line of the range. Must be at least 1.
+ *                   to   = number of the last line of the range. If less than from, the range is empty.
+ *         Returns : The option.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : None.
+ *         Remarks : The lines before the range are copied verbatim to the output ahead of the sorted ones and the lines
+ *                   after it verbatim behind them, blank ones included. Lines are counted as WithOriginalLineNumbers
+ *                   does. A range extending past the end of the input stops there; one starting past it, or an empty
+ *                   one, leaves the input unchanged. Only the records of the range are keyed, so the field widths,
+ *                   the statistics and the index of SortIndex are theirs alone.
+ *         History : v1.18.0 - October 15, 2026 - Original release.
+ */
+    return func(s *Sorter) { s.lineRange, s.lineFrom, s.lineTo = true, from, to }
+} //end func WithLineRange
+//Private ----------------------------------------------------------------------------------------------------------------------
+func (s *Sorter) inRange(lineNum int) bool {
+    //Returns true if the line takes part in the sort
+    return !s.lineRange || (int64(lineNum) >= s.lineFrom && int64(lineNum) <= s.lineTo)
+} //end func inRange
+func (r *sortRun) trackRange(lineNum int, recordEnd int64) {
+    //Records the offsets at which the range starts and ends as the lines are keyed
+    if !r.lineRange { return }
+    r.ranged = true
+    if int64(lineNum) <  r.lineFrom { r.rangeStart = recordEnd }
+    if int64(lineNum) <= r.lineTo   { r.rangeEnd   = recordEnd }
+    if r.rangeEnd < r.rangeStart { r.rangeEnd = r.rangeStart }
+} //end func trackRange
+func (r *sortRun) copyOutside(fhIn io.ReaderAt, fhOut io.Writer, before bool) {
+    //Copies the lines preceding the range, or those following it, verbatim to the output
+    if !r.ranged { return }
+    start, length := int64(0), r.rangeStart
+    if !before { start, length = r.rangeEnd, math.MaxInt64 - r.rangeEnd }
+    if _, err := io.Copy(fhOut, io.NewSectionReader(fhIn, start, length)); err != nil { halt("io.Copy - " + err.Error()) }
+} //end func copyOutside
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file linerange.go
@@ -0,0 +1,62 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     linerange_test.go
+ * Overview:
+ *     tests of the sorts of a range of input lines, with a title and a column header ahead of the range and a total behind it.
+ * Functions:
+ *     TestLineRange(t *testing.T)
+ *         Checks the output of ranges inside the input, extending or starting past its end, and empty.
+ *     TestLineRangeHeader(t *testing.T)
+ *         Checks that the header lines kept by a range are neither keyed, numbered nor measured.
+ * History:
+ *     v1.18.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "fmt"
+    "testing"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func TestLineRange(t *testing.T) {
+    tests := []struct {
+        from, to int64
+        sortAsc  bool
+        output   string
+    }{
+        {3, 5, true, "# fruit\nname\tn\napple\t10\nfig\t2\npear\t3\nTOTAL\t15\n"},
+        {3, 5, false, "# fruit\nname\tn\npear\t3\nfig\t2\napple\t10\nTOTAL\t15\n"},
+        {1, 6, true, "# fruit\nTOTAL\t15\napple\t10\nfig\t2\nname\tn\npear\t3\n"},
+        {3, 100, true, "# fruit\nname\tn\nTOTAL\t15\napple\t10\nfig\t2\npear\t3\n"},  //extends past the end of the input
+        {7, 9, true, _rangeInput},                                                    //starts past the end of the input
+        {5, 4, true, _rangeInput},                                                    //empty
+        {4, 4, false, _rangeInput},                                                   //a single line
+    }
+    for _, test := range tests {
+        name   := fmt.Sprintf("from=%d/to=%d/asc=%v", test.from, test.to, test.sortAsc)
+        output := sortBytes(t, _rangeInput, WithFields("1"), WithAlignment(1, AlignLeft), WithAscending(test.sortAsc),
+                            WithLineRange(test.from, test.to))
+        if string(output) != test.output { t.Errorf("%s: output\n%s\nexpected\n%s", name, output, test.output) }
+    }
+} //end func TestLineRange
+func TestLineRangeHeader(t *testing.T) {
+    //Numbered lines: those of the range are tagged with their numbers in the whole input, the others are copied as is
+    output := sortBytes(t, _rangeInput, WithFields("1"), WithAlignment(1, AlignLeft), WithLineRange(3, 5),
+                        WithOriginalLineNumbers(true, ""))
+    if expected := "# fruit\nname\tn\n4\tapple\t10\n5\tfig\t2\n3\tpear\t3\nTOTAL\t15\n"; string(output) != expected {
+        t.Errorf("numbered output\n%s\nexpected\n%s", output, expected)
+    }
+    //Right-aligned counts: only the records of the range are keyed and counted, not the header "n" or the total
+    var stats SortStats
+    output = sortBytes(t, _rangeInput, WithFields("2"), WithLineRange(3, 5), WithStats(&stats))
+    if expected := "# fruit\nname\tn\nfig\t2\npear\t3\napple\t10\nTOTAL\t15\n"; string(output) != expected {
+        t.Errorf("output\n%s\nexpected\n%s", output, expected)
+    }
+    if stats.InputRecords != 3 { t.Errorf("%d records sorted, expected the 3 of the range", stats.InputRecords) }
+} //end func TestLineRangeHeader
+//Private ----------------------------------------------------------------------------------------------------------------------
+const _rangeInput = "# fruit\nname\tn\npear\t3\napple\t10\nfig\t2\nTOTAL\t15\n" //a title, a header, 3 records and a total
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file linerange_test.go
@@ -0,0 +1,25 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !windows
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!windows
+
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     lock_other.go
+ * Overview:
+ *     advisory file locks, on the systems without flock or LockFileEx.
+ * History:
+ *     v1.35.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "os"
+)
+//Private ----------------------------------------------------------------------------------------------------------------------
+func tryLock(fh *os.File) (bool, error) {
+    //Reports the file as locked, no lock being available
+    return true, nil
+} //end func tryLock
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file lock_other.go
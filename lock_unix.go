@@ -0,0 +1,28 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     lock_unix.go
+ * Overview:
+ *     advisory file locks through flock.
+ * History:
+ *     v1.35.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "os"
+    "syscall"
+)
+//Private ----------------------------------------------------------------------------------------------------------------------
+func tryLock(fh *os.File) (bool, error) {
+    //Locks a file exclusively if no other open file holds its lock, returning false otherwise
+    err := syscall.Flock(int(fh.Fd()), syscall.LOCK_EX | syscall.LOCK_NB)
+    if err == syscall.EWOULDBLOCK { return false, nil }
+    return err == nil, err
+} //end func tryLock
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file lock_unix.go
@@ -0,0 +1,35 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     lock_windows.go
+ * Overview:
+ *     advisory file locks through LockFileEx.
+ * History:
+ *     v1.35.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "os"
+    "syscall"
+    "unsafe"
+)
+//Private ----------------------------------------------------------------------------------------------------------------------
+const(
+    _lockfileFailImmediately = 0x1                                //LOCKFILE_FAIL_IMMEDIATELY
+    _lockfileExclusiveLock   = 0x2                                //LOCKFILE_EXCLUSIVE_LOCK
+    _errorLockViolation      = syscall.Errno(33)                  //ERROR_LOCK_VIOLATION
+)
+var _procLockFileEx = syscall.NewLazyDLL("kernel32.dll").NewProc("LockFileEx")
+func tryLock(fh *os.File) (bool, error) {
+    //Locks a file exclusively if no other open file holds its lock, returning false otherwise
+    var overlapped syscall.Overlapped
+    ok, _, err := _procLockFileEx.Call(fh.Fd(), _lockfileExclusiveLock | _lockfileFailImmediately, 0, 1, 0,
+                                       uintptr(unsafe.Pointer(&overlapped)))
+    if ok != 0 { return true, nil }
+    if err == _errorLockViolation { return false, nil }
+    return false, err
+} //end func tryLock
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file lock_windows.go
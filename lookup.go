@@ -0,0 +1,330 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     lookup.go
+ * Overview:
+ *     binary-search lookups of records in a text file sorted by the package.
+ * Functions:
+ *     Lookup(sortedFile string, usingFields, sep string, sortAsc bool, key string) (offset int64, record string, err error)
+ *         Finds the first record of a sorted file whose index fields equal a given key.
+ *     LookupAll(sortedFile string, usingFields, sep string, sortAsc bool, key string) (*RecordIterator, error)
+ *         Returns an iterator over all the records of a sorted file whose index fields equal a given key.
+ *     ExtractRange(sortedFile, outFile string, usingFields, sep string, sortAsc bool, fromKey, toKey string,
+ *                  bounds ...RangeBound) error
+ *         Copies the records of a sorted file whose index fields lie between two keys.
+ * Types:
+ *     RecordIterator
+ *         Iterator over consecutive records of a sorted file.
+ *     RangeBound
+ *         Bound of a key range excluded from an extraction.
+ * History:
+ *     v1.2.0 - October 15, 2026 - Original release.
+ *     v1.62.0 - October 15, 2026 - Added ExtractRange.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "bufio"
+    "errors"
+    "fmt"
+    "io"
+    "os"
+    "strings"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+var ErrKeyNotFound = errors.New("mergesort: key not found")
+
+type RecordIterator struct {
+    fh       *os.File
+    reader   *bufio.Reader
+    colIdxs  []int
+    sep      string
+    values   []string
+    offset   int64
+    next     int64
+    record   string
+    err      error
+}
+type RangeBound int
+const(
+    ExcludeFrom RangeBound = iota //records equal to fromKey are not extracted
+    ExcludeTo                     //records equal to toKey are not extracted
+)
+
+func Lookup(sortedFile string, usingFields, sep string, sortAsc bool, key string) (offset int64, record string, err error) {
+/*         Purpose : Finds the first record of a sorted file whose index fields equal a given key.
+ *       Arguments : sortedFile  = path of a file sorted by the package.
+ *                   usingFields = CSV of field numbers used as indexes when sorting, ordered as primary, secondary, etc.,
+ *                                 with the first field referenced as 1.
+ *                   sep         = the field separator.
+ *                   sortAsc     = boolean flag indicating whether the file was sorted in ascending order.
+ *                   key         = the values of the index fields to look for, separated by sep. Fewer values than index
+ *                                 fields may be given, in which case only the leading index fields are compared.
+ *         Returns : offset      = offset of the record relative to the origin of the file.
+ *                   record      = the record, including its terminator.
+ *                   err         = ErrKeyNotFound if no record matches the key, or any error encountered.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : lowerBound, parseColumns, readRecordAt
+ *         Remarks : The search bisects the file's byte range, resynchronizing to the next record boundary after each seek,
+ *                   or probing closer to the start of the range when a long record spans its second half, so that only
+ *                   O(log n) records are ever read. The keys are compared as Sort does, i.e. on values right-aligned to a
+ *                   common width.
+ *         History : v1.2.0 - October 15, 2026 - Original release.
+ */
+    it, err := LookupAll(sortedFile, usingFields, sep, sortAsc, key)
+    if err != nil { return }
+    defer it.Close()
+    if !it.Next() {
+        err = it.Err()
+        if err == nil { err = ErrKeyNotFound }
+        return
+    }
+    return it.Offset(), it.Record(), nil
+} //end func Lookup
+func LookupAll(sortedFile string, usingFields, sep string, sortAsc bool, key string) (*RecordIterator, error) {
+/*         Purpose : Returns an iterator over all the records of a sorted file whose index fields equal a given key.
+ *       Arguments : sortedFile  = path of a file sorted by the package.
+ *                   usingFields = CSV of field numbers used as indexes when sorting, ordered as primary, secondary, etc.,
+ *                                 with the first field referenced as 1.
+ *                   sep         = the field separator.
+ *                   sortAsc     = boolean flag indicating whether the file was sorted in ascending order.
+ *                   key         = the values of the index fields to look for, separated by sep.
+ *         Returns : An iterator positioned before the first matching record, and any error encountered. The iterator
+ *                   must be closed by the caller.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : lowerBound, parseColumns
+ *         Remarks : See Lookup.
+ *         History : v1.2.0 - October 15, 2026 - Original release.
+ */
+    if sortedFile  == "" { return nil, errors.New("mergesort: the sorted file was not specified") }
+    if usingFields == "" {
+        return nil, &kindError{kind:ErrBadFieldSpec, err:errors.New("mergesort: the index fields columns were not specified")}
+    }
+    colIdxs, err := parseColumns(usingFields)
+    if err != nil { return nil, fmt.Errorf("mergesort: %w", err) }
+    values := strings.Split(key, sep)
+    if len(values) > len(colIdxs) { return nil, errors.New("mergesort: the key has more values than index fields") }
+    fh, err := os.Open(sortedFile)
+    if err != nil { return nil, err }
+    fi, err := fh.Stat()
+    if err != nil {
+        fh.Close()
+        return nil, err
+    }
+    start, err := lowerBound(fh, fi.Size(), colIdxs[:len(values)], sep, sortAsc, values)
+    if err != nil {
+        fh.Close()
+        return nil, err
+    }
+    if _, err = fh.Seek(start, 0); err != nil {
+        fh.Close()
+        return nil, err
+    }
+    return &RecordIterator{fh:fh, reader:bufio.NewReader(fh), colIdxs:colIdxs[:len(values)], sep:sep, values:values,
+                           next:start}, nil
+} //end func LookupAll
+func (it *RecordIterator) Next() bool {
+/*         Purpose : Advances the iterator to the next matching record.
+ *       Arguments : None.
+ *         Returns : True if a matching record is available, false at the end of the matches or on error.
+ *         History : v1.2.0 - October 15, 2026 - Original release.
+ */
+    if it.err != nil || it.fh == nil { return false }
+    record, err := it.reader.ReadString('\n')
+    if err != nil && err != io.EOF {
+        it.err = err
+        return false
+    }
+    if len(record) == 0 || compareKeys(recordKey(record, it.sep, it.colIdxs), it.values) != 0 { return false }
+    it.offset, it.record = it.next, record
+    it.next             += int64(len(record))
+    return true
+} //end func Next
+func (it *RecordIterator) Record() string { return it.record }  //the current record, including its terminator
+func (it *RecordIterator) Offset() int64  { return it.offset }  //offset of the current record relative to the file origin
+func (it *RecordIterator) Err() error     { return it.err }     //the first error encountered, if any
+func (it *RecordIterator) Close() error {
+/*         Purpose : Releases the file handle held by the iterator.
+ *       Arguments : None.
+ *         Returns : Any error encountered when closing the file.
+ *         History : v1.2.0 - October 15, 2026 - Original release.
+ */
+    if it.fh == nil { return nil }
+    err  := it.fh.Close()
+    it.fh = nil
+    return err
+} //end func Close
+func ExtractRange(sortedFile, outFile string, usingFields, sep string, sortAsc bool, fromKey, toKey string,
+                  bounds ...RangeBound) (err error) {
+/*         Purpose : Copies the records of a sorted file whose index fields lie between two keys.
+ *       Arguments : sortedFile  = path of a file sorted by the package.
+ *                   outFile     = path of the file for the records extracted.
+ *                   usingFields = CSV of field numbers used as indexes when sorting, ordered as primary, secondary, etc.,
+ *                                 with the first field referenced as 1.
+ *                   sep         = the field separator.
+ *                   sortAsc     = boolean flag indicating whether the file was sorted in ascending order.
+ *                   fromKey     = the values of the index fields starting the range, separated by sep, or "" for a range
+ *                                 starting with the file.
+ *                   toKey       = the values of the index fields ending the range, separated by sep, or "" for a range
+ *                                 ending with the file.
+ *                   bounds      = bounds excluded from the range, ExcludeFrom and/or ExcludeTo. Both are included if none.
+ *         Returns : Any error encountered.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : bisect, parseColumns, precedes, recordKey
+ *         Remarks : The keys are taken in the file's order: for a descending file, fromKey is the larger of the two. As
+ *                   for Lookup, a key may give fewer values than index fields, in which case only the leading index fields
+ *                   are compared, e.g. the range "2026-01" to "2026-03" of a file sorted on a month then a day includes
+ *                   every day of March. The start of the range is found by bisection and the records are then copied
+ *                   sequentially until the end of the range, so that the records before it are never read. The keys are
+ *                   compared as Sort does, i.e. on values right-aligned to a common width. A range whose end precedes its
+ *                   start extracts no records. A last record lacking its terminator is given one.
+ *         History : v1.62.0 - October 15, 2026 - Original release.
+ */
+    var excludeFrom, excludeTo bool
+
+    if sortedFile  == "" { return errors.New("mergesort: the sorted file was not specified") }
+    if outFile     == "" { return errors.New("mergesort: the output file was not specified") }
+    if usingFields == "" {
+        return &kindError{kind:ErrBadFieldSpec, err:errors.New("mergesort: the index fields columns were not specified")}
+    }
+    colIdxs, err := parseColumns(usingFields)
+    if err != nil { return fmt.Errorf("mergesort: %w", err) }
+    for _, bound := range bounds {
+        switch bound {
+            case ExcludeFrom: excludeFrom = true
+            case ExcludeTo:   excludeTo   = true
+            default:          return fmt.Errorf("mergesort: the range bound %d is unknown", bound)
+        }
+    }
+    var fromValues, toValues []string                             //nil for an open end
+    if fromKey != "" { fromValues = strings.Split(fromKey, sep) }
+    if toKey   != "" { toValues   = strings.Split(toKey, sep) }
+    if len(fromValues) > len(colIdxs) || len(toValues) > len(colIdxs) {
+        return errors.New("mergesort: a key has more values than index fields")
+    }
+    fh, err := os.Open(sortedFile)
+    if err != nil { return err }
+    defer fh.Close()
+    fi, err := fh.Stat()
+    if err != nil { return err }
+    //Locate the start of the range
+    start := int64(0)
+    if fromValues != nil {
+        fromIdxs := colIdxs[:len(fromValues)]
+        start, err = bisect(fh, fi.Size(), func(record string) bool {
+            cmp := compareKeys(recordKey(record, sep, fromIdxs), fromValues)
+            return precedes(cmp, sortAsc) || (excludeFrom && cmp == 0)
+        })
+        if err != nil { return }
+    }
+    if _, err = fh.Seek(start, 0); err != nil { return }
+    //Copy the records up to the end of the range
+    fhOut, err := os.Create(outFile)
+    if err != nil { return }
+    defer func() {
+        if errClose := fhOut.Close(); err == nil { err = errClose }
+    }()
+    reader := bufio.NewReader(fh)
+    writer := bufio.NewWriter(fhOut)
+    for {
+        record, errIn := reader.ReadString('\n')
+        if errIn != nil && errIn != io.EOF { return errIn }
+        if len(record) == 0 { break }
+        if toValues != nil {
+            cmp := compareKeys(recordKey(record, sep, colIdxs[:len(toValues)]), toValues)
+            if precedes(-cmp, sortAsc) || (excludeTo && cmp == 0) { break }
+        }
+        if !strings.HasSuffix(record, "\n") { record += "\n" } //file's last record lacking its terminator
+        if _, err = writer.WriteString(record); err != nil { return }
+        if errIn == io.EOF { break }
+    }
+    if err = writer.Flush(); err != nil { return }
+    return fhOut.Sync()
+} //end func ExtractRange
+//Private ----------------------------------------------------------------------------------------------------------------------
+////Bisection
+func lowerBound(fh *os.File, size int64, colIdxs []int, sep string, sortAsc bool, values []string) (int64, error) {
+    //Returns the offset of the first record not ordered before the key, or the file size if none
+    return bisect(fh, size, func(record string) bool {
+        return precedes(compareKeys(recordKey(record, sep, colIdxs), values), sortAsc)
+    })
+} //end func lowerBound
+func bisect(fh *os.File, size int64, before func(record string) bool) (int64, error) {
+    //Returns the offset of the first record for which before is false, before being true for a leading part of the file.
+    //Invariant: lo is a record start preceded only by records for which before is true, and hi is a record start (or the
+    //file size) followed only by records for which it is false.
+    lo, hi := int64(0), size
+    for lo < hi {
+        mid          := lo + (hi - lo) / 2
+        start, err   := nextRecordStart(fh, mid)
+        for err == nil && start >= hi {                           //no record boundary in [mid, hi): probe closer to lo,
+            mid        = lo + (mid - lo) / 2                      //lo itself being a record start
+            start, err = nextRecordStart(fh, mid)
+        }
+        if err != nil { return 0, err }
+        record, err  := readRecordAt(fh, start)
+        if err != nil { return 0, err }
+        if before(record) {
+            lo = start + int64(len(record))
+        } else {
+            hi = start
+        }
+    }
+    return lo, nil
+} //end func bisect
+func nextRecordStart(fh *os.File, pos int64) (int64, error) {
+    if pos == 0 { return 0, nil }
+    if _, err := fh.Seek(pos - 1, 0); err != nil { return 0, err }
+    skipped, err := bufio.NewReader(fh).ReadString('\n')
+    if err != nil && err != io.EOF { return 0, err }
+    return pos - 1 + int64(len(skipped)), nil
+} //end func nextRecordStart
+func precedes(cmp int, sortAsc bool) bool {
+    if sortAsc { return cmp < 0 }
+    return cmp > 0
+} //end func precedes
+func readRecordAt(fh *os.File, pos int64) (string, error) {
+    if _, err := fh.Seek(pos, 0); err != nil { return "", err }
+    record, err := bufio.NewReader(fh).ReadString('\n')
+    if err != nil && err != io.EOF { return "", err }
+    return record, nil
+} //end func readRecordAt
+////Key comparison
+func compareKeys(a, b []string) int {
+    //Compares lists of field values as Sort does, i.e. each pair being right-aligned to a common width
+    return compareAligned(a, b, nil)
+} //end func compareKeys
+func compareAligned(a, b []string, leftAligned []bool) int {
+    //Compares lists of field values with each pair aligned to a common width, to the left if flagged in leftAligned and to
+    //the right otherwise
+    for k := 0; k < len(a) && k < len(b); k++ {
+        x, y := a[k], b[k]
+        left := k < len(leftAligned) && leftAligned[k]
+        if n := len(y) - len(x); n > 0 { x = pad(x, n, left) }
+        if n := len(x) - len(y); n > 0 { y = pad(y, n, left) }
+        if cmp := strings.Compare(x, y); cmp != 0 { return cmp }
+    }
+    return 0
+} //end func compareAligned
+func pad(value string, n int, right bool) string {
+    //Pads a value with n spaces, on the right or on the left
+    if right { return value + strings.Repeat(" ", n) }
+    return strings.Repeat(" ", n) + value
+} //end func pad
+func recordKey(record, sep string, colIdxs []int) []string {
+    return fieldValues(strings.Split(strings.Trim(record, " \r\n"), sep), colIdxs)
+} //end func recordKey
+func fieldValues(fields []string, colIdxs []int) []string {
+    //Returns the values of the index fields, empty if missing
+    values := make([]string, len(colIdxs))
+    for k, colIdx := range colIdxs {
+        if colIdx < len(fields) { values[k] = fields[colIdx] }
+    }
+    return values
+} //end func fieldValues
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file lookup.go
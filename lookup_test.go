@@ -0,0 +1,168 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     lookup_test.go
+ * Overview:
+ *     tests of the binary-search lookups and range extractions of sorted files, ascending or descending.
+ * Functions:
+ *     TestLookup(t *testing.T)
+ *         Checks the records found by Lookup and LookupAll for keys absent, first, last and repeated.
+ *     TestLookupLongRecord(t *testing.T)
+ *         Checks that a long record spanning the midpoint of the search keeps the number of records read logarithmic.
+ *     TestExtractRange(t *testing.T)
+ *         Checks the records extracted between two keys, open-ended or with their bounds excluded.
+ * History:
+ *     v1.2.0 - October 15, 2026 - Original release.
+ *     v1.62.0 - October 15, 2026 - Added TestExtractRange.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "errors"
+    "fmt"
+    "io/ioutil"
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func TestLookup(t *testing.T) {
+    for _, sortAsc := range []bool{true, false} {
+        records := lookupRecords(sortAsc)
+        inFile  := writeInput(t, strings.Join(records, ""))
+        //Lookup of the first record of each key, or of none
+        cases   := []struct {
+            key    string
+            record string                                           //"" if not found
+        }{
+            {"k000", "k000\ta\n"},
+            {"k099", "k099\ta\n"},
+            {"k050", "k050\ta\n"},
+            {"k100", ""},
+            {"k0505", ""},
+            {"j", ""},
+        }
+        for _, test := range cases {
+            offset, record, err := Lookup(inFile, "1", "\t", sortAsc, test.key)
+            if test.record == "" {
+                if !errors.Is(err, ErrKeyNotFound) { t.Errorf("asc %v, key %s: error %v, expected ErrKeyNotFound", sortAsc,
+                                                              test.key, err) }
+                continue
+            }
+            if err != nil { t.Fatalf("asc %v, key %s: %v", sortAsc, test.key, err) }
+            if expected := recordOffset(records, test.record); record != test.record || offset != expected {
+                t.Errorf("asc %v, key %s: record %q at %d, expected %q at %d", sortAsc, test.key, record, offset,
+                         test.record, expected)
+            }
+        }
+        //All the records of a repeated key, of the last key, and of none
+        for key, expected := range map[string]string{"k050":"k050\ta\nk050\tb\nk050\tc\n", "k099":"k099\ta\n", "k100":""} {
+            it, err := LookupAll(inFile, "1", "\t", sortAsc, key)
+            if err != nil { t.Fatal(err) }
+            var found strings.Builder
+            for it.Next() {
+                if offset := recordOffset(records, it.Record()); it.Offset() != offset {
+                    t.Errorf("asc %v, key %s: %q at %d, expected at %d", sortAsc, key, it.Record(), it.Offset(), offset)
+                }
+                found.WriteString(it.Record())
+            }
+            if err := it.Err(); err != nil { t.Fatal(err) }
+            if err := it.Close(); err != nil { t.Fatal(err) }
+            if found.String() != expected { t.Errorf("asc %v, key %s: found %q, expected %q", sortAsc, key, found.String(),
+                                                     expected) }
+        }
+    }
+} //end func TestLookup
+func TestLookupLongRecord(t *testing.T) {
+    //1000 short records, then one of 100KB past the midpoint of the file, then 10 short records
+    var input strings.Builder
+    for k := 0; k < 1010; k++ {
+        fmt.Fprintf(&input, "k%04d", k)
+        if k == 1000 { input.WriteString("\t" + strings.Repeat("x", 100000)) }
+        input.WriteString("\n")
+    }
+    inFile := writeInput(t, input.String())
+    fh, err := os.Open(inFile)
+    if err != nil { t.Fatal(err) }
+    defer fh.Close()
+    for _, key := range []string{"k0000", "k0500", "k0999", "k1000", "k1005", "k2000"} {
+        reads  := 0
+        offset, err := bisect(fh, int64(input.Len()), func(record string) bool {
+            reads++
+            return compareKeys(recordKey(record, "\t", []int{0}), []string{key}) < 0
+        })
+        if err != nil { t.Fatal(err) }
+        if expected := strings.Index(input.String() + key, key); offset != int64(expected) {
+            t.Errorf("key %s at %d, expected at %d", key, offset, expected)
+        }
+        if reads > 20 { t.Errorf("key %s: %d records read, expected at most 20 of 1010", key, reads) }
+    }
+} //end func TestLookupLongRecord
+func TestExtractRange(t *testing.T) {
+    cases := []struct {
+        sortAsc          bool
+        fromKey, toKey   string
+        bounds           []RangeBound
+        first, last      string                                     //keys of the first and last records extracted
+        count            int
+    }{
+        {true, "k010", "k012", nil, "k010", "k012", 3},
+        {true, "k010", "k012", []RangeBound{ExcludeFrom}, "k011", "k012", 2},
+        {true, "k010", "k012", []RangeBound{ExcludeTo}, "k010", "k011", 2},
+        {true, "k010", "k012", []RangeBound{ExcludeFrom, ExcludeTo}, "k011", "k011", 1},
+        {true, "k049", "k050", nil, "k049", "k050", 4},             //the repeated key included whole
+        {true, "k050", "k051", []RangeBound{ExcludeFrom}, "k051", "k051", 1},
+        {true, "", "k001", nil, "k000", "k001", 2},
+        {true, "k098", "", nil, "k098", "k099", 2},
+        {true, "k012", "k010", nil, "", "", 0},
+        {false, "k012", "k010", nil, "k012", "k010", 3},
+        {false, "k051", "k050", []RangeBound{ExcludeTo}, "k051", "k051", 1},
+        {false, "k001", "", nil, "k001", "k000", 2},
+    }
+    for _, test := range cases {
+        inFile  := writeInput(t, strings.Join(lookupRecords(test.sortAsc), ""))
+        outFile := filepath.Join(t.TempDir(), "out.txt")
+        if err := ExtractRange(inFile, outFile, "1", "\t", test.sortAsc, test.fromKey, test.toKey,
+                               test.bounds...); err != nil {
+            t.Fatal(err)
+        }
+        output, err := ioutil.ReadFile(outFile)
+        if err != nil { t.Fatal(err) }
+        records := strings.SplitAfter(string(output), "\n")
+        records  = records[:len(records) - 1]
+        name    := fmt.Sprintf("asc %v, %q to %q, bounds %v", test.sortAsc, test.fromKey, test.toKey, test.bounds)
+        if len(records) != test.count {
+            t.Errorf("%s: %d records extracted, expected %d", name, len(records), test.count)
+        } else if test.count > 0 && (!strings.HasPrefix(records[0], test.first + "\t") ||
+                                     !strings.HasPrefix(records[test.count - 1], test.last + "\t")) {
+            t.Errorf("%s: extracted %q to %q, expected %s to %s", name, records[0], records[test.count - 1], test.first,
+                     test.last)
+        }
+    }
+} //end func TestExtractRange
+//Private ----------------------------------------------------------------------------------------------------------------------
+func lookupRecords(sortAsc bool) []string {
+    //Returns the records of the keys k000 to k099, that of k050 repeated thrice, in the order of the sort
+    var records []string
+    for k := 0; k < 100; k++ {
+        key := k
+        if !sortAsc { key = 99 - k }
+        values := []string{"a"}
+        if key == 50 { values = []string{"a", "b", "c"} }
+        for _, value := range values { records = append(records, fmt.Sprintf("k%03d\t%s\n", key, value)) }
+    }
+    return records
+} //end func lookupRecords
+func recordOffset(records []string, record string) int64 {
+    //Returns the offset of the first occurrence of a record
+    offset := int64(0)
+    for _, listed := range records {
+        if listed == record { break }
+        offset += int64(len(listed))
+    }
+    return offset
+} //end func recordOffset
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file lookup_test.go
@@ -0,0 +1,98 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     lowcard.go
+ * Overview:
+ *     fast path of the runs whose index fields take few distinct values: a single pass sorts their keys into buckets of
+ *     equal index fields, in input order, and only the buckets are compared.
+ * Functions:
+ *     WithLowCardinality(maxValues int) Option
+ *         Option setting the most distinct values of the index fields sorted by buckets.
+ * History:
+ *     v1.90.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "fmt"
+    "sort"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func WithLowCardinality(maxValues int) Option {
+/*         Purpose : Sets the most distinct values of the index fields for which a run is sorted by buckets.
+ *       Arguments : maxValues = the most distinct values, _bucketValues by default, or 0 to always sort by comparisons.
+ *         Returns : The option.
+ * Externals -  In : _bucketValues
+ * Externals - Out : None.
+ *       Functions : None.
+ *         Remarks : Each run, and the keys of an input sorted in memory, are first sorted by buckets: a single pass assigns
+ *                   each key to the bucket of its index fields, i.e. of the values of all of them together, and only the
+ *                   first key of each bucket is then compared with the others, by the normal ordering of the sort, so that
+ *                   the secondary fields order the buckets sharing a primary value. The keys of a bucket keep their input
+ *                   order, as the offsets that end them would order them. As soon as a run holds more than maxValues
+ *                   distinct values, the pass stops and the run is sorted by comparisons as usual, the pass having cost a
+ *                   map lookup for each key up to there. For an index of a few hundred values, e.g. status codes or regions,
+ *                   the runs are thus sorted in linear time, with the same output. A larger maxValues suits an index known
+ *                   to hold more values, at the price of a map of that many entries per run. The verbose echo reports each
+ *                   run sorted by buckets.
+ *         History : v1.90.0 - October 15, 2026 - Original release.
+ */
+    return func(s *Sorter) { s.maxBuckets = maxValues }
+} //end func WithLowCardinality
+//Private ----------------------------------------------------------------------------------------------------------------------
+const _bucketValues = 256 //default number of distinct values of the index fields sorted by buckets
+func (r *sortRun) sortRunKeys(keys []string) {
+    //Sorts the keys of a run, by buckets if their index fields take few values, by comparisons otherwise
+    if !r.bucketKeys(keys) { sort.Slice(keys, func(i, j int) bool { return keyPrecedes(keys[i], keys[j], r.sortAsc) }) }
+} //end func sortRunKeys
+func (r *sortRun) bucketKeys(keys []string) bool {
+    //Sorts keys by buckets of equal index fields if there are at most maxBuckets of them, returning false if not
+    if r.maxBuckets == 0 { return false }
+    var(
+        buckets = map[string]int32{}                              //bucket of each value of the index fields
+        ids     = make([]int32, len(keys))                        //bucket of each key
+        firsts  = []string{}                                      //first key of each bucket
+        lasts   = []string{}                                      //last key of each bucket so far
+        counts  = []int{}                                         //keys of each bucket
+        ordered = true                                            //keys of each bucket in order
+    )
+    for k, key := range keys {
+        value   := keyIndexPart(key)
+        id, ok  := buckets[value]
+        if !ok {
+            if len(firsts) == r.maxBuckets { return false }
+            id, buckets[value] = int32(len(firsts)), int32(len(firsts))
+            firsts, lasts      = append(firsts, key), append(lasts, key)
+            counts             = append(counts, 0)
+        } else if key < lasts[id] {
+            ordered = false
+        }
+        ids[k], lasts[id] = id, key
+        counts[id]++
+    }
+    order := make([]int, len(firsts))                             //buckets in sorted order
+    for k := range order { order[k] = k }
+    sort.Slice(order, func(i, j int) bool { return keyPrecedes(firsts[order[i]], firsts[order[j]], r.sortAsc) })
+    starts, at := make([]int, len(firsts)), 0                     //position of each bucket in the sorted keys
+    for _, id := range order {
+        starts[id] = at
+        at        += counts[id]
+    }
+    sorted := make([]string, len(keys))
+    for k, key := range keys {
+        sorted[starts[ids[k]]] = key
+        starts[ids[k]]++
+    }
+    copy(keys, sorted)
+    if !ordered {                                                 //keys out of input order: the buckets sorted on them
+        for _, id := range order {
+            bucket := keys[starts[id] - counts[id]:starts[id]]
+            sort.Strings(bucket)
+        }
+    }
+    if r.verbose { fmt.Println("func Sort - sorted", len(keys), "keys by", len(firsts), "buckets") }
+    return true
+} //end func bucketKeys
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file lowcard.go
@@ -0,0 +1,62 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     lowcard_test.go
+ * Overview:
+ *     tests of the sorts of the runs of few distinct index values by buckets.
+ * Functions:
+ *     TestLowCardinality(t *testing.T)
+ *         Checks that the runs sorted by buckets, or past their limit by comparisons, are ordered as by comparisons.
+ * History:
+ *     v1.90.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "fmt"
+    "math/rand"
+    "sort"
+    "testing"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func TestLowCardinality(t *testing.T) {
+    //Records of 8 regions by 4 statuses, i.e. 32 distinct pairs of index fields
+    rng     := rand.New(rand.NewSource(26))
+    records := make([]string, 2000)
+    input   := ""
+    for k := range records {
+        records[k] = fmt.Sprintf("%s\t%s\t%d\n", _regions[rng.Intn(len(_regions))], _statuses[rng.Intn(len(_statuses))], k)
+        input     += records[k]
+    }
+    for _, sortAsc := range []bool{true, false} {
+        //The keys of a run sorted by buckets within the limit only, as by comparisons
+        newKeyFn := keyBuilder(t, records, WithFields("1,2"))
+        keyFn    := newKeyFn()
+        keys     := make([]string, len(records))
+        for k, record := range records { keys[k] = keyFn(record, int64(k), len(record)) }
+        compared := append([]string(nil), keys...)
+        sort.Slice(compared, func(i, j int) bool { return keyPrecedes(compared[i], compared[j], sortAsc) })
+        for _, maxValues := range []int{32, 31} {
+            s, err := NewSorter(WithFields("1,2"), WithSeparator("\t"), WithAscending(sortAsc),
+                                WithLowCardinality(maxValues))
+            if err != nil { t.Fatal(err) }
+            bucketed := append([]string(nil), keys...)
+            if done := s.newRun().bucketKeys(bucketed); done != (maxValues == 32) {
+                t.Errorf("asc %v, limit %d: sorted by buckets %v, expected %v", sortAsc, maxValues, done, !done)
+            } else if done && fmt.Sprint(bucketed) != fmt.Sprint(compared) {
+                t.Errorf("asc %v, limit %d: keys sorted by buckets out of order", sortAsc, maxValues)
+            }
+        }
+        //The outputs alike with the default limit, a limit exceeded, and none
+        var outputs []string
+        for _, maxValues := range []int{_bucketValues, 31, 0} {
+            outputs = append(outputs, string(sortBytes(t, input, WithFields("1,2"), WithSeparator("\t"),
+                                                       WithAscending(sortAsc), WithKeysPerSort(300),
+                                                       WithLowCardinality(maxValues))))
+        }
+        if outputs[0] != outputs[2] || outputs[1] != outputs[2] { t.Errorf("asc %v: outputs differ by limit", sortAsc) }
+    }
+} //end func TestLowCardinality
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file lowcard_test.go
@@ -0,0 +1,64 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     lowpriority.go
+ * Overview:
+ *     low-priority execution of a sort: a single fetching goroutine, naps between the runs and merges while the system is
+ *     busy, and the lowest thread priorities for the merges where the system allows them.
+ * Functions:
+ *     WithLowPriority() Option
+ *         Option running the sorts so as to give way to the other work of the system.
+ * History:
+ *     v1.85.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "io/ioutil"
+    "runtime"
+    "strconv"
+    "strings"
+    "time"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func WithLowPriority() Option {
+/*         Purpose : Runs the sorts so as to give way to the other work of the system.
+ *       Arguments : None.
+ *         Returns : The option.
+ * Externals -  In : _lowPriorityNap
+ * Externals - Out : None.
+ *       Functions : None.
+ *         Remarks : The records of the output are fetched by a single goroutine, whatever WithParallelism requests, the
+ *                   merges being already those of a single goroutine. Before writing each run and each merge, the sort
+ *                   naps for _lowPriorityNap if the system is busy, i.e. if the load average over the last minute
+ *                   exceeds the number of CPUs, or, where no load average is found in /proc/loadavg, if a sleep of a
+ *                   millisecond overruns by another. On Linux, the merging goroutine also runs on a thread of its own
+ *                   with the lowest CPU priority, nice 19, and the idle I/O class, which the kernel permits without
+ *                   privileges. The keying and output, and the merges of WithDeterministic and MergeRuns, run by the
+ *                   caller's goroutine, keep its priorities. The sort thus gives way to the system, taking longer under
+ *                   load, with the same output. It combines with WithMaxIORate for a hard cap on its bandwidth.
+ *         History : v1.85.0 - October 15, 2026 - Original release.
+ */
+    return func(s *Sorter) { s.lowPriority = true }
+} //end func WithLowPriority
+//Private ----------------------------------------------------------------------------------------------------------------------
+const _lowPriorityNap = 50 * time.Millisecond //nap before a run or merge of a low-priority sort if the system is busy
+func (r *sortRun) giveWay() {
+    //Naps if the system is busy and the sort has a low priority
+    if r.lowPriority && systemBusy() { time.Sleep(_lowPriorityNap) }
+} //end func giveWay
+func systemBusy() bool {
+    //Returns whether the load average over the last minute exceeds the number of CPUs or, without a load average, whether a
+    //sleep of a millisecond overruns by another
+    if data, err := ioutil.ReadFile("/proc/loadavg"); err == nil {
+        if fields := strings.Fields(string(data)); len(fields) > 0 {
+            if load, err := strconv.ParseFloat(fields[0], 64); err == nil { return load > float64(runtime.NumCPU()) }
+        }
+    }
+    start := time.Now()
+    time.Sleep(time.Millisecond)
+    return time.Since(start) > 2 * time.Millisecond
+} //end func systemBusy
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file lowpriority.go
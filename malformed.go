@@ -0,0 +1,234 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     malformed.go
+ * Overview:
+ *     handling of the malformed records met while keying the input, each one either skipped or failing the sort as decided
+ *     by a handler.
+ * Functions:
+ *     WithRecordErrorHandler(handler func(line int64, record string, err error) error) Option
+ *         Option deciding the fate of the records that cannot be keyed.
+ *     WithMaxErrors(n int) Option
+ *     WithMaxErrorRate(fraction float64, minSample int) Option
+ *         Options aborting the sort once too many records are malformed.
+ * History:
+ *     v1.66.0 - October 15, 2026 - Original release.
+ *     v1.67.0 - October 15, 2026 - Added WithMaxErrors and WithMaxErrorRate.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "errors"
+    "fmt"
+    "sort"
+    "strings"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func WithRecordErrorHandler(handler func(line int64, record string, err error) error) Option {
+/*         Purpose : Decides the fate of the records that cannot be keyed.
+ *       Arguments : handler = function called with the 1-based line of each malformed record, 0 if unknown, the record
+ *                             without its terminator and its error, matching ErrMalformedRecord. Returns nil to skip the
+ *                             record, or an error to abort the sort. nil for none, the default.
+ *         Returns : The option.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : None.
+ *         Remarks : A record is malformed when it lacks one of the index fields, when a typed index field holds a value that
+ *                   does not parse, unless WithInvalidValuesLast places such values or the value is empty and placed by
+ *                   WithNullsFirst or WithNullsLast, when a value of a strictly zero-padded field is not all digits, or when
+ *                   a text value exceeds its width sampled by WithSampledWidths, unless the keying is to restart with the
+ *                   exact widths instead, or when the key of WithDerivedKey cannot be computed. Without a handler, nor a
+ *                   limit set by WithMaxErrors or WithMaxErrorRate, records lacking an index field or holding a value that
+ *                   does not parse are keyed as though the values were empty or invalid, and the others fail the sort. A
+ *                   skipped record is neither keyed nor output, and is counted by the SkippedRecords of SortStats rather
+ *                   than by its InputRecords. An error returned by the handler is returned by the sort within a RecordError
+ *                   locating the record, or as is by GenerateRuns, which reports the lines as unknown. The handler is called
+ *                   by the goroutine keying the input, one record at a time, and at most once per record even if the keying
+ *                   restarts; a sorter sorting several inputs at once, e.g. through SortGlob or concurrent calls of Run,
+ *                   calls it concurrently, so it must then be safe for concurrent use.
+ *         History : v1.66.0 - October 15, 2026 - Original release.
+ */
+    return func(s *Sorter) { s.onRecordError = handler }
+} //end func WithRecordErrorHandler
+func WithMaxErrors(n int) Option {
+/*         Purpose : Aborts the sort once more than n records are malformed.
+ *       Arguments : n = the number of malformed records tolerated. Must not be negative; 0 tolerates none.
+ *         Returns : The option.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : None.
+ *         Remarks : The malformed records are those described by WithRecordErrorHandler. With a limit, they are skipped
+ *                   even without a handler, and those skipped by a handler count toward it. The sort then fails with
+ *                   ErrTooManyErrors at the record exceeding the limit, the error giving the number of malformed records
+ *                   by category and quoting the first ones. A producer of GenerateRuns fails once its own range exceeds
+ *                   the limit, and MergeRuns applies it again to the malformed records of all the ranges.
+ *         History : v1.67.0 - October 15, 2026 - Original release.
+ */
+    return func(s *Sorter) { s.errorCap, s.maxErrors = true, n }
+} //end func WithMaxErrors
+func WithMaxErrorRate(fraction float64, minSample int) Option {
+/*         Purpose : Aborts the sort once the fraction of the records that are malformed exceeds a rate.
+ *       Arguments : fraction  = the largest fraction tolerated, from 0 to 1.
+ *                   minSample = the number of records read before the rate applies, so that a few malformed records at
+ *                               the start of the input do not abort the sort. Must not be negative.
+ *         Returns : The option.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : None.
+ *         Remarks : The rate is that of the malformed records among the non-blank records read so far, i.e. within any
+ *                   line range and filters, and is checked at each malformed record once minSample records are read, and
+ *                   at the minSample-th record, so that the sort stops as soon as it is exceeded. The records are
+ *                   screened, skipped and reported as with WithMaxErrors. The producers of GenerateRuns, each seeing a
+ *                   part of the input only, record the positions of their malformed records in their manifests, and
+ *                   MergeRuns applies the rate to them in input order before merging, reaching the same verdict as a
+ *                   single sort of the input; the producers must then be given the same options as the merger.
+ *         History : v1.67.0 - October 15, 2026 - Original release.
+ */
+    return func(s *Sorter) { s.rateCap, s.errorRate, s.rateSample = true, fraction, minSample }
+} //end func WithMaxErrorRate
+//Private ----------------------------------------------------------------------------------------------------------------------
+const _faultExamples = 3 //number of malformed records quoted by the error of an exceeded limit
+type recordHandler func(line int64, record string, err error) error
+type malformedError struct {
+    category string                                               //class of the fault, for the summary of a limit
+    err      error                                                //the fault
+}
+type faultLog struct {
+    seen     int                                                  //records screened for faults
+    at       []int                                                //0-based ordinals of the records skipped among those seen
+    kinds    []string                                             //categories of the records skipped
+    examples []string                                             //first records skipped, located
+}
+func (e *malformedError) Error() string        { return e.err.Error() }
+func (e *malformedError) Unwrap() error        { return e.err }
+func (e *malformedError) Is(target error) bool { return target == ErrMalformedRecord }
+func (s *Sorter) screening() bool {
+    //Returns whether the records are screened for faults, i.e. if a handler or a limit is set
+    return s.onRecordError != nil || s.errorCap || s.rateCap
+} //end func screening
+func (r *sortRun) keyRecord(keyFn func(record string, recordStart int64, recordLen int) string, at *RecordError,
+                            record, trimmed string) (key string, ok bool) {
+    //Returns the composite key of the record located by at, or false if it is malformed and skipped, then rejected; halts
+    //if the handler aborts the sort or a limit on the malformed records is exceeded
+    recordLen := len(record)
+    if !r.screening() { return keyFn(trimmed, at.Offset, recordLen), true }
+    r.faults.seen++
+    fault, replayed := r.skipped[at.Line]                         //skipped before a restart of the keying
+    if !replayed {
+        if fault = r.recordFault(trimmed); fault == nil { key, fault = r.tryKey(keyFn, trimmed, at.Offset, recordLen) }
+        if fault == nil {
+            if r.faults.seen == r.rateSample { r.checkFaults(&r.faults) }
+            return key, true
+        }
+        if r.onRecordError != nil {
+            if err := r.onRecordError(int64(at.Line), at.Record, fault); err != nil { panic(haltError{err}) }
+        }
+        if r.sampleRestart && at.Line > 0 {
+            if r.skipped == nil { r.skipped = map[int]error{} }
+            r.skipped[at.Line] = fault
+        }
+    }
+    r.stats.SkippedRecords++
+    r.reject(r.faults.log(fault, at), record)
+    r.checkFaults(&r.faults)
+    return "", false
+} //end func keyRecord
+func (r *sortRun) recordFault(trimmed string) error {
+    //Returns the error of a record lacking an index field or holding a typed value that is neither parsed nor placed
+    fields := r.splitFields(trimmed, r.lastIndexField() + 2)
+    for _, colIdx := range r.colIdxs {
+        if colIdx >= len(fields) {
+            return malformed("missing field", fmt.Sprintf("the record has %d fields and lacks the index field %d",
+                                                          len(fields), colIdx + 1))
+        }
+        kind, typed := r.fieldTypes[colIdx]
+        if !typed || r.invalidPlaced { continue }
+        value := r.prepare(colIdx, fields[colIdx])
+        if _, ok := parseField(kind, r.parsing(colIdx), value); ok { continue }
+        if _, placed := r.nulls[colIdx]; placed && value == "" { continue }
+        return malformed("unparsed value", fmt.Sprintf("the value %q of field %d does not parse as its type",
+                                                       fields[colIdx], colIdx + 1))
+    }
+    return nil
+} //end func recordFault
+func (r *sortRun) tryKey(keyFn func(record string, recordStart int64, recordLen int) string, trimmed string,
+                         recordStart int64, recordLen int) (key string, err error) {
+    //Returns the composite key of a record, or the halt it caused as a malformed-record error; a value overflowing its
+    //sampled width is left to the restart of the keying if allowed
+    defer func() {
+        if p := recover(); p != nil {
+            var overflow *widthError
+            h, ok := p.(haltError)
+            if !ok || (r.sampleRestart && errors.As(h.err, &overflow)) { panic(p) }
+            category := "bad value"
+            if errors.As(h.err, &overflow) { category = "overlong value" }
+            if m, ok := h.err.(*malformedError); ok { category = m.category }
+            err = &malformedError{category:category, err:h.err}
+        }
+    }()
+    return keyFn(trimmed, recordStart, recordLen), nil
+} //end func tryKey
+func malformed(category, msg string) error {
+    return &malformedError{category:category, err:errors.New("mergesort: " + msg)}
+} //end func malformed
+func (f *faultLog) log(fault error, at *RecordError) (category string) {
+    //Records a skipped record, quoting it if among the first ones, and returns its category
+    category = "bad value"
+    if m, ok := fault.(*malformedError); ok { category = m.category }
+    f.at, f.kinds = append(f.at, f.seen - 1), append(f.kinds, category)
+    if len(f.examples) < _faultExamples {
+        where := fmt.Sprintf("offset %d", at.Offset)
+        if at.Line > 0 { where = fmt.Sprintf("line %d", at.Line) }
+        record := at.Record
+        if len(record) > 80 { record = record[:80] + "..." }
+        f.examples = append(f.examples, fmt.Sprintf("%s (%s) %q", where, category, record))
+    }
+    return
+} //end func log
+func (r *sortRun) checkFaults(f *faultLog) {
+    //Halts with a summary of the skipped records if they exceed WithMaxErrors, or WithMaxErrorRate once its sample is seen;
+    //the rate is left to the merger of runs
+    var limit string
+    skipped := len(f.at)
+    switch {
+        case r.errorCap && skipped > r.maxErrors:
+            limit = fmt.Sprintf("the limit of %d", r.maxErrors)
+        case r.rateCap && !r.producing && f.seen >= r.rateSample && float64(skipped) > r.errorRate * float64(f.seen):
+            limit = fmt.Sprintf("the rate of %g", r.errorRate)
+        default:
+            return
+    }
+    var categories []string
+    counts := map[string]int{}
+    for _, category := range f.kinds { counts[category]++ }
+    for category, n := range counts { categories = append(categories, fmt.Sprintf("%s: %d", category, n)) }
+    sort.Strings(categories)
+    haltKind(ErrTooManyErrors, fmt.Sprintf("%d malformed records among the first %d exceed %s (%s), e.g. %s", skipped,
+                                           f.seen, limit, strings.Join(categories, ", "), strings.Join(f.examples, "; ")), nil)
+} //end func checkFaults
+func (r *sortRun) replayFaults(manifests []*runManifest) {
+    //Applies the limits on the malformed records to those skipped by the producers of runs, in input order, as a single
+    //sort of the input would
+    var f faultLog
+    crossed := func(upTo int) {                                   //checks the rate once its sample is seen
+        if r.rateSample > 0 && f.seen < r.rateSample && upTo >= r.rateSample {
+            f.seen = r.rateSample
+            r.checkFaults(&f)
+        }
+    }
+    base := 0
+    for _, m := range manifests {
+        if len(m.FaultKinds) != len(m.FaultAt) { haltKind(ErrRunMismatch, "the manifest " + m.path + " is invalid", nil) }
+        for k, ordinal := range m.FaultAt {
+            crossed(base + ordinal)
+            f.seen, f.at, f.kinds = base + ordinal + 1, append(f.at, base + ordinal), append(f.kinds, m.FaultKinds[k])
+            if k < len(m.Examples) && len(f.examples) < _faultExamples { f.examples = append(f.examples, m.Examples[k]) }
+            r.checkFaults(&f)
+        }
+        base += m.InputRecords + m.SkippedRecords
+    }
+    crossed(base)
+} //end func replayFaults
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file malformed.go
@@ -0,0 +1,177 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     mergeinto.go
+ * Overview:
+ *     incremental merge of unsorted new records into an already sorted file.
+ * Functions:
+ *     MergeInto(sortedMaster, unsortedDelta, outFile string, opts ...Option) error
+ *         Sorts a delta file and merges it with a sorted master file.
+ *     WithUpsert() Option
+ *         Option having the delta records of MergeInto replace the master ones with the same index fields.
+ * History:
+ *     v1.43.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "bufio"
+    "fmt"
+    "os"
+    "path/filepath"
+    "strings"
+    "time"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func MergeInto(sortedMaster, unsortedDelta, outFile string, opts ...Option) (err error) {
+/*         Purpose : Sorts a delta file and merges it with a sorted master file.
+ *       Arguments : sortedMaster  = path of the master file, sorted by the package with the same options.
+ *                   unsortedDelta = path of the file with the new records, in any order. It may be empty.
+ *                   outFile       = path of the file for the merged records. It cannot be the master file.
+ *                   opts          = options, see NewSorter. WithFields is mandatory.
+ *         Returns : Any error encountered, including finding that the master file is not sorted.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : NewSorter, catch, checkRemoval, halt, mergeInto, preserveAttributes, removeInputs, useScratchDir
+ *         Remarks : Only the delta goes through the sort, its keys being merged in temporary files as Run merges them.
+ *                   The master file is then read once, sequentially, its records being interleaved with the sorted
+ *                   delta's in a single pass and its order checked as it goes. The index fields are compared as Sort
+ *                   compares them, whatever the field widths of either file. On equal index fields, the master records
+ *                   come first, unless WithUpsert is given. The master records are copied unchanged, its blank lines
+ *                   dropped, and the filters apply to the delta only. WithFrequencyOrder, WithLineRange,
+ *                   WithOriginalLineNumbers, WithRankColumn and WithPercentileColumn are not supported.
+ *         History : v1.43.0 - October 15, 2026 - Original release.
+ */
+    defer catch(&err)
+    if sortedMaster == "" { halt("the master file was not specified") }
+    if outFile      == "" { halt("the output file was not specified") }
+    sorter, err := NewSorter(opts...)
+    if err != nil { panic(haltError{err}) }
+    if sorter.freqOrder || sorter.lineRange || sorter.lineNumbers || sorter.rankCol || sorter.pctCol {
+        halt("WithFrequencyOrder, WithLineRange, WithOriginalLineNumbers and the rank columns do not apply to a merge")
+    }
+    run := sorter.newRun()
+    run.checkRemoval(outFile, sortedMaster, unsortedDelta)
+    defer run.useScratchDir(outFile)()
+    run.mergeInto(sortedMaster, unsortedDelta, outFile)
+    run.preserveAttributes(sortedMaster, outFile)
+    run.removeInputs(sortedMaster, unsortedDelta)
+    return
+} //end func MergeInto
+func WithUpsert() Option {
+/*         Purpose : Has the delta records of MergeInto replace the master ones with the same index fields.
+ *       Arguments : None.
+ *         Returns : The option.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : None.
+ *         Remarks : All the master records equal to a delta record on the index fields are dropped, and all the delta
+ *                   records equal to one another are kept, in their input order. The dropped records are not counted
+ *                   in the statistics, so that WithVerifyOutput still holds. The option has no effect on the other
+ *                   functions.
+ *         History : v1.43.0 - October 15, 2026 - Original release.
+ */
+    return func(s *Sorter) { s.upsert = true }
+} //end func WithUpsert
+//Private ----------------------------------------------------------------------------------------------------------------------
+type masterReader struct {
+    file     string
+    reader   *bufio.Reader
+    readFn   func(*bufio.Reader) (string, error) //reads a record, with its continuation lines if grouped
+    keyFn    func(record string) []string        //comparable values of a record's index fields
+    compare  func(a, b []string) int             //comparison of the values of two records
+    rejectFn func(reason, record string)         //rejection of the blank records
+    sortAsc  bool
+    lineNum  int                                 //line number of the current record
+    record   string                              //current record, including its terminator
+    key      []string                            //comparable values of the current record
+    eof      bool
+    err      error                               //disorder found, ending the reading
+}
+func (mr *masterReader) advance() {
+    //Reads the next non-blank record, checking that it does not precede the current one
+    prevKey := mr.key
+    for !mr.eof {
+        record, _ := mr.readFn(mr.reader)
+        if len(record) == 0 {
+            mr.record, mr.key, mr.eof = "", nil, true
+            return
+        }
+        mr.lineNum++
+        if strings.Trim(record, " \r\n") == "" {
+            mr.rejectFn("blank", record)
+            continue
+        }
+        if !strings.HasSuffix(record, "\n") { record += "\n" }          //master's last record lacking its terminator
+        mr.record, mr.key = record, mr.keyFn(record)
+        if prevKey != nil && precedes(mr.compare(mr.key, prevKey), mr.sortAsc) {
+            mr.err, mr.eof = fmt.Errorf("%s is not sorted at line %d", mr.file, mr.lineNum), true
+        }
+        return
+    }
+} //end func advance
+func (r *sortRun) mergeInto(masterFile, deltaFile, outFile string) {
+    //Merges the sorted delta into the master file, record by record
+    if filepath.Clean(masterFile) == filepath.Clean(outFile) { halt("the output file cannot be the master file") }
+    r.resolveSeparator(masterFile)
+    fhMaster, err := os.Open(masterFile)
+    if err != nil { haltKind(ErrInputNotFound, "the master file cannot be located", err) }
+    defer fhMaster.Close()
+    fhDelta, size := r.openInput(deltaFile)
+    defer fhDelta.Close()
+    fhOut  := r.createOutput(outFile)
+    defer fhOut.Close()
+    defer r.openRejects()()
+    writer := bufio.NewWriter(fhOut)
+    write  := func(record string) {
+        r.countOutput(record)
+        if _, err := writer.WriteString(record); err != nil { halt("writer.WriteString - " + err.Error()) }
+    }
+    master := &masterReader{file:masterFile, reader:bufio.NewReader(fhMaster), readFn:r.readRecord, keyFn:r.orderKey,
+                            compare:r.orderComparer(), sortAsc:r.sortAsc, rejectFn:r.reject}
+    copyMaster := func() {
+        r.countInput(master.record)
+        write(master.record)
+        master.advance()
+    }
+    master.advance()
+    if size > 0 {
+        sortedKeysFile, numKeys, _ := r.sortKeys(deltaFile)
+        defer r.removeTemp(sortedKeysFile)
+        fhKeys, scannerKeys := r.openKeys(sortedKeysFile)
+        defer fhKeys.Close()
+        numRecs := 0
+        r.readRecords(readerAt(fhDelta), sortedKeysFile, scannerKeys, func(_, record string, _ bool) bool {
+            if !strings.HasSuffix(record, "\n") { record += "\n" }      //delta's last record lacking its terminator
+            key := master.keyFn(record)
+            for !master.eof {
+                cmp := master.compare(master.key, key)
+                if precedes(cmp, r.sortAsc) || cmp == 0 && !r.upsert {
+                    copyMaster()
+                } else if cmp == 0 {                                    //master record replaced
+                    r.reject("replaced", master.record)
+                    master.advance()
+                } else {
+                    break
+                }
+            }
+            if master.err != nil { return false }
+            write(record)
+            if r.verbose {
+                numRecs++
+                updateProgressBar("func MergeInto - merging the delta", numRecs, numKeys)
+            }
+            return true
+        })
+    }
+    for !master.eof { copyMaster() }
+    if master.err != nil { halt(master.err.Error()) }
+    if err := writer.Flush();  err != nil { halt("writer.Flush - " + err.Error()) }
+    if err := fhOut.Sync();    err != nil { halt("fhOut.Sync - " + err.Error()) }
+    if err := fhOut.Close();   err != nil { halt("fhOut.Close - " + err.Error()) }
+    r.checkOutput(nil)
+    if r.verbose { fmt.Println("func MergeInto - created", outFile, "in", time.Since(r.start)) }
+} //end func mergeInto
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file mergeinto.go
@@ -0,0 +1,60 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     mergeinto_test.go
+ * Overview:
+ *     tests of the merge of an unsorted delta into a sorted master file, with or without the upserts of its records.
+ * Functions:
+ *     TestMergeInto(t *testing.T)
+ *         Checks the order of the records with equal index fields, and their replacement per WithUpsert.
+ *     TestMergeIntoDisorder(t *testing.T)
+ *         Checks that a master file that is not sorted fails the merge at its first record out of order.
+ * History:
+ *     v1.43.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "io/ioutil"
+    "path/filepath"
+    "strings"
+    "testing"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func TestMergeInto(t *testing.T) {
+    //The master's records of "a" and "b" tied with delta records, the delta's own ties in their input order
+    master := writeInput(t, "a\t1\nb\t1\nb\t2\nd\t1\n")
+    delta  := writeInput(t, "c\tx\nb\tx\na\tx\nb\ty\n")
+    cases  := []struct {
+        name     string
+        opts     []Option
+        expected string
+    }{
+        {"master first", nil, "a\t1\na\tx\nb\t1\nb\t2\nb\tx\nb\ty\nc\tx\nd\t1\n"},
+        {"upsert", []Option{WithUpsert()}, "a\tx\nb\tx\nb\ty\nc\tx\nd\t1\n"},
+    }
+    for _, test := range cases {
+        outFile := filepath.Join(t.TempDir(), "out.txt")
+        opts    := append([]Option{WithFields("1"), WithSeparator("\t"), WithTempDir(t.TempDir())}, test.opts...)
+        if err := MergeInto(master, delta, outFile, opts...); err != nil { t.Fatalf("%s: %v", test.name, err) }
+        output, err := ioutil.ReadFile(outFile)
+        if err != nil { t.Fatal(err) }
+        if string(output) != test.expected { t.Errorf("%s: output %q, expected %q", test.name, output, test.expected) }
+    }
+} //end func TestMergeInto
+func TestMergeIntoDisorder(t *testing.T) {
+    //The third master record out of order, found while merging the delta or copying the master's tail
+    master := writeInput(t, "a\t1\nc\t1\nb\t1\nd\t1\n")
+    for _, deltaData := range []string{"a\tx\nd\tx\n", ""} {
+        delta := filepath.Join(t.TempDir(), "delta.txt")
+        if err := ioutil.WriteFile(delta, []byte(deltaData), 0644); err != nil { t.Fatal(err) }
+        err := MergeInto(master, delta, filepath.Join(t.TempDir(), "out.txt"), WithFields("1"), WithSeparator("\t"),
+                         WithTempDir(t.TempDir()))
+        if err == nil || !strings.Contains(err.Error(), "is not sorted at line 3") {
+            t.Errorf("delta %q: error %v, expected the master not sorted at line 3", deltaData, err)
+        }
+    }
+} //end func TestMergeIntoDisorder
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file mergeinto_test.go
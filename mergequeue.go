@@ -0,0 +1,119 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     mergequeue.go
+ * Overview:
+ *     optimal ordering of the merges of the key files: a priority queue of the files keyed by size, from which each merge
+ *     takes the smallest ones, as in the construction of a Huffman code, so that the large files are rewritten the fewest
+ *     times.
+ * History:
+ *     v1.87.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "container/heap"
+    "fmt"
+    "io"
+    "sync/atomic"
+)
+//Private ----------------------------------------------------------------------------------------------------------------------
+type pooledFile struct {
+    name  string                                                  //name of the key file
+    size  int64                                                   //its size, in keys or bytes
+    level int                                                     //merges between the runs and the file, 0 for a run
+    seq   int                                                     //order of its addition, breaking ties
+}
+type fileHeap []pooledFile
+type mergeQueue struct {
+    files fileHeap                                                //files pending, the smallest first
+    added int                                                     //files added so far
+}
+func (h fileHeap) Len() int { return len(h) }
+func (h fileHeap) Less(i, j int) bool {
+    //Orders on the sizes, then on the order of addition so that equal files are merged as listed
+    if h[i].size != h[j].size { return h[i].size < h[j].size }
+    return h[i].seq < h[j].seq
+}
+func (h fileHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *fileHeap) Push(x interface{}) { *h = append(*h, x.(pooledFile)) }
+func (h *fileHeap) Pop() interface{} {
+    old   := *h
+    file  := old[len(old) - 1]
+    *h     = old[:len(old) - 1]
+    return file
+}
+func (q *mergeQueue) Len() int { return len(q.files) }
+func (q *mergeQueue) add(name string, size int64) {
+    //Makes a file available for merging
+    heap.Push(&q.files, pooledFile{name:name, size:size, seq:q.added})
+    q.added++
+} //end func add
+func (q *mergeQueue) addMerged(name string, group []pooledFile) {
+    //Makes the output of a merge of a group of files available for merging, one level above the highest of them
+    merged := pooledFile{name:name, seq:q.added}
+    for _, file := range group {
+        merged.size += file.size
+        if file.level >= merged.level { merged.level = file.level + 1 }
+    }
+    heap.Push(&q.files, merged)
+    q.added++
+} //end func addMerged
+func (q *mergeQueue) putBack(group []pooledFile) {
+    //Returns files taken to the queue, in their places
+    for _, file := range group { heap.Push(&q.files, file) }
+} //end func putBack
+func (q *mergeQueue) take(n int) (group []pooledFile, names []string, size int64) {
+    //Removes the n smallest files, returning them, their names and their total size
+    for ; n > 0; n-- {
+        file := heap.Pop(&q.files).(pooledFile)
+        group, names, size = append(group, file), append(names, file.name), size + file.size
+    }
+    return
+} //end func take
+func nextFanIn(files, fanIn int) int {
+    //Returns the number of files of the next merge of files down to one, just enough for all the later merges to take fanIn
+    //files each
+    return (files - 2) % (fanIn - 1) + 2
+} //end func nextFanIn
+func mergeTasks(files, fanIn int) int {
+    //Returns the number of merges of files down to one, fanIn at most at a time
+    return (files + fanIn - 3) / (fanIn - 1)
+} //end func mergeTasks
+func (r *sortRun) mergeSmallestFirst(files []string, caller string, pooled bool) string {
+    //Merges key files down to one, each merge taking the smallest files left, by bytes, and returns its name. The merges,
+    //counted as one pass, run in the caller's goroutine, or in one of their own if pooled.
+    fanIn := r.mergeFanIn()
+    atomic.AddInt64(&r.counters.passes, 1)
+    passTimed := r.timePass()
+    r.progress.begin(mergeTasks(len(files), fanIn))
+    if r.verbose { fmt.Printf("func %s - %d files pending\n", caller, len(files)) }
+    queue := mergeQueue{}
+    for _, name := range files { queue.add(name, r.tempSize(name)) }
+    if pooled {
+        pool := &mergePool{run:r, queue:queue, present:queue.Len(), closed:true, passTimed:passTimed}
+        pool.start(1)
+        merged, err := pool.finish()
+        if err != nil { panic(haltError{err}) }
+        return merged
+    }
+    for queue.Len() > 1 {
+        _, names, size := queue.take(nextFanIn(queue.Len(), fanIn))
+        merged, err    := r.mergeFiles(names)
+        if err != nil { panic(haltError{err}) }
+        queue.add(merged, size)
+    }
+    passTimed()
+    return queue.files[0].name
+} //end func mergeSmallestFirst
+func (r *sortRun) tempSize(name string) int64 {
+    //Returns the size of a key file in bytes
+    fh := r.openTemp(name)
+    defer fh.Close()
+    size, err := fh.Seek(0, io.SeekEnd)
+    if err != nil { halt("Seek - " + err.Error()) }
+    return size
+} //end func tempSize
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file mergequeue.go
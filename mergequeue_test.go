@@ -0,0 +1,80 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     mergequeue_test.go
+ * Overview:
+ *     benchmarks of the merges of the smallest key files first, on runs of which one is 100 times the size of the others.
+ * Functions:
+ *     BenchmarkMergeQueue(b *testing.B)
+ *         Times MergeRuns over 16 runs, even or with one 100 times the others, reporting the keys rewritten.
+ * History:
+ *     v1.87.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "math/rand"
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func BenchmarkMergeQueue(b *testing.B) {
+    //Sub-benchmarks "runs=even", "runs=big-first" and "runs=big-last", reporting the bytes of the temporary files written
+    //per byte of the runs: each byte is rewritten twice by two levels of merges at fan-in 4 when the runs are even, while the
+    //large run, merged the smallest first, is rewritten only once
+    const runs, small, big = 16, 1000, 100000                     //records of the runs
+    rng     := rand.New(rand.NewSource(8))
+    records := make([]string, (runs - 1) * small + big)
+    for k := range records { records[k] = randomRecord(rng) }
+    inFile  := writeInput(b, strings.Join(records, ""))
+    cases   := []struct {
+        name  string
+        sizes []int                                               //records of each run, in input order
+    }{
+        {"even", evenSizes(len(records), runs)},
+        {"big-first", append([]int{big}, evenSizes((runs - 1) * small, runs - 1)...)},
+        {"big-last", append(evenSizes((runs - 1) * small, runs - 1), big)},
+    }
+    for _, test := range cases {
+        b.Run("runs=" + test.name, func(b *testing.B) {
+            opts   := []Option{WithFields("2,1"), WithKeysPerSort(big), WithMergeFanIn(4), WithTempDir(b.TempDir())}
+            runDir := b.TempDir()
+            start  := int64(0)
+            next   := 0
+            for k, size := range test.sizes {
+                end := start
+                for _, record := range records[next:next + size] { end += int64(len(record)) }
+                if k == len(test.sizes) - 1 { end = -1 }
+                if err := GenerateRuns(inFile, ByteRange{Start:start, End:end}, runDir, opts...); err != nil { b.Fatal(err) }
+                start, next = end, next + size
+            }
+            runBytes := int64(0)
+            keyFiles, err := listDir(runDir, "", ".keys")
+            if err != nil || len(keyFiles) != runs { b.Fatalf("%d run files (%v)", len(keyFiles), err) }
+            for _, name := range keyFiles {
+                info, err := os.Stat(name)
+                if err != nil { b.Fatal(err) }
+                runBytes += info.Size()
+            }
+            var stats SortStats
+            outFile := filepath.Join(b.TempDir(), "out.txt")
+            b.ResetTimer()
+            for k := 0; k < b.N; k++ {
+                if err := MergeRuns(runDir, inFile, outFile, append(opts, WithStats(&stats))...); err != nil { b.Fatal(err) }
+            }
+            b.ReportMetric(float64(stats.TempBytes) / float64(runBytes), "rewrites/op")
+        })
+    }
+} //end func BenchmarkMergeQueue
+//Private ----------------------------------------------------------------------------------------------------------------------
+func evenSizes(records, runs int) []int {
+    //Returns the records of runs of even size covering records
+    sizes := make([]int, runs)
+    for k := range sizes { sizes[k] = (records * (k + 1)) / runs - (records * k) / runs }
+    return sizes
+} //end func evenSizes
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file mergequeue_test.go
@@ -3,32 +3,203 @@
  *     mergesort
  * Overview:
  *     package for a stable, multi-index, partially concurrent hybrid merge sort of a text file.
- * Function:
- *     Sort(inFile, outFile string, sortAsc bool, usingFields, sep string, keysPerSort int, verbose bool)
+ * Functions:
+ *     Sort(inFile, outFile string, sortAsc bool, usingFields, sep string, keysPerSort int, verbose bool,
+ *          opts ...Option) error
  *         Does a stable, multi-index, partially concurrent hybrid merge sort of a text file.
+ *     SortFS(fsys fs.FS, name, outFile string, sortAsc bool, usingFields, sep string, keysPerSort int, verbose bool,
+ *            opts ...Option) error
+ *         Sorts a text file read from a file system rather than from the OS one.
+ *     SortIndex(inFile, indexFile string, sortAsc bool, usingFields, sep string, keysPerSort int, verbose bool,
+ *               opts ...Option) error
+ *         Produces the sort order of a text file without rewriting its data.
+ *     Lookup(sortedFile string, usingFields, sep string, sortAsc bool, key string) (offset int64, record string, err error)
+ *         Finds the first record of a sorted file whose index fields equal a given key.
+ *     LookupAll(sortedFile string, usingFields, sep string, sortAsc bool, key string) (*RecordIterator, error)
+ *         Returns an iterator over all the records of a sorted file whose index fields equal a given key.
+ *     ApplyIndex(inFile, indexFile, outFile string) error
+ *         Creates the sorted copy of a text file from an index produced by SortIndex.
+ *     SortWithKeys(inFile, keysFile, outFile string, opts ...Option) error
+ *         Sorts a text file on the keys of an index produced by SortIndex with the same options, without keying it.
+ *     Join(leftFile, rightFile, outFile string, leftFields, rightFields, sep string, joinType string, opts ...Option) error
+ *         Does a streaming sort-merge equi-join of two sorted text files.
+ *     SortAndReduce(inFile, outFile string, sortAsc bool, usingFields, sep string, keysPerSort int, verbose bool,
+ *                   reduce func(key, record string, lastInGroup bool) []string, opts ...Option) error
+ *         Sorts a text file and reduces each group of records sharing the same index-field values.
+ *     SortChan(ctx context.Context, in <-chan string, sortAsc bool, usingFields, sep string, keysPerSort int,
+ *              verbose bool, opts ...Option) (<-chan string, <-chan error)
+ *         Sorts the records received on a channel and emits them in sorted order on another.
+ *     SortStrings(records []string, sortAsc bool, usingFields, sep string) error
+ *         Sorts a slice of records in place with the same ordering rules as Sort.
+ *     ParseFieldSpec(spec string) ([]KeySpec, error)
+ *         Parses a CSV of field numbers into key specifications.
+ *     CompareRecords(a, b string, specs []KeySpec, sep string) (int, error)
+ *         Compares two records on their index fields as Sort orders them.
+ *     NewSorter(opts ...Option) (*Sorter, error)
+ *     (*Sorter) Run(inFile, outFile string) error
+ *     (*Sorter) RunFS(fsys fs.FS, name, outFile string) error
+ *         Creates a sorter configured once through options, reusable across many files. See sorter.go.
+ *     NewSpillQueue(opts ...Option) (*SpillQueue, error)
+ *         Creates a disk-backed queue delivering the records pushed into it in sorted order. See spillqueue.go.
+ *     Reverse(inFile, outFile string, opts ...Option) error
+ *         Copies a text file with its records in reverse order. See reverse.go.
+ *     NewMemStorage() *MemStorage
+ *         Creates a RAM-backed storage for the temporary files, set with WithTempStorage. See storage.go.
+ *     GenerateTestFile(path string, spec GenSpec) error
+ *         Writes a file of random records per spec, the same seed always giving the same bytes. See generate.go.
+ *     SortGlob(pattern, outDirOrTemplate string, opts ...Option) ([]GlobResult, error)
+ *         Sorts every file matching a pattern to its own output file, several at once. See glob.go.
+ *     MergeInto(sortedMaster, unsortedDelta, outFile string, opts ...Option) error
+ *         Sorts a delta file and merges it with a sorted master file. See mergeinto.go.
+ *     DetectSeparator(inFile string, candidates []string) (string, error)
+ *         Returns the candidate separator splitting the first records of a file consistently. See detect.go.
+ *     GenerateRuns(inFile string, byteRange ByteRange, runDir string, opts ...Option) error
+ *         Sorts the keys of the records starting within a byte range of a file into a shared directory. See runs.go.
+ *     MergeRuns(runDir, inFile, outFile string, opts ...Option) error
+ *         Merges the run files of a shared directory covering a file and outputs its sorted records. See runs.go.
+ *     ExtractRange(sortedFile, outFile string, usingFields, sep string, sortAsc bool, fromKey, toKey string,
+ *                  bounds ...RangeBound) error
+ *         Copies the records of a sorted file whose index fields lie between two keys. See lookup.go.
+ *     CompareSorted(fileA, fileB string, usingFields, sep string, sortAsc bool, onlyA, onlyB, both io.Writer) error
+ *         Routes the records of two sorted files to those only in the first, only in the second, or in both. See compare.go.
+ *     VerifyStable(inFile, outFile string, usingFields, sep string, sortAsc bool) (bool, error)
+ *         Checks that a file is a stable sort of another on the given index fields. See stable.go.
+ *     Estimate(input EstimateInput, opts ...Option) (SortEstimate, error)
+ *         Works out the runs, merge passes, temporary space and I/O of a sort without running it. See estimate.go.
+ *     NewPool(limits PoolLimits) (*Pool, error)
+ *     (*Pool) Sort(ctx context.Context, inFile, outFile string, opts ...Option) (SortStats, error)
+ *         Creates a pool of sorts sharing workers, memory for their runs and temporary space. See pool.go.
+ *     SortOffsets(inFile string, opts ...Option) ([]RecordSpan, error)
+ *         Returns the byte spans of the records of a text file in sorted order, without writing a file. See offsets.go.
  * History:
  *     v1.0.0 - November 19, 2016 - Original release.
+ *     v1.1.0 - October 15, 2026  - Added SortIndex.
+ *     v1.2.0 - October 15, 2026  - Added Lookup and LookupAll.
+ *     v1.3.0 - October 15, 2026  - Added ApplyIndex.
+ *     v1.4.0 - October 15, 2026  - Added Join.
+ *     v1.5.0 - October 15, 2026  - Added SortAndReduce.
+ *     v1.6.0 - October 15, 2026  - Added SortStrings. Descending sorts now keep equal keys in their input order.
+ *     v1.7.0 - October 15, 2026  - Added SortChan. Errors are now returned instead of terminating the program.
+ *     v1.8.0 - October 15, 2026  - Added Sorter and its functional options, also accepted by the Sort family.
+ *     v1.9.0 - October 15, 2026  - Added SpillQueue.
+ *     v1.10.0 - October 15, 2026 - Added SortFS and RunFS.
+ *     v1.11.0 - October 15, 2026 - Added the pluggable storage of the temporary files.
+ *     v1.12.0 - October 15, 2026 - Added WithInMemorySpillThreshold.
+ *     v1.13.0 - October 15, 2026 - Inputs within half the memory budget are sorted entirely in memory.
+ *     v1.14.0 - October 15, 2026 - Added WithHashOrder.
+ *     v1.15.0 - October 15, 2026 - Added Reverse. A last input record lacking its terminator is now given one.
+ *     v1.16.0 - October 15, 2026 - Added SortStats, WithStats and WithVerifyOutput.
+ *     v1.17.0 - October 15, 2026 - Added WithOriginalLineNumbers.
+ *     v1.18.0 - October 15, 2026 - Added WithLineRange.
+ *     v1.19.0 - October 15, 2026 - Added FieldType and WithFieldType with numeric fields.
+ *     v1.20.0 - October 15, 2026 - Added hex fields and SortStats.InvalidValues.
+ *     v1.21.0 - October 15, 2026 - Added duration fields and WithInvalidValuesLast.
+ *     v1.22.0 - October 15, 2026 - Added semver fields.
+ *     v1.23.0 - October 15, 2026 - Added WithFolding.
+ *     v1.24.0 - October 15, 2026 - Added WithKeyNormalizer.
+ *     v1.25.0 - October 15, 2026 - Added TrimMode and WithTrimming.
+ *     v1.26.0 - October 15, 2026 - Added WithNullsFirst and WithNullsLast.
+ *     v1.27.0 - October 15, 2026 - Added WithKeyFilterFile.
+ *     v1.28.0 - October 15, 2026 - Added WithFrequencyOrder.
+ *     v1.29.0 - October 15, 2026 - Merge tasks take WithMergeFanIn files instead of 2.
+ *     v1.30.0 - October 15, 2026 - Added WithTempNextToOutput.
+ *     v1.31.0 - October 15, 2026 - Added WithMaxOpenTempFiles.
+ *     v1.32.0 - October 15, 2026 - Added WithIORetry and IOError.
+ *     v1.33.0 - October 15, 2026 - Added the sentinel errors.
+ *     v1.34.0 - October 15, 2026 - Added RecordError.
+ *     v1.35.0 - October 15, 2026 - The output files are locked while written. Added WithOutputLockTimeout.
+ *     v1.36.0 - October 15, 2026 - The records are output by several goroutines. Added WithParallelism.
+ *     v1.37.0 - October 15, 2026 - The records of nearby offsets are fetched by a single read.
+ *     v1.38.0 - October 15, 2026 - Added WithPayloadKeys.
+ *     v1.39.0 - October 15, 2026 - Added GenerateTestFile.
+ *     v1.40.0 - October 15, 2026 - Added WithDeterministic.
+ *     v1.41.0 - October 15, 2026 - Added SortStats.TempBytes & MergePasses and the bench subpackage.
+ *     v1.42.0 - October 15, 2026 - Added SortGlob.
+ *     v1.43.0 - October 15, 2026 - Added MergeInto & WithUpsert.
+ *     v1.44.0 - October 15, 2026 - Added WithRankColumn, WithRankTies & WithPercentileColumn.
+ *     v1.45.0 - October 15, 2026 - Restricted the width scan to the index fields.
+ *     v1.46.0 - October 15, 2026 - Added WithSampledWidths.
+ *     v1.47.0 - October 15, 2026 - Added WithMaxKeyLen.
+ *     v1.48.0 - October 15, 2026 - Added WithCollapseSeparators & WithLeadingSeparatorField.
+ *     v1.49.0 - October 15, 2026 - Added WithEscapedSeparators.
+ *     v1.50.0 - October 15, 2026 - Added DetectSeparator & the "auto" separator.
+ *     v1.51.0 - October 15, 2026 - Added WithThousandsSeparator.
+ *     v1.52.0 - October 15, 2026 - Added FieldBoolean & WithBooleanTokens.
+ *     v1.53.0 - October 15, 2026 - Added WithDecimalSeparator.
+ *     v1.54.0 - October 15, 2026 - Interned the index-field values of the composite keys.
+ *     v1.55.0 - October 15, 2026 - Added WithZeroPadding.
+ *     v1.56.0 - October 15, 2026 - Added WithAlignment.
+ *     v1.57.0 - October 15, 2026 - Added GenerateRuns & MergeRuns.
+ *     v1.58.0 - October 15, 2026 - Added the trailers of the key files.
+ *     v1.59.0 - October 15, 2026 - Anonymous temporary files on Linux.
+ *     v1.60.0 - October 15, 2026 - Added WithMemoryMappedInput.
+ *     v1.61.0 - October 15, 2026 - The records are fetched by positioned reads.
+ *     v1.62.0 - October 15, 2026 - Added ExtractRange.
+ *     v1.63.0 - October 15, 2026 - Added WithTempCodec.
+ *     v1.64.0 - October 15, 2026 - Added CompareSorted.
+ *     v1.65.0 - October 15, 2026 - Added WithContinuationLines.
+ *     v1.66.0 - October 15, 2026 - Added WithRecordErrorHandler.
+ *     v1.67.0 - October 15, 2026 - Added WithMaxErrors and WithMaxErrorRate.
+ *     v1.68.0 - October 15, 2026 - Added WithRejectFile.
+ *     v1.69.0 - October 15, 2026 - Added VerifyStable.
+ *     v1.70.0 - October 15, 2026 - Added WithDerivedKey.
+ *     v1.71.0 - October 15, 2026 - Added KeySpec and WithKeys.
+ *     v1.72.0 - October 15, 2026 - Added SortWithKeys. Index headers record the digest of the options shaping the keys.
+ *     v1.73.0 - October 15, 2026 - Temporary files and run manifests listed without globbing their directory's path.
+ *     v1.74.0 - October 15, 2026 - Added WithRemoveInput.
+ *     v1.75.0 - October 15, 2026 - Added WithPreserveAttributes.
+ *     v1.76.0 - October 15, 2026 - Added WithTempEncryption. Run manifests created 0600.
+ *     v1.77.0 - October 15, 2026 - Added WithMergeProgress. The verbose echo of the merges reports their pass and task.
+ *     v1.78.0 - October 15, 2026 - Added Estimate.
+ *     v1.79.0 - October 15, 2026 - Added WithMaxTempSpace.
+ *     v1.80.0 - October 15, 2026 - Added WithTempDirs.
+ *     v1.81.0 - October 15, 2026 - Added WithTempPlacement.
+ *     v1.82.0 - October 15, 2026 - Added SortStats.Phases.
+ *     v1.83.0 - October 15, 2026 - Added WithTracing.
+ *     v1.84.0 - October 15, 2026 - Added WithMaxIORate.
+ *     v1.85.0 - October 15, 2026 - Added WithLowPriority.
+ *     v1.86.0 - October 15, 2026 - Added WithContinuousMerge.
+ *     v1.87.0 - October 15, 2026 - The merges after the first pass take the smallest key files first.
+ *     v1.88.0 - October 15, 2026 - Added WithMaxLiveRuns, SortStats.PeakTempBytes & PeakTempFiles.
+ *     v1.89.0 - October 15, 2026 - Added WithAdaptiveRunSize & SortStats.RunSizes.
+ *     v1.90.0 - October 15, 2026 - Added WithLowCardinality: the runs of few distinct keys are sorted by buckets.
+ *     v1.91.0 - October 15, 2026 - Added WithPackedRuns.
+ *     v1.92.0 - October 15, 2026 - The merges move the keys as bytes through buffered writers.
+ *     v1.93.0 - October 15, 2026 - Added WithMemoryPressure & SortStats.PressureFlushes.
+ *     v1.94.0 - October 15, 2026 - Added ParseFieldSpec & CompareRecords.
+ *     v1.95.0 - October 15, 2026 - Added WithReadProgress & the progress of the width scan and key generation.
+ *     v1.96.0 - October 15, 2026 - Added WithExpectUnique, WithUniqueMode, ErrDuplicateKeys & SortStats.DuplicateKeys.
+ *     v1.97.0 - October 15, 2026 - Added WithDedup, WithDuplicatesFile & SortStats.SuppressedRecords.
+ *     v1.98.0 - October 15, 2026 - Added Pool, its shared limits and the cancellation of its sorts.
+ *     v1.99.0 - October 15, 2026 - Added WithHeartbeat.
+ *     v1.100.0 - October 15, 2026 - Added SortOffsets & RecordSpan.
  *============================================================================================================================*/
 package mergesort
 
 import(
     "bufio"
+    "bytes"
+    "context"
+    "errors"
     "fmt"
+    "hash/crc32"
+    "hash/fnv"
     "io"
+    "io/fs"
     "io/ioutil"
-    "log"
     "math"
     "os"
-    "path/filepath"
     "runtime"
     "sort"
     "strconv"
     "strings"
     "sync"
+    "sync/atomic"
     "time"
 )
 //Exported ---------------------------------------------------------------------------------------------------------------------
-func Sort(inFile, outFile string, sortAsc bool, usingFields, sep string, keysPerSort int, verbose bool) {
+func Sort(inFile, outFile string, sortAsc bool, usingFields, sep string, keysPerSort int, verbose bool,
+          opts ...Option) (err error) {
 /*         Purpose : Does a stable, multi-index, partially concurrent hybrid merge sort of a text file.
  *       Arguments : inFile      = path of the file with the data to be sorted.
  *                   outFile     = path of the file for the sorted data.
@@ -36,260 +207,1054 @@ func Sort(inFile, outFile string, sortAsc bool, usingFields, sep string, keysPer
  *                                 descending order.
  *                   usingFields = CSV of field numbers to use as indexes, ordered as primary, secondary, etc., with the
  *                                 first field referenced as 1.
- *                   sep         = the field separator.
+ *                   sep         = the field separator, or "auto" for its detection per DetectSeparator.
  *                   keysPerSort = the number of elements for in-place sorting of the initial composite-key files.
  *                   verbose     = boolean flag for verbose mode. If true, the main execution stages will be echoed to Stdout.
- *         Returns : None.
- * Externals -  In : _sync4Merge, keyParams
+ *                   opts        = further options, see NewSorter.
+ *         Returns : Any error encountered.
+ * Externals -  In : None.
  * Externals - Out : None.
- *       Functions : createFile, createTempFile, halt, makeCompositeKeyFn, merge, openFile, readString, resetReader, seekFile,
-                     updateProgressBar
- *         Remarks : The temporary files are prefixed as "keys_" and wiil be stored on the temporary directory reported by the
- *                   OS. They will be deleted as soon as they have been processed.
+ *       Functions : catch, checkRemoval, halt, newLegacySorter, preserveAttributes, removeInputs, sortFile, useScratchDir
+ *         Remarks : The temporary files are prefixed as "keys_" followed by an identifier of the run, and wiil be stored on
+ *                   the temporary directory reported by the OS unless WithTempDir is given. They will be deleted as soon as
+ *                   they have been processed.
  *         History : v1.0.0 - November 19, 2016 - Original release.
+ *                   v1.1.0 - October 15, 2026 - Moved the key generation and merging stages to sortKeys.
+ *                   v1.7.0 - October 15, 2026 - Errors are now returned instead of terminating the program.
+ *                   v1.8.0 - October 15, 2026 - Added the trailing options.
  */
-    if inFile      == "" { halt("the input file was not specified") }
-    if outFile     == "" { halt("the output file was not specified") }
-    if usingFields == "" { halt("the index fields columns were not specified") }
-    if keysPerSort == 0  { halt("the number of keys for in-place sorting was not specified") }
-    fi, err := os.Stat(inFile)
-    if err != nil || fi.Size() == 0 { halt("the input file cannot be located or is empty") }
+    defer catch(&err)
+    if outFile == "" { halt("the output file was not specified") }
+    run := newLegacySorter(sortAsc, usingFields, sep, keysPerSort, verbose, opts).newRun()
+    run.checkRemoval(outFile, inFile)
+    defer run.useScratchDir(outFile)()
+    run.sortFile(inFile, outFile, nil)
+    run.preserveAttributes(inFile, outFile)
+    run.removeInputs(inFile)
+    return
+} //end func Sort
+func SortFS(fsys fs.FS, name, outFile string, sortAsc bool, usingFields, sep string, keysPerSort int, verbose bool,
+            opts ...Option) (err error) {
+/*         Purpose : Sorts a text file read from a file system rather than from the OS one.
+ *       Arguments : fsys        = the file system holding the data to be sorted, e.g. an embed.FS or an fstest.MapFS.
+ *                   name        = name of the file with the data to be sorted, per the conventions of fs.FS.
+ *                   outFile     = path of the file for the sorted data, on the OS file system.
+ *                   sortAsc     = boolean flag for requesting an ascending alphanumeric sort. If false, sorting will be in
+ *                                 descending order.
+ *                   usingFields = CSV of field numbers to use as indexes, ordered as primary, secondary, etc., with the
+ *                                 first field referenced as 1.
+ *                   sep         = the field separator.
+ *                   keysPerSort = the number of elements for in-place sorting of the initial composite-key files.
+ *                   verbose     = boolean flag for verbose mode. If true, the main execution stages will be echoed to Stdout.
+ *                   opts        = further options, see NewSorter.
+ *         Returns : Any error encountered.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : catch, newLegacySorter, RunFS
+ *         Remarks : See RunFS.
+ *         History : v1.10.0 - October 15, 2026 - Original release.
+ */
+    defer catch(&err)
+    return newLegacySorter(sortAsc, usingFields, sep, keysPerSort, verbose, opts).RunFS(fsys, name, outFile)
+} //end func SortFS
+func SortIndex(inFile, indexFile string, sortAsc bool, usingFields, sep string, keysPerSort int, verbose bool,
+               opts ...Option) (err error) {
+/*         Purpose : Produces the sort order of a text file without rewriting its data.
+ *       Arguments : inFile      = path of the file with the data to be sorted.
+ *                   indexFile   = path of the file for the sorted index entries.
+ *                   sortAsc     = boolean flag for requesting an ascending alphanumeric sort. If false, sorting will be in
+ *                                 descending order.
+ *                   usingFields = CSV of field numbers to use as indexes, ordered as primary, secondary, etc., with the
+ *                                 first field referenced as 1.
+ *                   sep         = the field separator.
+ *                   keysPerSort = the number of elements for in-place sorting of the initial composite-key files.
+ *                   verbose     = boolean flag for verbose mode. If true, the main execution stages will be echoed to Stdout.
+ *                   opts        = further options, see NewSorter.
+ *         Returns : Any error encountered.
+ * Externals -  In : _indexMagic
+ * Externals - Out : None.
+ *       Functions : catch, createOutput, halt, keySignature, newLegacySorter, openTemp, removeTemp, sortKeys, useScratchDir
+ *         Remarks : The index file starts with a header line holding the size and CRC-32 checksum of inFile, the number of
+ *                   entries and a digest of the options shaping the keys, followed by one entry per data record in sorted
+ *                   order. Each entry is a composite key, i.e. the formatted index fields, the record offset and the
+ *                   record length, the three being separated by the ascii group separator.
+ *         History : v1.1.0 - October 15, 2026 - Original release.
+ *                   v1.7.0 - October 15, 2026 - Errors are now returned instead of terminating the program.
+ *                   v1.8.0 - October 15, 2026 - Added the trailing options.
+ *                   v1.72.0 - October 15, 2026 - The header records the digest of the options shaping the keys.
+ */
+    defer catch(&err)
+    if indexFile == "" { halt("the index file was not specified") }
 
-    var(
-        start                 = time.Now()                        //record start of execution
-        keys sort.StringSlice = []string{}                        //data keys
-        recordStart           int64                               //data-record offset relative to the origin of the file
-        tempDir               = filepath.ToSlash(os.TempDir())    //temporary directory for the merged files
-        pattern4merged        = fmt.Sprintf("%s/keys_*", tempDir) //glob pattern for the temporary merged files
-        todo                  = []string{}                        //key files to be processed
+    run                               := newLegacySorter(sortAsc, usingFields, sep, keysPerSort, verbose, opts).newRun()
+    run.indexing                       = true
+    defer run.useScratchDir(indexFile)()
+    sortedKeysFile, numKeys, checksum := run.sortKeys(inFile)
+    defer run.removeTemp(sortedKeysFile)
+    //Copy the sorted keys to the index file after its header
+    fi, err := os.Stat(inFile)
+    if err != nil { halt("os.Stat - " + err.Error()) }
+    fhKeys, scannerKeys := run.openKeys(sortedKeysFile)
+    defer fhKeys.Close()
+    fhIndex   := run.createOutput(indexFile)
+    defer fhIndex.Close()
+    writer    := bufio.NewWriter(fhIndex)
+    fmt.Fprintln(writer, strings.Join([]string{_indexMagic, strconv.FormatInt(fi.Size(), 10),
+                                               strconv.FormatUint(uint64(checksum), 16), strconv.Itoa(numKeys),
+                                               run.keySignature()}, _asciiGS))
+    for scannerKeys.Scan() { fmt.Fprintln(writer, scannerKeys.Text()) } //the keys without the trailer of their file
+    if err := scannerKeys.Err(); err != nil { haltKind(nil, "scannerKeys.Scan", err) }
+    if err := writer.Flush();    err != nil { halt("writer.Flush - " + err.Error()) }
+    if err := fhIndex.Sync();  err != nil { halt("fhIndex.Sync - " + err.Error()) }
+    if err := fhIndex.Close(); err != nil { halt("fhIndex.Close - " + err.Error()) }
+    if verbose { fmt.Println("func SortIndex - created", indexFile, "with", numKeys, "entries in", time.Since(run.start)) }
+    return
+} //end func SortIndex
+func ApplyIndex(inFile, indexFile, outFile string) (err error) {
+/*         Purpose : Creates the sorted copy of a text file from an index produced by SortIndex.
+ *       Arguments : inFile    = path of the file with the data to be sorted.
+ *                   indexFile = path of the index file produced by SortIndex for inFile.
+ *                   outFile   = path of the file for the sorted data.
+ *         Returns : Any error encountered.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : catch, fileChecksum, halt, newSorter, openFile, readIndexHeader, writeRecords
+ *         Remarks : The size and checksum of inFile are checked against those recorded in the index header, and the sort
+ *                   is refused if they differ as the offsets would then be meaningless.
+ *         History : v1.3.0 - October 15, 2026 - Original release.
+ *                   v1.7.0 - October 15, 2026 - Errors are now returned instead of terminating the program.
+ */
+    defer catch(&err)
+    if inFile    == "" { halt("the input file was not specified") }
+    if indexFile == "" { halt("the index file was not specified") }
+    if outFile   == "" { halt("the output file was not specified") }
+    fi, err := os.Stat(inFile)
+    if err != nil { haltKind(ErrInputNotFound, "the input file cannot be located", err) }
 
-        chan4command          = make(chan string,    1)           //merge channel for signalling
-        chan4tasks            = make(chan [2]string, 1)           //merge channel for key files to merge
-    )
+    fhIndex, _                := openFile(indexFile)
+    defer fhIndex.Close()
+    scannerIndex              := bufio.NewScanner(fhIndex)
+    size, checksum, numKeys, _ := readIndexHeader(scannerIndex)
+    if size != fi.Size() || checksum != fileChecksum(inFile) {
+        haltKind(ErrIndexMismatch, "the index file does not match the input file", nil)
+    }
+    fhIn, _                   := openFile(inFile)
+    defer fhIn.Close()
+    newSorter(nil).newRun().writeRecords(fhIn, outFile, indexFile, scannerIndex, numKeys, nil)
+    return
+} //end func ApplyIndex
+func SortWithKeys(inFile, keysFile, outFile string, opts ...Option) (err error) {
+/*         Purpose : Sorts a text file on the keys of an index produced by SortIndex with the same options, without keying it.
+ *       Arguments : inFile   = path of the file with the data to be sorted.
+ *                   keysFile = path of the index file produced by SortIndex for inFile.
+ *                   outFile  = path of the file for the sorted data.
+ *                   opts     = the options, see NewSorter, those shaping the keys being the ones given to SortIndex.
+ *         Returns : Any error encountered.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : catch, checkOutput, checkRemoval, fileChecksum, halt, keySignature, NewSorter, openFile,
+ *                   preserveAttributes, readIndexHeader, removeInputs, resolveSeparator, writeRecords
+ *         Remarks : Unlike ApplyIndex, the output honours the sorter's options, e.g. the rank columns, and the index is
+ *                   checked against them: the digest of the options shaping the keys recorded in its header, e.g. the
+ *                   index fields, their types, the sort direction and the separator, must be that of the sorter, and the
+ *                   size and CRC-32 checksum of inFile must be those recorded, so that a stale or mismatched index fails
+ *                   with ErrIndexMismatch rather than being applied. Indexes written before v1.72.0 record no digest and
+ *                   are refused. The keying and the merges are skipped, the input being read once for its checksum and
+ *                   then as the records are output. WithLineRange and WithVerifyOutput are not supported, the records
+ *                   outside a range and the digests of the input records being known only by keying it.
+ *         History : v1.72.0 - October 15, 2026 - Original release.
+ */
+    defer catch(&err)
+    if inFile   == "" { halt("the input file was not specified") }
+    if keysFile == "" { halt("the key file was not specified") }
+    if outFile  == "" { halt("the output file was not specified") }
+    sorter, err := NewSorter(opts...)
+    if err != nil { panic(haltError{err}) }
+    if sorter.lineRange || sorter.verify { halt("WithLineRange and WithVerifyOutput do not apply to presorted keys") }
+    fi, err := os.Stat(inFile)
+    if err != nil { haltKind(ErrInputNotFound, "the input file cannot be located", err) }
 
-    if verbose { fmt.Println("func Sort - temporary directory =", tempDir) }
-    //Launch coroutine for merging the composite-key files
-    _sync4Merge.Add(1)
-    go merge(sortAsc, chan4command, chan4tasks, verbose)
-    //Get the number of fields from the first record
-    fhIn, _   := openFile(inFile)
+    run := sorter.newRun()
+    run.checkRemoval(outFile, inFile)
+    run.resolveSeparator(inFile)
+    fhKeys, _                          := openFile(keysFile)
+    defer fhKeys.Close()
+    scannerKeys                        := bufio.NewScanner(fhKeys)
+    size, checksum, numKeys, signature := readIndexHeader(scannerKeys)
+    switch {
+        case signature == "":
+            haltKind(ErrIndexMismatch, "the key file records no digest of its options, being from an earlier release", nil)
+        case signature != run.keySignature():
+            haltKind(ErrIndexMismatch, "the key file was produced with other options than those of the sort", nil)
+        case size != fi.Size() || checksum != fileChecksum(inFile):
+            haltKind(ErrIndexMismatch, "the key file does not match the input file", nil)
+    }
+    fhIn, _ := openFile(inFile)
     defer fhIn.Close()
-    readerIn  := bufio.NewReader(fhIn)
-    record, _ := readString(readerIn)
-    numFields := len(strings.Split(record, sep))
-    if verbose { fmt.Println("func Sort - number of fields =", numFields) }
-    //Get the field widths
-    widths := make([]float64, numFields)
-    errIn  := resetReader(fhIn, readerIn)
-    for errIn != io.EOF {
-        record, errIn = readString(readerIn)
-        record        = strings.Trim(record, " \r\n")
-        for k, v := range strings.Split(record, sep) {
+    run.writeRecords(fhIn, outFile, keysFile, scannerKeys, numKeys, nil)
+    run.checkOutput(nil)
+    if run.verbose { fmt.Println("func SortWithKeys - created", outFile, "in", time.Since(run.start)) }
+    fhIn.Close()                                                  //an open file cannot be removed on Windows
+    run.preserveAttributes(inFile, outFile)
+    run.removeInputs(inFile)
+    return
+} //end func SortWithKeys
+func SortAndReduce(inFile, outFile string, sortAsc bool, usingFields, sep string, keysPerSort int, verbose bool,
+                   reduce func(key, record string, lastInGroup bool) []string, opts ...Option) (err error) {
+/*         Purpose : Sorts a text file and reduces each group of records sharing the same index-field values.
+ *       Arguments : inFile      = path of the file with the data to be sorted.
+ *                   outFile     = path of the file for the reduced data.
+ *                   sortAsc     = boolean flag for requesting an ascending alphanumeric sort. If false, sorting will be in
+ *                                 descending order.
+ *                   usingFields = CSV of field numbers to use as indexes, ordered as primary, secondary, etc., with the
+ *                                 first field referenced as 1.
+ *                   sep         = the field separator.
+ *                   keysPerSort = the number of elements for in-place sorting of the initial composite-key files.
+ *                   verbose     = boolean flag for verbose mode. If true, the main execution stages will be echoed to Stdout.
+ *                   reduce      = function called for every record in sorted order with the record's index-field values
+ *                                 joined by sep, the record stripped of its terminator, and a flag set on the last record
+ *                                 of its group. The records it returns are written to outFile, each followed by a newline.
+ *                   opts        = further options, see NewSorter.
+ *         Returns : Any error encountered.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : catch, checkRemoval, halt, newLegacySorter, preserveAttributes, removeInputs, sortFile, useScratchDir
+ *         Remarks : The reducer is fed one record at a time during the output phase, so a group never needs to be held in
+ *                   memory. It would typically accumulate sums, counts or extrema and return the collapsed record(s) when
+ *                   lastInGroup is set, and nil otherwise. The group boundaries are determined by the index fields only,
+ *                   excluding the record offsets. It is called from a single goroutine.
+ *         History : v1.5.0 - October 15, 2026 - Original release.
+ *                   v1.7.0 - October 15, 2026 - Errors are now returned instead of terminating the program.
+ *                   v1.8.0 - October 15, 2026 - Added the trailing options.
+ */
+    defer catch(&err)
+    if outFile == "" { halt("the output file was not specified") }
+    if reduce  == nil { halt("the reduce function was not specified") }
+    run := newLegacySorter(sortAsc, usingFields, sep, keysPerSort, verbose, opts).newRun()
+    run.checkRemoval(outFile, inFile)
+    defer run.useScratchDir(outFile)()
+    if inFile != "" { run.resolveSeparator(inFile) }
+    run.sortFile(inFile, outFile, &groupReducer{fn:reduce, sep:run.sep, colIdxs:run.colIdxs})
+    run.preserveAttributes(inFile, outFile)
+    run.removeInputs(inFile)
+    return
+} //end func SortAndReduce
+func SortStrings(records []string, sortAsc bool, usingFields, sep string) error {
+/*         Purpose : Sorts a slice of records in place with the same ordering rules as Sort.
+ *       Arguments : records     = the records to be sorted.
+ *                   sortAsc     = boolean flag for requesting an ascending alphanumeric sort. If false, sorting will be in
+ *                                 descending order.
+ *                   usingFields = CSV of field numbers to use as indexes, ordered as primary, secondary, etc., with the
+ *                                 first field referenced as 1.
+ *                   sep         = the field separator.
+ *         Returns : Any error in the field specification.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : keyIndexPart, makeCompositeKeyFn, makeKeySpecs, parseColumns
+ *         Remarks : The composite keys are built exactly as Sort builds them, and sort.SliceStable keeps records with equal
+ *                   index fields in their input order, so the result can serve as a reference for the external sort.
+ *                   Unlike Sort, which drops them, records that are blank once trimmed are kept and sort as though all
+ *                   their fields were empty.
+ *         History : v1.6.0 - October 15, 2026 - Original release.
+ */
+    colIdxs, err := parseColumns(usingFields)
+    if err != nil { return fmt.Errorf("mergesort: %w", err) }
+
+    widths := []float64{}
+    for _, record := range records {
+        for k, v := range strings.Split(strings.Trim(record, " \r\n"), sep) {
+            if k == len(widths) { widths = append(widths, 0) }
             widths[k] = math.Max(widths[k], float64(len(v)))
         }
     }
-    if verbose {
-        fmt.Println("func Sort - field widths:")
-        for k, v := range widths {
-            fmt.Println("       column #", k + 1, ":", v)
+    split          := func(record string) []string { return strings.Split(record, sep) }
+    compositeKeyFn := makeCompositeKeyFn(split, makeKeySpecs(colIdxs, widths), 1)
+    keys           := make([]string, len(records))
+    sorted         := make([]string, len(records))
+    order          := make([]int,    len(records))
+    for k, record := range records {
+        keys[k]  = keyIndexPart(compositeKeyFn(strings.Trim(record, " \r\n"), 0, 0))
+        order[k] = k
+    }
+    sort.SliceStable(order, func(i, j int) bool {
+        if sortAsc { return keys[order[i]] < keys[order[j]] }
+        return keys[order[i]] > keys[order[j]]
+    })
+    for k, v := range order { sorted[k] = records[v] }
+    copy(records, sorted)
+    return nil
+} //end func SortStrings
+func SortChan(ctx context.Context, in <-chan string, sortAsc bool, usingFields, sep string, keysPerSort int,
+              verbose bool, opts ...Option) (<-chan string, <-chan error) {
+/*         Purpose : Sorts the records received on a channel and emits them in sorted order on another.
+ *       Arguments : ctx         = context whose cancellation aborts the sort.
+ *                   in          = channel of the records to be sorted. It must be closed by the producer once all the
+ *                                 records have been sent.
+ *                   sortAsc     = boolean flag for requesting an ascending alphanumeric sort. If false, sorting will be in
+ *                                 descending order.
+ *                   usingFields = CSV of field numbers to use as indexes, ordered as primary, secondary, etc., with the
+ *                                 first field referenced as 1.
+ *                   sep         = the field separator.
+ *                   keysPerSort = the number of elements for in-place sorting of the initial composite-key files.
+ *                   verbose     = boolean flag for verbose mode. If true, the main execution stages will be echoed to Stdout.
+ *                   opts        = further options, see NewSorter.
+ *         Returns : The unbuffered channel of the sorted records, without terminators, and a channel receiving at most one
+ *                   error. Both are closed once the sort ends.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : NewSorter, sortChan
+ *         Remarks : As the composite keys depend on the field widths over all the records, the incoming records are
+ *                   spooled to a temporary file prefixed as "spool_" which is sorted once the input channel is closed.
+ *                   The output channel being unbuffered, the sort proceeds at the pace of its consumer. On cancellation,
+ *                   the input channel is drained in the background, the temporary files are removed and an error
+ *                   matching both ErrInterrupted and ctx.Err() is sent on the error channel.
+ *         History : v1.7.0 - October 15, 2026 - Original release.
+ *                   v1.8.0 - October 15, 2026 - Added the trailing options.
+ *                   v1.33.0 - October 15, 2026 - The cancellation error now matches ErrInterrupted.
+ */
+    chanOut := make(chan string)
+    chanErr := make(chan error, 1)
+    go func() {
+        defer close(chanErr)
+        defer close(chanOut)
+        s, err := NewSorter(append([]Option{WithAscending(sortAsc), WithFields(usingFields), WithSeparator(sep),
+                                            WithKeysPerSort(keysPerSort), WithVerbose(verbose)}, opts...)...)
+        if err == nil { err = s.newRun().sortChan(ctx, in, chanOut) }
+        if err != nil { chanErr<- err }
+    }()
+    return chanOut, chanErr
+} //end func SortChan
+//Private ----------------------------------------------------------------------------------------------------------------------
+type inputFile interface {
+    io.ReadSeeker
+    io.Closer
+}
+type haltError struct {
+    err error
+}
+type groupReducer struct {
+    fn      func(key, record string, lastInGroup bool) []string
+    sep     string
+    colIdxs []int
+}
+type keyParams struct {
+    COLIDX  int
+    FORMAT  string
+    HASHED  bool                 //field preceded by its keyed digest
+    SEED    uint64               //key of the digest
+    TYPE    FieldType            //type of the field
+    INVHIGH bool                 //typed values that do not parse encoded as following all others
+    PREPARE func(string) string  //transformation of the values before their encoding, or nil
+    NULLPRE string               //prefix of the empty values, "" if they are not placed
+    PARSING fieldParsing         //settings of the parsing of the typed values
+    ZEROPAD string               //format of the digits of a zero-padded field, "" if not zero-padded
+    STRICT  bool                 //zero-padded field whose values must be all digits
+    WIDTH   int                  //sampled width that the text values may not exceed, -1 if measured exactly
+    DERIVE  keyDeriver           //computation of a derived key from the fields, in lieu of COLIDX, or nil
+    DESC    bool                 //field ordered against the sort direction, its part of the key being reversed
+}
+const(
+    _indexMagic     = "mergesort-index-v1"
+    _progressBarLen = 50
+)
+var _asciiGS = fmt.Sprintf("%c", 29) //ascii character for group separator
+////Channel sort
+func (r *sortRun) sortChan(ctx context.Context, in <-chan string, chanOut chan<- string) (err error) {
+    defer func() {
+        if err != nil { go func() { for range in {} }() }        //drain the input so that the producer never blocks
+    }()
+    defer catch(&err)
+    defer r.startHeartbeat()()
+    //Spool the incoming records
+    fhSpool, spoolFile := r.createTemp("spool_")
+    defer r.removeTemp(spoolFile)
+    defer fhSpool.Close()
+    writer := bufio.NewWriter(fhSpool)
+    spoolLoop: for {
+        select {
+            case record, ok := <-in:
+                if !ok { break spoolLoop }
+                if !strings.HasSuffix(record, "\n") { record += "\n" }
+                if _, err := writer.WriteString(record); err != nil { haltTemp("writer.WriteString", err) }
+            case <-ctx.Done():
+                return interrupted(ctx.Err())
         }
     }
-    //Define the field formats for the composite keys
-    keySpecs := []keyParams{}
-    for _, v := range strings.Split(usingFields, ",") {
-        colIdx, err := strconv.Atoi(v); colIdx--
-        if err != nil { halt("the specification of the sort columns is syntactically incorrect") }
-        keyFormat := fmt.Sprintf("%%%vs", widths[colIdx])
-        keySpecs   = append(keySpecs, keyParams{COLIDX:colIdx, FORMAT:keyFormat})
-    }
-    //Create files of composite keys with seek pointers on the temp directory and enqueue merge tasks
-    numKeys, numRecs := 0, 0
-    compositeKeyFn   := makeCompositeKeyFn(sep, keySpecs, len(strconv.FormatInt(fi.Size(), 10)))
-    errIn             = resetReader(fhIn, readerIn)
+    if err := writer.Flush(); err != nil { haltTemp("writer.Flush", err) }
+    size, _ := fhSpool.Seek(0, io.SeekCurrent)
+    if err := fhSpool.Close(); err != nil { haltTemp("fhSpool.Close", err) }
+    if size == 0 { return nil }
+    //Sort the spooled records & emit them in order
+    r.spooled             = true
+    sortedKeysFile, _, _ := r.sortKeys(spoolFile)
+    defer r.removeTemp(sortedKeysFile)
+    fhKeys, scannerKeys := r.openKeys(sortedKeysFile)
+    defer fhKeys.Close()
+    fhSpool = r.openTemp(spoolFile)
+    defer fhSpool.Close()
+    r.readRecords(readerAt(fhSpool), sortedKeysFile, scannerKeys, func(key, record string, lastInGroup bool) bool {
+        select {
+            case chanOut<- strings.TrimRight(record, "\r\n"):
+                return true
+            case <-ctx.Done():
+                return false
+        }
+    })
+    return interrupted(ctx.Err())
+} //end func sortChan
+////File sort
+func (r *sortRun) sortFile(inFile, outFile string, reducer *groupReducer) {
+    if inFile == "" { halt("the input file was not specified") }
+    defer r.startHeartbeat()()
+    r.resolveSeparator(inFile)
+    fhIn, size := r.openInput(inFile)
+    defer fhIn.Close()
+    if size > 0 && 2 * size <= r.memBudget {                      //input and keys fit in the memory budget
+        r.sortInMemory(fhIn, size, outFile, reducer)
+    } else {
+        sortedKeysFile, numKeys, _ := r.sortKeys(inFile)
+        defer r.removeTemp(sortedKeysFile)
+        //Read sorted keys & output corresponding data records
+        fhKeys, scannerKeys := r.openKeys(sortedKeysFile)
+        defer fhKeys.Close()
+        r.writeRecords(fhIn, outFile, sortedKeysFile, scannerKeys, numKeys, reducer)
+    }
+    r.checkOutput(reducer)
+    if r.verbose { fmt.Println("func Sort - created", outFile, "in", time.Since(r.start)) }
+    return
+} //end func sortFile
+func (r *sortRun) sortInMemory(fhIn inputFile, size int64, outFile string, reducer *groupReducer) {
+    //Builds & sorts the keys of an input read whole, then outputs its records as the external path does
+    data, err := ioutil.ReadAll(fhIn)
+    if err != nil { halt("ioutil.ReadAll - " + err.Error()) }
+    if r.verbose { fmt.Println("func Sort - sorting", size, "bytes in memory") }
+    defer r.openRejects()()
+    input          := bytes.NewReader(data)
+    readerIn       := bufio.NewReader(input)
+    scanned        := r.timePhase("width scan")
+    keySpecs, _    := r.scanFields(input, readerIn, false)
+    scanned(size)
+    keyed          := r.timePhase("key generation")
+    progress       := r.trackRead("key generation", size)
+    compositeKeyFn := makeCompositeKeyFn(r.keyFields(), keySpecs, len(strconv.FormatInt(size, 10)))
+    keys           := []string{}
+    recordStart    := int64(0)
+    numRecs        := 0
+    errIn          := resetReader(input, readerIn)
+    at             := RecordError{}                              //record being keyed
+    defer blame(&at)
     for errIn != io.EOF {
-        record, errIn  = readString(readerIn)
+        var record string
+        at             = RecordError{Line:numRecs + 1, Offset:recordStart}
+        record, errIn  = r.readRecord(readerIn)
+        at.Record      = strings.TrimRight(record, "\r\n")
         recordLen     := len(record)
+        trimmed       := r.trimRecord(record)
         numRecs++
-        if record = strings.Trim(record, " \r\n"); len(record) > 0 {
-            keys = append(keys, compositeKeyFn(record, recordStart))
-            numKeys++
+        if r.admits(record, trimmed, numRecs) {
+            if key, ok := r.keyRecord(compositeKeyFn, &at, record, trimmed); ok {
+                keys = append(keys, r.numberKey(key, numRecs))
+                r.countInput(record)
+            }
         }
         recordStart += int64(recordLen)
-        if len(keys) > 0 && (len(keys) == keysPerSort || errIn == io.EOF) {
-            fhKeys, tempFile := createTempFile()
-            if sortAsc { keys.Sort() } else { sort.Sort(sort.Reverse(keys[:])) }
-            for _, v := range keys {
-                fmt.Fprintln(fhKeys, v)
+        if recordLen > 0 { r.trackRange(numRecs, recordStart) }
+        progress.advance(recordStart)
+    }
+    progress.finish(recordStart)
+    at = RecordError{}
+    r.sortRunKeys(keys)
+    if r.freqOrder { r.frequencyOrder(keys) }
+    keyed(size)
+    r.writeRecords(input, outFile, "", bufio.NewScanner(strings.NewReader(strings.Join(keys, "\n"))), len(keys), reducer)
+    return
+} //end func sortInMemory
+////Key generation & merging
+func (r *sortRun) sortKeys(inFile string) (string, int, uint32) {
+    if inFile == "" { halt("the input file was not specified") }
+    r.resolveSeparator(inFile)
+    fhIn, size := r.openInput(inFile)
+    defer fhIn.Close()
+    if size == 0 { haltKind(ErrEmptyInput, "the input file is empty", nil) }
+
+    if r.verbose { fmt.Println("func Sort - temporary directory =", r.tempDir) }
+    r.planTempSpace(inFile, size)
+    defer r.openRejects()()
+    readerIn           := bufio.NewReader(fhIn)
+    scanned            := r.timePhase("width scan")
+    keySpecs, checksum := r.scanFields(fhIn, readerIn, r.sampled)
+    scanned(r.scanLen(size))
+    sortedKeysFile, numKeys, keyedSum, overflow := r.generateKeys(fhIn, readerIn, size, keySpecs, r.sampled)
+    if overflow != nil {                                          //sampled widths too narrow: start over with exact ones
+        if r.verbose { fmt.Println("func Sort -", overflow, "- restarting with the exact widths") }
+        resetReader(fhIn, readerIn)
+        scanned            = r.timePhase("width scan")
+        keySpecs, checksum = r.scanFields(fhIn, readerIn, false)
+        scanned(size)
+        sortedKeysFile, numKeys, _, _ = r.generateKeys(fhIn, readerIn, size, keySpecs, false)
+    } else if r.sampled {
+        checksum = keyedSum
+    }
+    if r.freqOrder { sortedKeysFile = r.orderByFrequency(sortedKeysFile) }
+    return sortedKeysFile, numKeys, checksum
+} //end func sortKeys
+func (r *sortRun) generateKeys(fhIn io.ReadSeeker, readerIn *bufio.Reader, size int64, keySpecs []keyParams,
+                               sampled bool) (sortedKeysFile string, numKeys int, checksum uint32, overflow *widthError) {
+    //Creates the composite keys of the input and sorts them; with sampled widths, also computes the input's checksum and
+    //counts its invalid values, and returns the first value overflowing its width if a restart is allowed
+    var recordStart int64                                         //data-record offset relative to the origin of the file
+
+    stats, passes := r.stats, atomic.LoadInt64(&r.counters.passes)
+    defer func() {
+        if p := recover(); p != nil {
+            h, ok := p.(haltError)
+            if !ok || !r.sampleRestart || !errors.As(h.err, &overflow) { panic(p) }
+            r.stats, r.faults = stats, faultLog{}                 //the discarded keys are not counted
+            r.resetRejects()
+            atomic.StoreInt64(&r.counters.passes, passes)
+        }
+    }()
+    //Create the composite keys with seek pointers and sort them
+    numRecs        := 0
+    maxCol         := r.lastIndexField()
+    compositeKeyFn := makeCompositeKeyFn(r.keyFields(), keySpecs, len(strconv.FormatInt(size, 10)))
+    sortedKeysFile  = r.externalSort(func(emit func(key string)) {
+        var at RecordError                                        //record being keyed
+
+        defer blame(&at)
+        keyed    := r.timePhase("key generation")                 //run flushes included
+        progress := r.trackRead("key generation", size)
+        errIn    := resetReader(fhIn, readerIn)
+        for errIn != io.EOF {
+            var record string
+            at             = RecordError{Line:numRecs + 1, Offset:recordStart}
+            record, errIn  = r.readRecord(readerIn)
+            at.Record      = strings.TrimRight(record, "\r\n")
+            recordLen     := len(record)
+            numRecs++
+            trimmed       := r.trimRecord(record)
+            if sampled {
+                checksum = crc32.Update(checksum, crc32.IEEETable, []byte(record))
+                if len(trimmed) > 0 && r.inRange(numRecs) { r.countInvalid(r.splitFields(trimmed, maxCol + 2)) }
             }
-            if err := fhKeys.Sync();  err != nil { halt("fhKeys.Sync - " + err.Error()) }
-            if err := fhKeys.Close(); err != nil { halt("fhKeys.Close - " + err.Error()) }
-            if verbose { fmt.Println("func Sort - created", filepath.Base(tempFile)) }
-            todo = append(todo, tempFile)
-            if len(todo) == 2 {
-                chan4tasks<- [2]string{todo[0], todo[1]}
-                todo = nil
+            if r.admits(record, trimmed, numRecs) {
+                if key, ok := r.keyRecord(compositeKeyFn, &at, record, trimmed); ok {
+                    emit(r.payloadKey(r.numberKey(key, numRecs), record))
+                    r.countInput(record)
+                    numKeys++
+                }
             }
-            keys = nil
+            recordStart += int64(recordLen)
+            if recordLen > 0 { r.trackRange(numRecs, recordStart) }
+            progress.advance(recordStart)
+        }
+        progress.finish(recordStart)
+        keyed(recordStart)
+        if r.verbose { fmt.Println("func Sort - created", numKeys, "keys for", numRecs, "data records") }
+    })
+    return
+} //end func generateKeys
+func (r *sortRun) externalSort(produce func(emit func(key string))) string {
+    //Sorts the keys emitted by produce in runs of runSize, merges the run files in the background and returns the file
+    //holding all the keys in order
+    r.runSize = r.keysPerSort
+    if r.maxLiveRuns > 0 || r.continuous && !r.deterministic { return r.mergeContinuously(produce) }
+    var(
+        keys                  = []string{}                        //keys of the current run
+        todo                  = []string{}                        //key files to be processed
+
+        chan4command          = make(chan string,    1)           //merge channel for signalling
+        chan4tasks            = make(chan []string,  1)           //merge channel for key files to merge
+        chan4done             = make(chan struct{})               //merge channel closed on exit
+        errMerge              error                               //first error encountered by the merge coroutine
+        merging               bool                                //merge tasks enqueued while the runs are created
+        passTimed             func()                              //records the first pass, once its merges are done
+        openTasks             int                                 //merge tasks enqueued while the runs are created
+        stopped               bool                                //merge coroutine stopped
+        sync4Merge            sync.WaitGroup                      //completion of the enqueued merge tasks
+    )
+
+    //Launch coroutine for merging the composite-key files
+    r.progress = &mergeTracker{fn:r.onMerge, spoke:&r.counters.spoke}
+    sync4Merge.Add(1)
+    go r.merge(chan4command, chan4tasks, chan4done, &sync4Merge, &errMerge)
+    stop := func() {                                              //stops the coroutine, once
+        if stopped { return }
+        chan4command<- "quit"
+        <-chan4done
+        stopped = true
+        if r.verbose { fmt.Println("func Sort - sent quit signal") }
+    }
+    defer func() {
+        stop()
+        if p := recover(); p != nil {                             //on failure, remove the remaining key files
+            r.abandonPack()
+            files, _ := r.storage.List(r.prefix)
+            for _, v := range files { r.removeTemp(v) }
+            panic(p)
+        }
+    }()
+    //Create files of sorted keys on the temporary storage and enqueue merge tasks
+    enqueue := func(tasks []string) {                             //merge at once if deterministic
+        if !r.deterministic {
+            chan4tasks<- tasks
+        } else if _, err := r.mergeFiles(tasks); err != nil {
+            panic(haltError{err})
         }
     }
-    if verbose { fmt.Println("func Sort - created", numKeys, "keys for", numRecs, "data records") }
-    //Get list of merged files and enqueue further merge tasks until only one file remaining
+    addRun := func(name string) {
+        todo = append(todo, name)
+        if len(todo) == r.mergeFanIn() {
+            if !merging {                                         //first pass, overlapping the runs
+                atomic.AddInt64(&r.counters.passes, 1)
+                passTimed = r.timePass()
+                r.progress.beginOpen()
+            }
+            openTasks++
+            enqueue(todo)
+            todo, merging = nil, true
+        }
+    }
+    writeRun := func() {
+        for _, name := range r.flushRun(keys) { addRun(name) }
+        r.resizeRun(keys)
+        keys = nil
+    }
+    produce(func(key string) {
+        keys = append(keys, key)
+        if len(keys) == r.runSize || r.underPressure(len(keys)) { writeRun() }
+    })
+    if len(keys) > 0 { writeRun() }
+    for _, name := range r.closePack() { addRun(name) }
+    if merging { r.progress.settle(openTasks, openTasks + len(todo)) }
+    //Get list of merged files and merge them, the smallest first, until only one file remaining
     chan4command<- "e-o-t"
-    if verbose { fmt.Println("func Sort - sent end-of-tasks signal") }
-    _sync4Merge.Wait()
-    todo, _ = filepath.Glob(pattern4merged)
-    for len(todo) > 1 {
-        if verbose { fmt.Printf("func Sort - %d files pending\n", len(todo)) }
-        _sync4Merge.Add(1)
-        for len(todo) > 1 {
-            chan4tasks<- [2]string{todo[0], todo[1]}
-            todo = todo[2:]
+    if r.verbose { fmt.Println("func Sort - sent end-of-tasks signal") }
+    sync4Merge.Wait()
+    if errMerge != nil { panic(haltError{errMerge}) }
+    if passTimed != nil { passTimed() }
+    stop()                                                        //the coroutine, polling, would compete with the last pass
+    todo = r.listKeys(r.prefix)
+    if len(todo) > 1 { return r.mergeSmallestFirst(todo, "Sort", !r.deterministic) }
+    if len(todo) == 0 { return r.emptyKeyFile() }                 //no keys
+    return todo[0]
+} //end func externalSort
+func (r *sortRun) emptyKeyFile() string {
+    //Creates a key file holding no keys, returning its name
+    fhKeys, tempFile := r.createTemp(r.prefix)
+    r.newKeyWriter(fhKeys).close()
+    fhKeys.Close()
+    return tempFile
+} //end func emptyKeyFile
+func (r *sortRun) writeRunFile(keys []string) string {
+    //Sorts the keys of a run & writes them to a new temporary file, returning its name
+    r.giveWay()
+    r.limiter.acquire(1)
+    defer r.limiter.release(1)
+    fhKeys, tempFile := r.createTemp(r.prefix)
+    defer fhKeys.Close()                                          //on a failed write; closing twice is harmless
+    r.sortRunKeys(keys)
+    writer := r.newKeyWriter(fhKeys)
+    for _, v := range keys {
+        writer.write(v)
+    }
+    writer.close()
+    if err := fhKeys.Sync();  err != nil { haltTemp("fhKeys.Sync", err) }
+    if err := fhKeys.Close(); err != nil { haltTemp("fhKeys.Close", err) }
+    if r.verbose { fmt.Println("func Sort - created", r.tempLabel(tempFile) + r.placement(tempFile)) }
+    return tempFile
+} //end func writeRunFile
+func (r *sortRun) scanFields(fhIn io.ReadSeeker, readerIn *bufio.Reader, sample bool) ([]keyParams, uint32) {
+    //Determines the field formats of the composite keys from the field widths, measured exactly along with the input's
+    //CRC-32 checksum or estimated from a sample
+    var at, first RecordError                                     //record being scanned, first one of the range
+
+    defer blame(&at)
+    //Get the number of fields from the first record of the line range
+    at             = RecordError{Line:1, Offset:0}
+    record, errIn := r.readRecord(readerIn)
+    lineNum       := 1
+    for offset := int64(len(record)); !r.inRange(lineNum) && errIn != io.EOF; lineNum++ {
+        at            = RecordError{Line:lineNum + 1, Offset:offset}
+        record, errIn = r.readRecord(readerIn)
+        first.Offset  = offset
+        offset       += int64(len(record))
+    }
+    at = RecordError{}
+    first.Line, first.Record = lineNum, strings.TrimRight(record, "\r\n")
+    keyed         := r.inRange(lineNum) && (!r.lineRange || len(record) > 0) //false for a range past the input
+    numFields     := len(r.splitFields(r.firstLine(record), -1))
+    if r.verbose { fmt.Println("func Sort - number of fields =", numFields) }
+    //Get the widths of the index fields, the records being split no further than the last of them
+    var(
+        checksum uint32
+        widths   []float64
+    )
+    if sample {
+        widths = r.sampleWidths(fhIn, numFields)
+    } else {
+        size, _ := fhIn.Seek(0, io.SeekEnd)
+        progress := r.trackRead("width scan", size)
+        widths    = make([]float64, numFields)
+        errIn     = resetReader(fhIn, readerIn)
+        offset   := int64(0)
+        for lineNum := 1; errIn != io.EOF; lineNum++ {
+            at            = RecordError{Line:lineNum, Offset:offset}
+            record, errIn = r.readRecord(readerIn)
+            at.Record     = strings.TrimRight(record, "\r\n")
+            offset       += int64(len(record))
+            progress.advance(offset)
+            checksum      = crc32.Update(checksum, crc32.IEEETable, []byte(record))
+            record        = r.trimRecord(record)
+            if !r.inRange(lineNum) { continue }
+            fields := r.measureFields(widths, record)
+            if len(record) > 0 { r.countInvalid(fields) }
         }
-        chan4command<- "e-o-t"
-        if verbose { fmt.Println("func Sort - sent end-of-tasks signal") }
-        _sync4Merge.Wait()
-        todo, _ = filepath.Glob(pattern4merged)
-    }
-    chan4command<- "quit"
-    if verbose { fmt.Println("func Sort - sent quit signal") }
-    //Read sorted keys & output corresponding data records
-    sortedKeysFile := todo[0]                  //open sorted keys file for read
-    fhKeys, _      := openFile(sortedKeysFile)
-    scannerKeys    := bufio.NewScanner(fhKeys)
-    fhOut          := createFile(outFile)      //create destination file for sorted data
-    numRecs         = 0
-    for scannerKeys.Scan() {
-        readerIn.Discard(readerIn.Buffered())
-        seekFile(fhIn, (strings.Split(scannerKeys.Text(), _asciiGS))[1])
-        record, _ = readString(readerIn)
-        fmt.Fprint(fhOut, record)
-        if verbose {
-            numRecs++
-            updateProgressBar("func Sort - creating outFile", numRecs, numKeys)
+        progress.finish(offset)
+    }
+    if r.verbose {
+        fmt.Println("func Sort - field widths:")
+        for _, k := range r.colIdxs {
+            if k < numFields { fmt.Println("       column #", k + 1, ":", widths[k]) }
+        }
+    }
+    //Define the field formats for the composite keys
+    at = first
+    for _, colIdx := range r.colIdxs {
+        if colIdx >= numFields && keyed {
+            haltKind(ErrBadFieldSpec, "the sort column " + strconv.Itoa(colIdx + 1) + " exceeds the number of fields", nil)
         }
     }
+    at = RecordError{}
+    keySpecs := r.sortSpecs(widths)
+    if sample {
+        for k := range keySpecs {
+            if keySpecs[k].DERIVE == nil { keySpecs[k].WIDTH = int(widths[keySpecs[k].COLIDX]) }
+        }
+    }
+    return keySpecs, checksum
+} //end func scanFields
+func (s *Sorter) sortSpecs(widths []float64) []keyParams {
+    //Returns the key specs of the index fields, hashed and typed per the settings, preceded by that of the derived key if any
+    keySpecs := makeKeySpecs(s.colIdxs, widths)
+    s.typeKeySpecs(keySpecs)
+    if s.derive != nil { keySpecs = append([]keyParams{{COLIDX:-1, FORMAT:"%s", WIDTH:-1, DERIVE:s.derive}}, keySpecs...) }
+    for k := range keySpecs { keySpecs[k].HASHED, keySpecs[k].SEED = s.hashOrder, s.hashSeed }
+    return keySpecs
+} //end func sortSpecs
+////Record output
+func (r *sortRun) writeRecords(fhIn io.ReadSeeker, outFile, keysFile string, scannerKeys *bufio.Scanner, numKeys int,
+                               reducer *groupReducer) {
+    fhOut   := r.createOutput(outFile)       //create destination file for sorted data
+    defer fhOut.Close()
+    output  := r.timePhase("output")
+    written := int64(0)                      //bytes output
+    numRecs := 0
+    r.ranking = ranking{total:numKeys}
+    r.unique  = uniqueCheck{}
+    defer r.openDuplicates()()
+    fhAt    := readerAt(fhIn)
+    r.copyOutside(fhAt, fhOut, true)
+    r.readRecords(fhAt, keysFile, scannerKeys, func(key, record string, lastInGroup bool) bool {
+        r.checkDone()
+        if !r.checkUnique(key, record) { return false }
+        if reducer == nil {
+            if !strings.HasSuffix(record, "\n") { record += "\n" } //input's last record lacking its terminator
+            if r.keeps(record, lastInGroup) {
+                r.countOutput(record)
+                n, _ := fmt.Fprint(fhOut, r.rankRecord(key, r.numberRecord(key, record)))
+                written += int64(n)
+            }
+        } else {
+            values := strings.Join(recordKey(record, reducer.sep, reducer.colIdxs), reducer.sep)
+            for _, v := range reducer.fn(values, strings.TrimRight(record, "\r\n"), lastInGroup) {
+                n, _ := fmt.Fprintln(fhOut, v)
+                written += int64(n)
+            }
+        }
+        numRecs++
+        if r.verbose { updateProgressBar("func Sort - creating outFile", numRecs, numKeys) }
+        return true
+    })
+    r.settleUnique()
+    if numRecs != numKeys {                                       //keys lost between their writing and the output
+        haltKind(ErrCorruptKeys, fmt.Sprintf("%d of the %d keys written to %s were read", numRecs, numKeys, keysFile), nil)
+    }
+    r.copyOutside(fhAt, fhOut, false)
     if err := fhOut.Sync();  err != nil { halt("fhOut.Sync - " + err.Error()) }
     if err := fhOut.Close(); err != nil { halt("fhOut.Close - " + err.Error()) }
-    fhIn.Close()
-    fhKeys.Close()
-    os.Remove(sortedKeysFile)
-    if verbose { fmt.Println("func Sort - created", outFile, "in", time.Since(start)) }
+    output(written)
     return
-} //end func Sort
-//Private ----------------------------------------------------------------------------------------------------------------------
-type keyParams struct {
-    COLIDX int
-    FORMAT string
-}
-const _progressBarLen = 50
-var(
-    _asciiGS    = fmt.Sprintf("%c", 29) //ascii character for group separator
-    _sync4Merge sync.WaitGroup
-)
+} //end func writeRecords
+func (r *sortRun) readRecords(fhIn io.ReaderAt, keysFile string, scannerKeys *bufio.Scanner,
+                              emit func(key, record string, lastInGroup bool) bool) {
+    //Feeds emit with the records of fhIn in the order of the keys of keysFile, fetched by positioned reads, until the keys
+    //run out or emit returns false
+    var at RecordError                                            //key being processed
+
+    if r.mmapInput {
+        if data, unmap, ok := mapInput(fhIn); ok {
+            defer unmap()
+            r.sliceRecords(data, keysFile, scannerKeys, emit)
+            return
+        }
+    }
+    if _, seeking := fhIn.(*seekingReaderAt); !seeking && r.parallelism > 1 {
+        r.fetchRecords(fhIn, keysFile, scannerKeys, emit)
+        return
+    }
+    defer blame(&at)
+    clusters := newClusterScanner(scannerKeys)
+    emitClusters(func() (*recordCluster, bool) {
+        cluster, ok := clusters.next()
+        if ok { cluster.readAt(fhIn) }
+        return cluster, ok
+    }, keysFile, &at, emit)
+    return
+} //end func readRecords
+func sameGroup(key1, key2 string) bool {
+    //Compares the index-field portions of two composite keys, i.e. excluding the record offsets and lengths
+    return keyIndexPart(key1) == keyIndexPart(key2)
+} //end func sameGroup
+////Index header
+func readIndexHeader(scannerIndex *bufio.Scanner) (size int64, checksum uint32, numKeys int, signature string) {
+    //Returns the fields of an index header, the digest of the key options being "" for an index predating it
+    if !scannerIndex.Scan() { haltKind(ErrIndexMismatch, "the index file is empty", nil) }
+    header := strings.Split(scannerIndex.Text(), _asciiGS)
+    if len(header) == 5 { signature, header = header[4], header[:4] }
+    if len(header) != 4 || header[0] != _indexMagic { haltKind(ErrIndexMismatch, "the index file has an invalid header", nil) }
+    size, err1    := strconv.ParseInt(header[1], 10, 64)
+    crc, err2     := strconv.ParseUint(header[2], 16, 32)
+    numKeys, err3 := strconv.Atoi(header[3])
+    if err1 != nil || err2 != nil || err3 != nil { haltKind(ErrIndexMismatch, "the index file has an invalid header", nil) }
+    return size, uint32(crc), numKeys, signature
+} //end func readIndexHeader
+func (s *Sorter) keySignature() string {
+    //Returns the digest of the settings shaping the entries of an index file, i.e. its composite keys and their order
+    hash := fnv.New64a()
+    fmt.Fprintf(hash, "%s numbers=%v freq=%v", s.runOptions(), s.lineNumbers, s.freqOrder)
+    return fmt.Sprintf("%016x", hash.Sum64())
+} //end func keySignature
 ////Composite key
-func makeCompositeKeyFn(fieldSep string, sortSpecs []keyParams, seekLen int) func(record string, recordStart int64) string {
+func makeCompositeKeyFn(split func(record string) []string, sortSpecs []keyParams,
+                        seekLen int) func(record string, recordStart int64, recordLen int) string {
     var(
-        sep       = fieldSep
         keySpecs  = sortSpecs
-        keyFormat = fmt.Sprintf("%%s%%s%%%dv", seekLen)
+        keyFormat = fmt.Sprintf("%%s%%%dv%%s%%%dv", seekLen, seekLen)
+        builders  = make([]func(value string) string, len(sortSpecs)) //builders of the parts of the key, by index field
+        caches    = make([]*keyCache, len(sortSpecs))                 //interned parts of the key, by index field
+        lastLen   int                                                 //length of the last key, to size the next one
     )
-    return func(record string, recordStart int64) string {
+    for k := range keySpecs {
+        v          := keySpecs[k]
+        builders[k] = func(value string) string {
+            if v.PREPARE != nil { value = v.PREPARE(value) }
+            if v.WIDTH >= 0 && v.TYPE == FieldText && len(value) > v.WIDTH {
+                panic(haltError{&widthError{column:v.COLIDX + 1, length:len(value), width:v.WIDTH}})
+            }
+            part := nullMarked(v.NULLPRE, value)
+            value = encodeField(v.TYPE, v.PARSING, v.INVHIGH, value)
+            format, class := v.FORMAT, ""
+            if v.ZEROPAD != "" {
+                if class, value = zeroPadded(v.COLIDX + 1, v.STRICT, value); class == "1" { format = v.ZEROPAD }
+            }
+            if v.HASHED { part += hashDigest(v.SEED, value) }
+            if v.DESC { return descending(part + class + fmt.Sprintf(format, value)) }
+            return part + class + fmt.Sprintf(format, value)
+        }
+        caches[k]   = newKeyCache()
+    }
+    return func(record string, recordStart int64, recordLen int) string {
             var(
-                key    string
-                fields = strings.Split(record, sep)
+                key    strings.Builder
+                fields = split(record)
             )
-            for _,v := range keySpecs {
-                key += fmt.Sprintf(v.FORMAT, fields[v.COLIDX])
+            key.Grow(lastLen)
+            for k, v := range keySpecs {
+                if v.DERIVE != nil {
+                    key.WriteString(derivedPart(v, fields))
+                    continue
+                }
+                var value string
+                if v.COLIDX < len(fields) { value = fields[v.COLIDX] }
+                key.WriteString(caches[k].piece(value, builders[k]))
             }
-            return fmt.Sprintf(keyFormat, key, _asciiGS, recordStart)
+            fmt.Fprintf(&key, keyFormat, _asciiGS, recordStart, _asciiGS, recordLen)
+            lastLen = key.Len()
+            return key.String()
            }
 } //end func makeCompositeKeyFn
+func makeKeySpecs(colIdxs []int, widths []float64) []keyParams {
+    keySpecs := []keyParams{}
+    for _, colIdx := range colIdxs {
+        var width float64
+        if colIdx < len(widths) { width = widths[colIdx] }
+        keySpecs = append(keySpecs, keyParams{COLIDX:colIdx, FORMAT:fmt.Sprintf("%%%vs", width), WIDTH:-1})
+    }
+    return keySpecs
+} //end func makeKeySpecs
+func keyPrecedes(key1, key2 string, sortAsc bool) bool {
+    //Orders composite keys on their index fields per the sort direction, then on their record offsets in ascending order so
+    //that equal index fields keep their input order in both directions
+    if sortAsc { return key1 < key2 }
+    idx1, idx2 := keyIndexPart(key1), keyIndexPart(key2)
+    if idx1 != idx2 { return idx1 > idx2 }
+    return key1[len(idx1):] < key2[len(idx2):]
+} //end func keyPrecedes
+func keyBytesPrecede(key1, key2 []byte, sortAsc bool) bool {
+    //Orders composite keys held as bytes as keyPrecedes does, without converting them to strings
+    if sortAsc { return bytes.Compare(key1, key2) < 0 }
+    idx1, idx2 := key1, key2
+    if k := bytes.IndexByte(key1, _asciiGS[0]); k >= 0 { idx1 = key1[:k] }
+    if k := bytes.IndexByte(key2, _asciiGS[0]); k >= 0 { idx2 = key2[:k] }
+    if c := bytes.Compare(idx1, idx2); c != 0 { return c > 0 }
+    return bytes.Compare(key1[len(idx1):], key2[len(idx2):]) < 0
+} //end func keyBytesPrecede
+func keyIndexPart(key string) string {
+    if k := strings.Index(key, _asciiGS); k >= 0 { return key[:k] }
+    return key
+} //end func keyIndexPart
+////Field specification
+func parseColumns(usingFields string) ([]int, error) {
+    colIdxs := []int{}
+    for _, v := range strings.Split(usingFields, ",") {
+        colNum, err := strconv.Atoi(strings.TrimSpace(v))
+        if err != nil || colNum < 1 {
+            return nil, &kindError{kind:ErrBadFieldSpec,
+                                   err:errors.New("the specification of the sort columns is syntactically incorrect")}
+        }
+        colIdxs = append(colIdxs, colNum - 1)
+    }
+    return colIdxs, nil
+} //end func parseColumns
 ////Merge coroutine
-func merge(sortAsc bool, chan4command <-chan string, chan4tasks <-chan [2]string, verbose bool) {
-    var(
-        key1, key2 = "", ""
-        eot        bool
-    )
+func (r *sortRun) merge(chan4command <-chan string, chan4tasks <-chan []string, chan4done chan<- struct{},
+                        sync4Merge *sync.WaitGroup, errMerge *error) {
+    var eot bool
+
+    defer close(chan4done)
+    if r.lowPriority { lowerThreadPriority() }
     jobLoop: for {
         select {
             case command := <-chan4command:
                 eot = (command == "e-o-t")
                 if command == "quit" { break jobLoop }
             case tasks := <-chan4tasks:
-                sourceKeys1, sourceKeys2 := tasks[0], tasks[1]
-                fhKeys1, errKeys1        := openFile(sourceKeys1)           //open 1st keys file for read
-                reader1                  := bufio.NewReader(fhKeys1)
-                fhKeys2, errKeys2        := openFile(sourceKeys2)           //open 2nd keys file for read
-                reader2                  := bufio.NewReader(fhKeys2)
-                writer, tempFile         := createTempFile()                //create temp file for the merged keys
-                //Process the two key files until one of them runs out of records
-                for (key1 != "" || errKeys1 != io.EOF) && (key2 != "" || errKeys2 != io.EOF) {
-                    if key1 == "" { key1, errKeys1 = readString(reader1) }  //get the next key in 1st file
-                    if key2 == "" { key2, errKeys2 = readString(reader2) }  //get the next key in 2nd file
-                    if sortAsc {                                            //sort ascending
-                        if key1 < key2  {                                   // case of 1st key less than 2nd one
-                            fmt.Fprint(writer, key1)                        //  add key from 1st file to new temp key file
-                            key1 = ""                                       //  clear the current key from 1st file
-                        } else {                                            // case of 2nd key less than or equal to 1st one
-                            fmt.Fprint(writer, key2)                        //  add key from 2nd file to new temp key file
-                            key2 = ""                                       //  clear the current key from 2nd file
-                        }                                                   // end case of keys ordering
-                    } else {                                                //else sort descending
-                        if key1 > key2  {                                   // case of 1st key greater than 2nd one
-                            fmt.Fprint(writer, key1)                        //  add key from 1st file to new temp key file
-                            key1 = ""                                       //  clear the current key from 1st file
-                        } else {                                            // case of 2nd key greater than or equal to 1st one
-                            fmt.Fprint(writer, key2)                        //  add key from 2nd file to new temp key file
-                            key2 = ""                                       //  clear the current key from 2nd file
-                        }                                                   // end case of keys ordering
-                    }                                                       //end if-else
-                }
-                //Save the remaining keys,if any, for the next pass
-                if key1 != "" || errKeys1 != io.EOF {                       //if the 1st file has some unprocessed keys
-                    if key1 != "" { fmt.Fprint(writer, key1) }              // add any unprocessed read key to new temp file
-                    for errKeys1 != io.EOF {                                // add any unread keys to new temp file
-                        key1, errKeys1 = readString(reader1)
-                        fmt.Fprint(writer, key1)
-                    }
-                } else {                                                    //else the 2nd file has some unprocessed keys
-                    if key2 != "" { fmt.Fprint(writer, key2) }              // add any unprocessed read key to new temp file
-                    for errKeys2 != io.EOF {                                // add any unread keys to new temp file
-                        key2, errKeys2 = readString(reader2)
-                        fmt.Fprint(writer, key2)
-                    }
-                }
-                fhKeys1.Close()
-                fhKeys2.Close()
-                os.Remove(sourceKeys1)
-                os.Remove(sourceKeys2)
-                if err := writer.Sync();  err != nil { halt("writer.Sync - " + err.Error()) }
-                if err := writer.Close(); err != nil { halt("writer.Close - " + err.Error()) }
-                if verbose { fmt.Println("\tfunc merge - merged", filepath.Base(sourceKeys1), "and", filepath.Base(sourceKeys2),
-                                         "to", filepath.Base(tempFile)) }
+                if *errMerge == nil { _, *errMerge = r.mergeFiles(tasks) } //skip after a failure
             default:
                 if eot && len(chan4tasks) == 0 {
-                    if verbose { fmt.Println("\tfunc merge - all tasks done") }
-                    _sync4Merge.Done()
+                    if r.verbose { fmt.Println("\tfunc merge - all tasks done") }
+                    sync4Merge.Done()
                     eot = false
                 }
         }
     }
     return
-} // end func merge
+} //end func merge
+func (r *sortRun) mergeFiles(sourceKeys []string) (merged string, err error) {
+    //Merges key files into a new one, returning its name
+    var(
+        fhKeys   = make([]TempFile,       len(sourceKeys))          //key files being merged
+        scanners = make([]*bufio.Scanner, len(sourceKeys))          //their scanners, checking their trailers
+        heads    = make([][]byte,         len(sourceKeys))          //their next keys, nil once exhausted
+        names    = make([]string,         len(sourceKeys))          //their base names, for the verbose echo
+        last     []byte                                             //copy of the key last written, to locate errors
+        merges   int                                                //keys merged
+    )
+
+    defer catch(&err)
+    defer r.tracedMerge()()
+    r.giveWay()
+    r.checkDone()
+    at := RecordError{Offset:-1}                                    //file & key last read
+    defer blame(&at)
+    defer func() { if at.File != "" && last != nil { at.Key = string(last) } }() //on a failure, before blame
+    r.limiter.acquire(len(sourceKeys) + 1)                          //the key files and the merged one
+    defer r.limiter.release(len(sourceKeys) + 1)
+    defer func() { for _, fh := range fhKeys { if fh != nil { fh.Close() } } }()
+    nextKey := func(k int) []byte {                                 //returns the next key of a file, nil once exhausted
+        if scanners[k].Scan() { return scanners[k].Bytes() }
+        if err := scanners[k].Err(); err != nil { haltKind(nil, "scanner.Scan", err) }
+        return nil
+    }
+    for k, v := range sourceKeys {                                  //open the key files & read their first keys
+        at.File                = v
+        fhKeys[k], scanners[k] = r.openKeys(v)
+        heads[k]               = nextKey(k)
+        names[k]               = r.tempLabel(v) + r.placement(v)
+    }
+    fhMerged, merged := r.createTemp(r.prefix, sourceKeys...)       //create temp file for the merged keys
+    defer fhMerged.Close()
+    fhMerged = &countedFile{TempFile:fhMerged, written:&r.counters.merged}
+    writer := r.newKeyWriter(fhMerged)
+    //Repeatedly output the first of the next keys until all the files are exhausted
+    for {
+        next := -1
+        for k, key := range heads {
+            if len(key) > 0 && (next < 0 || keyBytesPrecede(key, heads[next], r.sortAsc)) { next = k }
+        }
+        if next < 0 { break }
+        if merges++; merges % _doneEvery == 0 { r.checkDone() }
+        writer.writeBytes(heads[next])                              //before the next scan reuses the bytes of the key
+        at.File, last = sourceKeys[next], append(last[:0], heads[next]...)
+        heads[next]   = nextKey(next)
+    }
+    writer.close()
+    at = RecordError{}
+    for k, v := range sourceKeys {
+        fhKeys[k].Close()
+        fhKeys[k] = nil
+        r.removeTemp(v)
+    }
+    if err := fhMerged.Sync();  err != nil { haltTemp("fhMerged.Sync", err) }
+    if err := fhMerged.Close(); err != nil { haltTemp("fhMerged.Close", err) }
+    progress := r.progress.done()
+    if r.verbose {
+        stamp(&r.counters.spoke)
+        fmt.Println("\tfunc merge -", progress + ": merged", strings.Join(names, ", "), "to",
+                    r.tempLabel(merged) + r.placement(merged))
+    }
+    return
+} //end func mergeFiles
 ////File ops
-func createFile(file string) *os.File {
-    fh, err := os.Create(file)
-    if err != nil { halt("os.Create - " + err.Error()) }
-    return fh
-} //end func createFile
-func createTempFile() (*os.File, string) {
-    fh, err := ioutil.TempFile("", "keys_")
-    if err != nil { halt("ioutil.TempFile - " + err.Error()) }
-    return fh, fh.Name()
-} //end func createTempFile
+func fileChecksum(file string) uint32 {
+    fh, _ := openFile(file)
+    defer fh.Close()
+    hash  := crc32.NewIEEE()
+    if _, err := io.Copy(hash, fh); err != nil { halt("io.Copy - " + err.Error()) }
+    return hash.Sum32()
+} //end func fileChecksum
+func (r *sortRun) openInput(inFile string) (inputFile, int64) {
+    //Opens the input on the temporary storage if spooled, else on the run's file system or the OS one if none, and returns it
+    //with its size
+    var(
+        fh  fs.File
+        err error
+    )
+    if r.spooled {                                                //input spooled to the temporary storage
+        fhIn    := r.openTemp(inFile)
+        size, err := fhIn.Seek(0, io.SeekEnd)
+        if err == nil { _, err = fhIn.Seek(0, io.SeekStart) }
+        if err != nil { halt("Seek - " + err.Error()) }
+        return fhIn, size
+    }
+    if r.fsys == nil { fh, err = os.Open(inFile) } else { fh, err = r.fsys.Open(inFile) }
+    if err != nil { haltKind(ErrInputNotFound, "the input file cannot be located", err) }
+    fi, err := fh.Stat()
+    if err != nil {
+        fh.Close()
+        halt("Stat - " + err.Error())
+    }
+    fhIn, ok := fh.(inputFile)
+    if !ok {
+        fh.Close()
+        halt("the input file does not support seeking")
+    }
+    return r.throttleInput(fhIn), fi.Size()
+} //end func openInput
 func openFile(file string) (fh *os.File, err error) {
     fh, err = os.Open(file)
-    if err != nil { halt("os.Open - " + err.Error()) }
+    if err != nil { haltKind(ErrInputNotFound, "os.Open", err) }
     return
 } //end func openFile
 func readString(reader *bufio.Reader) (record string, err error) {
@@ -297,39 +1262,61 @@ func readString(reader *bufio.Reader) (record string, err error) {
     if err != nil && err != io.EOF { halt("reader.ReadString - " + err.Error()) }
     return
 } //end func readString
-func resetReader(fh *os.File, reader *bufio.Reader) (err error) {
+func resetReader(fh io.Seeker, reader *bufio.Reader) (err error) {
     reader.Discard(reader.Buffered())
     _, err = fh.Seek(0, 0)
     if err != nil { halt("fh.Seek - " + err.Error()) }
     return
 } //end func resetReader
-func seekFile(fh *os.File, offsetStr string) {
-    offset, err := strconv.ParseInt(strings.TrimLeft(offsetStr, " "), 10, 64)
-    if err != nil { halt("strconv.ParseInt - " + err.Error()) }
-    _, err = fh.Seek(offset, 0)
-    if err != nil { halt("fh.Seek - " + err.Error()) }
-    return
-} //end func seekFile
 ////Reporting
+func catch(err *error) {
+    //Converts a halt into the error returned by the deferring function; any other panic is propagated
+    if r := recover(); r != nil {
+        if h, ok := r.(haltError); ok {
+            *err = h.err
+            return
+        }
+        panic(r)
+    }
+} //end func catch
 func halt(msg string) {
-    pc, _, _, ok := runtime.Caller(1)
+    //Aborts the current operation by panicking with a haltError, to be recovered by catch
+    panic(haltError{newHaltError(nil, msg, nil)})
+} //end func halt
+func newHaltError(kind error, msg string, cause error) error {
+    //Returns the error of a halt, prefixed with the name of the function calling halt or its variants, matching kind if
+    //not nil and wrapping any cause
+    var err error
+    pc, _, _, ok := runtime.Caller(2)
     details      := runtime.FuncForPC(pc)
     if ok && details != nil {
-        log.Fatalln(fmt.Sprintf("\a%s: %s", details.Name(), msg))
+        name := details.Name()
+        name  = name[strings.LastIndex(name, "/") + 1:]
+        if k := strings.Index(name, ")."); k >= 0 { name = "." + name[k + 2:] } //drop any method receiver
+        name  = name[strings.Index(name, ".") + 1:]
+        prefix := fmt.Sprintf("mergesort.%s: %s", name, msg)
+        if cause == nil { err = errors.New(prefix) } else { err = fmt.Errorf("%s - %w", prefix, cause) }
+    } else {
+        err = errors.New("mergesort: fatal error")
     }
-    log.Fatalln("\aoctree: FATAL ERROR!")
-} //end func halt
+    if kind != nil { err = &kindError{kind:kind, err:err} }
+    return err
+} //end func newHaltError
 func updateProgressBar(title string, current, total int) {
     //code derived from Graham King's post "Pretty command line / console output on Unix in Python and Go Lang"
     //(http://www.darkcoding.net/software/pretty-command-line-console-output-on-unix-in-python-and-go-lang/)
-    prefix := fmt.Sprintf("%s: %d / %d ", title, current, total)
-    amount := int(0.1 + float32(_progressBarLen) * float32(current) / float32(total))
-    remain := _progressBarLen - amount
-    bar    := strings.Repeat("\u2588", amount) + strings.Repeat("\u2591", remain)
+    prefix := fmt.Sprintf("%s: ", title)
+    bar    := progressBar(current, total)
     os.Stdout.WriteString(prefix + bar + "\r")
-    if current == total { os.Stdout.WriteString(strings.Repeat(" ", len(prefix) + _progressBarLen) + "\r") }
+    if current == total { os.Stdout.WriteString(strings.Repeat(" ", len(prefix) + len(bar)) + "\r") }
     os.Stdout.Sync()
     return
 } //end func updateProgressBar
+func progressBar(current, total int) string {
+    //Returns the progress of current out of total, as the numbers followed by a bar of _progressBarLen characters
+    amount := int(0.1 + float32(_progressBarLen) * float32(current) / float32(total))
+    remain := _progressBarLen - amount
+    return fmt.Sprintf("%d / %d ", current, total) + strings.Repeat("\u2588", amount) + strings.Repeat("\u2591", remain)
+} //end func progressBar
 //===== Copyright (c) 2016 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
 //end of Package mergesort
@@ -0,0 +1,249 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     mergesort_test.go
+ * Overview:
+ *     tests of the sorts of whole files, and the helpers shared by the tests of the package.
+ * Functions:
+ *     TestInMemoryMatchesExternal(t *testing.T)
+ *         Checks that inputs straddling the in-memory threshold sort to the same bytes on both paths.
+ *     TestIndex(t *testing.T)
+ *         Checks the sorts through an index file, and the refusal of an index stale, foreign or of other options.
+ *     TestSortChan(t *testing.T)
+ *         Checks the records sorted through channels, and the cancellation of the sort while spooling or emitting.
+ *     BenchmarkMergeFiles(b *testing.B)
+ *         Merges the run files of an input, reporting the allocations per key merged.
+ * History:
+ *     v1.13.0 - October 15, 2026 - Original release.
+ *     v1.36.0 - October 15, 2026 - Added TestIndex.
+ *     v1.69.0 - October 15, 2026 - Added TestSortChan.
+ *     v1.92.0 - October 15, 2026 - Added BenchmarkMergeFiles.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "bytes"
+    "context"
+    "errors"
+    "fmt"
+    "io/ioutil"
+    "math/rand"
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+    "time"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func TestInMemoryMatchesExternal(t *testing.T) {
+    const threshold = 64 << 10                                    //inputs of up to 32KB sorted in memory
+    for seed := int64(1); seed <= 4; seed++ {
+        //Records up to just below half the threshold, then one more taking the input just above it
+        rng     := rand.New(rand.NewSource(seed))
+        records := []string{}
+        size    := 0
+        for {
+            record := randomRecord(rng)
+            if size + len(record) > threshold / 2 {
+                records = append(records, record)
+                break
+            }
+            records, size = append(records, record), size + len(record)
+        }
+        below, above := strings.Join(records[:len(records) - 1], ""), strings.Join(records, "")
+        for _, input := range []string{below, above} {
+            inMemory := len(input) <= threshold / 2
+            for _, sortAsc := range []bool{true, false} {
+                name := fmt.Sprintf("seed=%d/size=%d/asc=%v", seed, len(input), sortAsc)
+                opts := []Option{WithFields("2,1"), WithAscending(sortAsc), WithKeysPerSort(100)}
+                var stats SortStats
+                fast     := sortBytes(t, input, append(opts, WithInMemorySpillThreshold(threshold), WithStats(&stats))...)
+                if (stats.TempBytes == 0) != inMemory {
+                    t.Fatalf("%s: %d temporary bytes written, expected the in-memory path to be %v", name, stats.TempBytes,
+                             inMemory)
+                }
+                external := sortBytes(t, input, opts...)
+                if !bytes.Equal(fast, external) { t.Fatalf("%s: the outputs of the two paths differ", name) }
+            }
+        }
+    }
+} //end func TestInMemoryMatchesExternal
+func TestIndex(t *testing.T) {
+    //An index applied as is, then to an input changed in place or resized, and as the index of another input
+    rng       := rand.New(rand.NewSource(23))
+    input     := randomInput(rng, 3000)
+    inFile    := writeInput(t, input)
+    indexFile := filepath.Join(t.TempDir(), "index.txt")
+    if err := SortIndex(inFile, indexFile, true, "2,1", "\t", 500, false, WithTempDir(t.TempDir())); err != nil {
+        t.Fatal(err)
+    }
+    for _, apply := range []func(inFile, outFile string) error{
+        func(inFile, outFile string) error { return ApplyIndex(inFile, indexFile, outFile) },
+        func(inFile, outFile string) error {
+            return SortWithKeys(inFile, indexFile, outFile, WithFields("2,1"), WithSeparator("\t"))
+        },
+    } {
+        outFile := filepath.Join(t.TempDir(), "out.txt")
+        if err := apply(inFile, outFile); err != nil { t.Fatal(err) }
+        checkSorted(t, input, outFile, "2,1")
+        changed := []byte(input)
+        changed[len(changed) / 2] ^= 1
+        for name, data := range map[string]string{"changed":string(changed), "resized":input + randomRecord(rng),
+                                                   "foreign":randomInput(rng, 3000)} {
+            if err := apply(writeInput(t, data), outFile); !errors.Is(err, ErrIndexMismatch) {
+                t.Errorf("%s input: error %v, expected ErrIndexMismatch", name, err)
+            }
+        }
+    }
+    //An index applied with other options, and a file that is not an index
+    err := SortWithKeys(inFile, indexFile, filepath.Join(t.TempDir(), "out.txt"), WithFields("1,2"), WithSeparator("\t"))
+    if !errors.Is(err, ErrIndexMismatch) { t.Errorf("other index fields: error %v, expected ErrIndexMismatch", err) }
+    if err := ApplyIndex(inFile, inFile, filepath.Join(t.TempDir(), "out.txt")); !errors.Is(err, ErrIndexMismatch) {
+        t.Errorf("input as its own index: error %v, expected ErrIndexMismatch", err)
+    }
+} //end func TestIndex
+func TestSortChan(t *testing.T) {
+    records := strings.SplitAfter(randomInput(rand.New(rand.NewSource(25)), 300), "\n")
+    records  = records[:len(records) - 1]
+    produce := func(in chan<- string, records []string, cancel func()) <-chan struct{} {
+        //Sends the records, without their terminators, cancelling the sort halfway if cancel is set, and closes the channel
+        done := make(chan struct{})
+        go func() {
+            defer close(done)
+            for k, record := range records {
+                if cancel != nil && k == len(records) / 2 { cancel() }
+                in<- strings.TrimSuffix(record, "\n")
+            }
+            close(in)
+        }()
+        return done
+    }
+    //All the records emitted in sorted order
+    sorted := append([]string(nil), records...)
+    if err := SortStrings(sorted, true, "2,1", "\t"); err != nil { t.Fatal(err) }
+    in := make(chan string)
+    produce(in, records, nil)
+    chanOut, chanErr := SortChan(context.Background(), in, true, "2,1", "\t", 50, false, WithTempDir(t.TempDir()))
+    var output strings.Builder
+    for record := range chanOut { output.WriteString(record + "\n") }
+    if err := <-chanErr; err != nil { t.Fatal(err) }
+    if output.String() != strings.Join(sorted, "") { t.Error("records not emitted in sorted order") }
+    //Cancelled while spooling, the producer never blocked, or after the first record emitted
+    for _, emitting := range []bool{false, true} {
+        ctx, cancel := context.WithCancel(context.Background())
+        tempDir     := t.TempDir()
+        in          := make(chan string)
+        var done <-chan struct{}
+        if emitting {
+            done = produce(in, records, nil)
+        } else {
+            done = produce(in, records, cancel)
+        }
+        chanOut, chanErr := SortChan(ctx, in, true, "2,1", "\t", 50, false, WithTempDir(tempDir))
+        if emitting {
+            <-chanOut
+            cancel()
+        }
+        for range chanOut {}
+        if err := <-chanErr; !errors.Is(err, ErrInterrupted) || !errors.Is(err, context.Canceled) {
+            t.Errorf("emitting %v: error %v, expected ErrInterrupted and context.Canceled", emitting, err)
+        }
+        select {
+            case <-done:
+            case <-time.After(5 * time.Second): t.Fatalf("emitting %v: producer blocked after the cancellation", emitting)
+        }
+        if left, err := ioutil.ReadDir(tempDir); err != nil || len(left) > 0 {
+            t.Errorf("emitting %v: %d temporary files left (%v)", emitting, len(left), err)
+        }
+        cancel()
+    }
+} //end func TestSortChan
+func BenchmarkMergeFiles(b *testing.B) {
+    //Sub-benchmarks "asc=true" and "asc=false", each merging 8 run files of 200000 keys in all, read in place and left so
+    //that every iteration merges the same files; reports the allocations per key merged
+    const runs, numRecords = 8, 200000
+    inFile := writeInput(b, randomInput(rand.New(rand.NewSource(14)), numRecords))
+    info, err := os.Stat(inFile)
+    if err != nil { b.Fatal(err) }
+    for _, sortAsc := range []bool{true, false} {
+        b.Run(fmt.Sprintf("asc=%v", sortAsc), func(b *testing.B) {
+            opts   := []Option{WithFields("2,1"), WithAscending(sortAsc), WithKeysPerSort(numRecords), WithTempDir(b.TempDir())}
+            runDir := b.TempDir()
+            for k := int64(0); k < runs; k++ {
+                byteRange := ByteRange{Start:info.Size() * k / runs, End:info.Size() * (k + 1) / runs}
+                if k == runs - 1 { byteRange.End = -1 }
+                if err := GenerateRuns(inFile, byteRange, runDir, opts...); err != nil { b.Fatal(err) }
+            }
+            runFiles, err := listDir(runDir, "", ".keys")
+            if err != nil || len(runFiles) != runs { b.Fatalf("%d run files (%v)", len(runFiles), err) }
+            sorter, err := NewSorter(opts...)
+            if err != nil { b.Fatal(err) }
+            inPlace := map[string]bool{}
+            for _, runFile := range runFiles { inPlace[runFile] = true }
+            sorter.storage = runStorage{TempStorage:sorter.storage, runs:inPlace}
+            r         := sorter.newRun()
+            r.progress = &mergeTracker{spoke:&r.counters.spoke}
+            merge     := func() {
+                merged, err := r.mergeFiles(runFiles)
+                if err != nil { b.Fatal(err) }
+                r.removeTemp(merged)
+            }
+            b.ReportAllocs()
+            b.ResetTimer()
+            for k := 0; k < b.N; k++ { merge() }
+            b.StopTimer()
+            b.ReportMetric(testing.AllocsPerRun(1, merge) / numRecords, "allocs/key")
+        })
+    }
+} //end func BenchmarkMergeFiles
+//Private ----------------------------------------------------------------------------------------------------------------------
+func randomRecord(rng *rand.Rand) string {
+    //Returns a record of three tab-separated fields, the first two often duplicated, terminated by a line feed
+    letters := []byte("abcdefgh")
+    word    := make([]byte, 1 + rng.Intn(6))
+    for k := range word { word[k] = letters[rng.Intn(len(letters))] }
+    return fmt.Sprintf("%s\t%d\t%d\n", word, rng.Intn(50), rng.Int63())
+} //end func randomRecord
+func writeInput(t testing.TB, data string) string {
+    //Writes data to a file of the test's temporary directory, returning its path
+    t.Helper()
+    path := filepath.Join(t.TempDir(), "in.txt")
+    if err := ioutil.WriteFile(path, []byte(data), 0644); err != nil { t.Fatal(err) }
+    return path
+} //end func writeInput
+func sortBytes(t testing.TB, data string, opts ...Option) []byte {
+    //Sorts data with the options, its temporary files in the test's temporary directory, and returns the output
+    t.Helper()
+    inFile  := writeInput(t, data)
+    outFile := filepath.Join(filepath.Dir(inFile), "out.txt")
+    sorter, err := NewSorter(append([]Option{WithTempDir(t.TempDir())}, opts...)...)
+    if err != nil { t.Fatal(err) }
+    if err := sorter.Run(inFile, outFile); err != nil { t.Fatal(err) }
+    output, err := ioutil.ReadFile(outFile)
+    if err != nil { t.Fatal(err) }
+    return output
+} //end func sortBytes
+func benchSort(b *testing.B, inFile string, opts ...Option) SortStats {
+    //Sorts the input b.N times with the options, its temporary files in the benchmark's temporary directory, reporting the
+    //throughput, the allocations, and the temporary bytes and merge passes of a sort; returns the statistics of the last
+    b.Helper()
+    var stats SortStats
+    sorter, err := NewSorter(append(append([]Option{WithTempDir(b.TempDir())}, opts...), WithStats(&stats))...)
+    if err != nil { b.Fatal(err) }
+    info, err := os.Stat(inFile)
+    if err != nil { b.Fatal(err) }
+    outFile := filepath.Join(b.TempDir(), "out.txt")
+    b.ReportAllocs()
+    b.SetBytes(info.Size())
+    b.ResetTimer()
+    for k := 0; k < b.N; k++ {
+        if err := sorter.Run(inFile, outFile); err != nil { b.Fatal(err) }
+    }
+    b.StopTimer()
+    b.ReportMetric(float64(stats.TempBytes),   "tempB/op")
+    b.ReportMetric(float64(stats.MergePasses), "passes/op")
+    return stats
+} //end func benchSort
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file mergesort_test.go
@@ -0,0 +1,74 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     mmap.go
+ * Overview:
+ *     output phase reading the records of the keys from the input mapped into memory.
+ * Functions:
+ *     WithMemoryMappedInput() Option
+ *         Option mapping the input into memory for the output phase.
+ * History:
+ *     v1.60.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "bufio"
+    "io"
+    "math"
+    "os"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func WithMemoryMappedInput() Option {
+/*         Purpose : Maps the input into memory for the output phase.
+ *       Arguments : None.
+ *         Returns : The option.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : None.
+ *         Remarks : The records of the sorted keys are then sliced from the mapping by their offsets and lengths, without
+ *                   any read or seek of the input, which pays off for inputs held by the page cache. The output is the
+ *                   same byte for byte. Where the input cannot be mapped, e.g. on a system without mmap, for an empty
+ *                   or spooled input, one read through an fs.FS or one too large for the address space, the records are
+ *                   read as without the option. The mapping is released when the output phase ends, failed or not.
+ *         History : v1.60.0 - October 15, 2026 - Original release.
+ */
+    return func(s *Sorter) { s.mmapInput = true }
+} //end func WithMemoryMappedInput
+//Private ----------------------------------------------------------------------------------------------------------------------
+func mapInput(fhIn io.ReaderAt) (data []byte, unmap func(), ok bool) {
+    //Maps an input file into memory read-only, returning false if it cannot be mapped
+    fh, isFile := fhIn.(*os.File)
+    if !isFile { return nil, nil, false }
+    fi, err := fh.Stat()
+    if err != nil || fi.Size() == 0 || uint64(fi.Size()) > math.MaxInt { return nil, nil, false }
+    if data, err = mapFile(fh, int(fi.Size())); err != nil { return nil, nil, false }
+    return data, func() { unmapFile(data) }, true
+} //end func mapInput
+func (r *sortRun) sliceRecords(data []byte, keysFile string, scannerKeys *bufio.Scanner,
+                               emit func(key, record string, lastInGroup bool) bool) {
+    //Feeds emit with the records of the mapped input in the order of the keys of keysFile, until the keys run out or emit
+    //returns false
+    var at RecordError                                            //key being processed
+
+    defer blame(&at)
+    clusters := newClusterScanner(scannerKeys)
+    emitClusters(func() (*recordCluster, bool) {
+        cluster, ok := clusters.next()
+        if ok { cluster.sliceFrom(data) }
+        return cluster, ok
+    }, keysFile, &at, emit)
+    return
+} //end func sliceRecords
+func (c *recordCluster) sliceFrom(data []byte) {
+    //Points the cluster at its bytes within the mapped input
+    if c.err != nil || c.inline != nil { return }
+    if c.start < 0 || c.end > int64(len(data)) || c.start > c.end {
+        c.err = io.ErrUnexpectedEOF
+        return
+    }
+    c.data = data[c.start:c.end:c.end]
+} //end func sliceFrom
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file mmap.go
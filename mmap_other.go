@@ -0,0 +1,29 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd
+
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     mmap_other.go
+ * Overview:
+ *     mappings of files, on the systems without mmap, where the inputs are read instead.
+ * History:
+ *     v1.60.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "errors"
+    "os"
+)
+//Private ----------------------------------------------------------------------------------------------------------------------
+func mapFile(fh *os.File, size int) ([]byte, error) {
+    //Returns that files cannot be mapped
+    return nil, errors.New("memory mappings unsupported")
+} //end func mapFile
+func unmapFile(data []byte) error {
+    return nil
+} //end func unmapFile
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file mmap_other.go
@@ -0,0 +1,29 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd
+// +build darwin dragonfly freebsd linux netbsd openbsd
+
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     mmap_unix.go
+ * Overview:
+ *     read-only mappings of files through mmap.
+ * History:
+ *     v1.60.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "os"
+    "syscall"
+)
+//Private ----------------------------------------------------------------------------------------------------------------------
+func mapFile(fh *os.File, size int) ([]byte, error) {
+    //Maps the first size bytes of a file into memory, read-only and shared
+    return syscall.Mmap(int(fh.Fd()), 0, size, syscall.PROT_READ, syscall.MAP_SHARED)
+} //end func mapFile
+func unmapFile(data []byte) error {
+    return syscall.Munmap(data)
+} //end func unmapFile
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file mmap_unix.go
@@ -0,0 +1,49 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     nulls.go
+ * Overview:
+ *     placement of the records whose index field is empty.
+ * Functions:
+ *     WithNullsFirst(column int) Option
+ *         Option placing the records with an empty index field ahead of all others, whatever the sort direction.
+ *     WithNullsLast(column int) Option
+ *         Option placing the records with an empty index field behind all others, whatever the sort direction.
+ * History:
+ *     v1.26.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func WithNullsFirst(column int) Option {
+    //Places the records whose index field is empty, once normalized, ahead of all others in either sort direction
+    return func(s *Sorter) { s.placeNulls(column, false) }
+} //end func WithNullsFirst
+func WithNullsLast(column int) Option {
+    //Places the records whose index field is empty, once normalized, behind all others in either sort direction
+    return func(s *Sorter) { s.placeNulls(column, true) }
+} //end func WithNullsLast
+//Private ----------------------------------------------------------------------------------------------------------------------
+const _nonNullPrefix = "1" //prefix of the non-empty values of a field whose empty ones are placed
+func (s *Sorter) placeNulls(column int, last bool) {
+    if s.nulls == nil { s.nulls = map[int]bool{} }
+    s.nulls[column - 1] = last
+} //end func placeNulls
+func (s *Sorter) nullPrefix(colIdx int, asc bool) string {
+    //Returns the prefix of the empty values of a field ordered in the given direction, sorting below or above
+    //_nonNullPrefix, or "" if they are not placed
+    last, placed := s.nulls[colIdx]
+    switch {
+        case !placed:    return ""
+        case last == asc: return "2"
+    }
+    return "0"
+} //end func nullPrefix
+func nullMarked(nullPrefix, value string) string {
+    //Returns the prefix of a value of a field whose empty values are placed
+    if nullPrefix == "" || value == "" { return nullPrefix }
+    return _nonNullPrefix
+} //end func nullMarked
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file nulls.go
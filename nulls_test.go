@@ -0,0 +1,62 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     nulls_test.go
+ * Overview:
+ *     tests of the placement of empty index fields interleaved with real values, in both sort directions.
+ * Functions:
+ *     TestNullsPlacement(t *testing.T)
+ *         Checks that empty values precede or follow all others as placed, whatever the direction and type of the field.
+ * History:
+ *     v1.26.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "fmt"
+    "strings"
+    "testing"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func TestNullsPlacement(t *testing.T) {
+    dates   := []string{"2026-03-01\tc", "\tb", "2025-12-31\ta", "\ta", "2026-01-15\tb", "\tc"} //cancellation dates
+    amounts := []string{"5\tx", "\tx", "-3\tx", "\tx", "12\tx", " \tx"}
+    tests := []struct {
+        name    string
+        records []string
+        fields  string
+        opts    []Option
+        sortAsc bool
+        order   []int                                             //indexes of the records in output order
+    }{
+        {"first", dates, "1", []Option{WithNullsFirst(1)}, true, []int{1, 3, 5, 2, 4, 0}},
+        {"first", dates, "1", []Option{WithNullsFirst(1)}, false, []int{1, 3, 5, 0, 4, 2}},
+        {"last", dates, "1", []Option{WithNullsLast(1)}, true, []int{2, 4, 0, 1, 3, 5}},
+        {"last", dates, "1", []Option{WithNullsLast(1)}, false, []int{0, 4, 2, 1, 3, 5}},
+        {"first/second key", dates, "1,2", []Option{WithNullsFirst(1)}, true, []int{3, 1, 5, 2, 4, 0}},
+        {"first/second key", dates, "1,2", []Option{WithNullsFirst(1)}, false, []int{5, 1, 3, 0, 4, 2}},
+        {"last/second key", dates, "1,2", []Option{WithNullsLast(1)}, true, []int{2, 4, 0, 3, 1, 5}},
+        {"last/second key", dates, "1,2", []Option{WithNullsLast(1)}, false, []int{0, 4, 2, 5, 1, 3}},
+        {"null second key", dates, "2,1", []Option{WithNullsLast(1)}, true, []int{2, 3, 4, 1, 0, 5}},
+        {"null second key", dates, "2,1", []Option{WithNullsLast(1)}, false, []int{0, 5, 4, 1, 2, 3}},
+        {"numeric/first", amounts, "1", []Option{WithFieldType(1, FieldNumeric), WithNullsFirst(1)}, false,
+         []int{1, 3, 5, 4, 0, 2}},
+        {"numeric/last", amounts, "1", []Option{WithFieldType(1, FieldNumeric), WithNullsLast(1)}, true,
+         []int{2, 0, 4, 1, 3, 5}},
+        {"numeric/last", amounts, "1", []Option{WithFieldType(1, FieldNumeric), WithNullsLast(1)}, false,
+         []int{4, 0, 2, 1, 3, 5}},
+    }
+    for _, test := range tests {
+        name     := fmt.Sprintf("%s/asc=%v", test.name, test.sortAsc)
+        input    := strings.Join(test.records, "\n") + "\n"
+        expected := ""
+        for _, k := range test.order { expected += test.records[k] + "\n" }
+        opts     := append([]Option{WithFields(test.fields), WithAscending(test.sortAsc)}, test.opts...)
+        if output := string(sortBytes(t, input, opts...)); output != expected {
+            t.Errorf("%s: output\n%q\nexpected\n%q", name, output, expected)
+        }
+    }
+} //end func TestNullsPlacement
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file nulls_test.go
@@ -0,0 +1,75 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     offsets.go
+ * Overview:
+ *     sort order of a text file handed back in memory as the byte spans of its records, for callers writing the records in
+ *     a format of their own rather than having the file rewritten.
+ * Functions:
+ *     SortOffsets(inFile string, opts ...Option) ([]RecordSpan, error)
+ *         Returns the byte spans of the records of a text file in sorted order.
+ * Types:
+ *     RecordSpan
+ *         Byte span of a record within its input.
+ * History:
+ *     v1.100.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "fmt"
+    "time"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+type RecordSpan struct {
+    Offset int64                     //offset of the record from the start of the input
+    Length int                       //bytes of the record, its line terminator included if any
+}
+
+func SortOffsets(inFile string, opts ...Option) (spans []RecordSpan, err error) {
+/*         Purpose : Returns the byte spans of the records of a text file in sorted order.
+ *       Arguments : inFile = path of the file with the data to be sorted.
+ *                   opts   = options, see NewSorter. WithFields is mandatory.
+ *         Returns : The spans of the records in sorted order, and any error encountered.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : NewSorter, catch, halt, haltKind, keyRecord, openKeys, publishStats, removeTemp, sortKeys, startHeartbeat
+ *         Remarks : The keys are generated, sorted and merged as by Sort, down to the single file of sorted keys, whose
+ *                   record offsets and lengths are then read into memory instead of the records being copied, so that
+ *                   the caller can read the records, e.g. through an io.ReaderAt over inFile, and write them as it sees
+ *                   fit. The spans take 16 bytes per record, which suits inputs of up to some tens of millions of
+ *                   records; SortIndex keeps the same order on disk for larger ones. The records filtered out or skipped as
+ *                   malformed have no span, nor the lines outside the range of WithLineRange, those within it keeping their
+ *                   offsets in the whole file. A last record lacking a line feed keeps its length, the output of Sort
+ *                   adding one. The options of the output phase, e.g. WithDedup, WithExpectUnique, WithRankColumn or the
+ *                   prepending of WithOriginalLineNumbers, do not apply, and WithRemoveInput, which would leave the spans
+ *                   pointing nowhere, is refused. The statistics of WithStats count the spans as the records output. The
+ *                   temporary files are removed before the spans are returned.
+ *         History : v1.100.0 - October 15, 2026 - Original release.
+ */
+    defer catch(&err)
+    sorter, err := NewSorter(opts...)
+    if err != nil { panic(haltError{err}) }
+    if sorter.removeInput { halt("WithRemoveInput does not apply to SortOffsets, whose spans refer to the input") }
+    run         := sorter.newRun()
+    run.indexing = true                                           //keys without embedded records
+    defer run.startHeartbeat()()
+    sortedKeysFile, numKeys, _ := run.sortKeys(inFile)
+    defer run.removeTemp(sortedKeysFile)
+    fhKeys, scannerKeys := run.openKeys(sortedKeysFile)
+    defer fhKeys.Close()
+    spans = make([]RecordSpan, 0, numKeys)
+    for scannerKeys.Scan() {
+        offset, length, err := keyRecord(scannerKeys.Text())
+        if err != nil { haltKind(ErrCorruptKeys, "keyRecord", err) }
+        spans = append(spans, RecordSpan{Offset:offset, Length:length})
+    }
+    if err := scannerKeys.Err(); err != nil { haltKind(nil, "scannerKeys.Scan", err) }
+    run.stats.OutputRecords = len(spans)
+    run.publishStats()
+    if run.verbose { fmt.Println("func SortOffsets - sorted", len(spans), "records in", time.Since(run.start)) }
+    return spans, nil
+} //end func SortOffsets
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file offsets.go
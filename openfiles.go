@@ -0,0 +1,137 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     openfiles.go
+ * Overview:
+ *     cap on the number of temporary files held open at once.
+ * Functions:
+ *     WithMaxOpenTempFiles(n int) Option
+ *         Option capping the number of temporary files the sorter holds open at once.
+ * History:
+ *     v1.31.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "math"
+    "sync"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func WithMaxOpenTempFiles(n int) Option {
+/*         Purpose : Caps the number of temporary files the sorter holds open at once.
+ *       Arguments : n = the cap. Must be at least 3.
+ *         Returns : The option.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : None.
+ *         Remarks : The cap is shared by all the runs of the sorter. Without the option, the sorters share a cap of half
+ *                   the soft limit of the process on open files, or _defaultMaxOpen where that limit is unknown. Merge
+ *                   tasks and the writing of the initial key files wait for open files to be closed rather than exceed
+ *                   the cap. The handles kept by the anonymous temporary files on Linux are counted too, from their
+ *                   creation to their removal, and take at most (n-3)/2 of the cap, named files being created past it.
+ *                   The merges take the rest, i.e. at most n-1-(n-3)/2 files at once whatever WithMergeFanIn requests,
+ *                   so that a merge and the handles kept stay within the cap together. The files held for a whole run,
+ *                   e.g. a spooled input or the sorted keys being output, are counted but never wait, so that the runs
+ *                   always progress: when they alone reach the cap, the tasks proceed one at a time. The input and
+ *                   output files of the runs are not counted.
+ *         History : v1.31.0 - October 15, 2026 - Original release.
+ */
+    return func(s *Sorter) { s.maxOpen = n }
+} //end func WithMaxOpenTempFiles
+//Private ----------------------------------------------------------------------------------------------------------------------
+const _defaultMaxOpen = 256 //cap on the open temporary files when the limit of the process is unknown
+var(
+    _sharedLimiter     *fileLimiter                               //cap shared by the sorters without their own
+    _sharedLimiterOnce sync.Once
+)
+type fileLimiter struct {
+    mutex    sync.Mutex
+    cond     *sync.Cond
+    max      int                                                  //cap on the open temporary files
+    open     int                                                  //number of open temporary files
+    held     int                                                  //number of handles kept by the anonymous files
+    reserved int                                                  //number of files reserved by the tasks under way
+    tasks    int                                                  //number of tasks under way
+}
+type trackedFile struct {
+    TempFile
+    limiter *fileLimiter
+    once    sync.Once
+}
+func newFileLimiter(max int) *fileLimiter {
+    l     := &fileLimiter{max:max}
+    l.cond = sync.NewCond(&l.mutex)
+    return l
+} //end func newFileLimiter
+func sharedLimiter() *fileLimiter {
+    //Returns the cap shared by the sorters, derived once from the limit of the process on open files
+    _sharedLimiterOnce.Do(func() {
+        max := _defaultMaxOpen
+        if soft, ok := openFilesLimit(); ok && soft / 2 >= 3 && soft / 2 <= math.MaxInt32 { max = int(soft / 2) }
+        _sharedLimiter = newFileLimiter(max)
+    })
+    return _sharedLimiter
+} //end func sharedLimiter
+func (l *fileLimiter) acquire(n int) {
+    //Waits until a task can open n files within the cap, unless no other task is under way. The reservation adds to the
+    //files the task then opens, which errs on the safe side.
+    l.mutex.Lock()
+    defer l.mutex.Unlock()
+    for l.tasks > 0 && l.open + l.held + l.reserved + n > l.max { l.cond.Wait() }
+    l.tasks++
+    l.reserved += n
+} //end func acquire
+func (l *fileLimiter) release(n int) {
+    //Ends a task that acquired n files
+    l.mutex.Lock()
+    l.tasks--
+    l.reserved -= n
+    l.mutex.Unlock()
+    l.cond.Broadcast()
+} //end func release
+func (l *fileLimiter) hold() bool {
+    //Counts the handle kept by a new anonymous file, returning false if it would take the handles kept past their share of
+    //the cap or the files counted past the cap. The file being created is already reserved by its task.
+    l.mutex.Lock()
+    defer l.mutex.Unlock()
+    if l.held + 1 > l.maxHeld() || l.open + l.held + l.reserved + 1 > l.max { return false }
+    l.held++
+    return true
+} //end func hold
+func (l *fileLimiter) maxHeld() int {
+    //Returns the share of the cap left to the handles kept by the anonymous files, the rest taking a merge of at least 2
+    //files into a third
+    return (l.max - 3) / 2
+} //end func maxHeld
+func (l *fileLimiter) unhold() {
+    //Ends the count of the handle kept by an anonymous file once it is removed
+    l.mutex.Lock()
+    l.held--
+    l.mutex.Unlock()
+    l.cond.Broadcast()
+} //end func unhold
+func (l *fileLimiter) track(fh TempFile) TempFile {
+    //Counts an open temporary file until it is closed
+    l.mutex.Lock()
+    l.open++
+    l.mutex.Unlock()
+    return &trackedFile{TempFile:fh, limiter:l}
+} //end func track
+func (f *trackedFile) Close() error {
+    err := f.TempFile.Close()
+    f.once.Do(func() {
+        f.limiter.mutex.Lock()
+        f.limiter.open--
+        f.limiter.mutex.Unlock()
+        f.limiter.cond.Broadcast()
+    })
+    return err
+} //end func Close
+func (r *sortRun) mergeFanIn() int {
+    //Returns the number of files per merge task, within the cap on open files less the handles the anonymous files may keep
+    if n := r.limiter.max - 1 - r.limiter.maxHeld(); r.fanIn > n && n >= 2 { return n }
+    return r.fanIn
+} //end func mergeFanIn
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file openfiles.go
@@ -0,0 +1,73 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     openfiles_test.go
+ * Overview:
+ *     tests of the cap on the temporary files held open at once.
+ * Functions:
+ *     TestMaxOpenTempFiles(t *testing.T)
+ *         Checks that a sort of many runs, merged concurrently, never holds more temporary files open than its cap.
+ * History:
+ *     v1.31.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "io/ioutil"
+    "math/rand"
+    "path/filepath"
+    "sync"
+    "testing"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func TestMaxOpenTempFiles(t *testing.T) {
+    input := randomInput(rand.New(rand.NewSource(3)), 5000)
+    dir   := t.TempDir()
+    inFile, outFile := filepath.Join(dir, "in.txt"), filepath.Join(dir, "out.txt")
+    if err := ioutil.WriteFile(inFile, []byte(input), 0644); err != nil { t.Fatal(err) }
+    for _, max := range []int{3, 4, 8} {
+        storage := &openCounter{TempStorage:newOSStorage(t.TempDir(), sharedLimiter())}
+        sorter, err := NewSorter(WithFields("2,1"), WithKeysPerSort(50), WithMergeFanIn(16), WithParallelism(4),
+                                 WithTempStorage(storage), WithMaxOpenTempFiles(max))
+        if err != nil { t.Fatal(err) }
+        if err := sorter.Run(inFile, outFile); err != nil { t.Fatalf("max=%d: %v", max, err) }
+        checkSorted(t, input, outFile, "2,1")
+        if storage.peak > max || storage.peak < 2 {
+            t.Errorf("max=%d: %d temporary files held open at once", max, storage.peak)
+        }
+        if storage.open != 0 { t.Errorf("max=%d: %d temporary files left open", max, storage.open) }
+    }
+} //end func TestMaxOpenTempFiles
+//Private ----------------------------------------------------------------------------------------------------------------------
+type openCounter struct {
+    TempStorage
+    mutex sync.Mutex
+    open  int                                                     //number of files open
+    peak  int                                                     //largest number of files open at once
+}
+type heldFile struct {
+    TempFile
+    storage *openCounter
+    once    sync.Once
+}
+func (s *openCounter) CreateTemp(prefix string) (TempFile, error) { return s.counted(s.TempStorage.CreateTemp(prefix)) }
+func (s *openCounter) Open(name string) (TempFile, error)         { return s.counted(s.TempStorage.Open(name)) }
+func (s *openCounter) counted(fh TempFile, err error) (TempFile, error) {
+    //Counts a file opened by the storage until it is closed
+    if err != nil { return nil, err }
+    s.mutex.Lock()
+    defer s.mutex.Unlock()
+    if s.open++; s.open > s.peak { s.peak = s.open }
+    return &heldFile{TempFile:fh, storage:s}, nil
+} //end func counted
+func (f *heldFile) Close() error {
+    f.once.Do(func() {                                            //files may be closed more than once
+        f.storage.mutex.Lock()
+        f.storage.open--
+        f.storage.mutex.Unlock()
+    })
+    return f.TempFile.Close()
+} //end func Close
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file openfiles_test.go
@@ -0,0 +1,64 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     outputlock.go
+ * Overview:
+ *     advisory locking of the output files while they are written.
+ * Functions:
+ *     WithOutputLockTimeout(timeout time.Duration) Option
+ *         Option waiting up to timeout for the lock of a busy output file.
+ * History:
+ *     v1.35.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "os"
+    "time"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func WithOutputLockTimeout(timeout time.Duration) Option {
+/*         Purpose : Waits up to timeout for the lock of a busy output file.
+ *       Arguments : timeout = the longest wait. 0, the default, fails at once.
+ *         Returns : The option.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : None.
+ *         Remarks : The output file, or the index file of SortIndex, is locked exclusively from its creation to its
+ *                   closing, with flock on the BSDs, Linux and macOS and LockFileEx on Windows. A file already locked
+ *                   by another sort, of this or another process, fails the sort with ErrOutputBusy once the timeout
+ *                   elapses, before the file is truncated. The lock is advisory: programs that do not lock the file
+ *                   can still write it. Elsewhere the files are not locked.
+ *         History : v1.35.0 - October 15, 2026 - Original release.
+ */
+    return func(s *Sorter) { s.lockWait = timeout }
+} //end func WithOutputLockTimeout
+//Private ----------------------------------------------------------------------------------------------------------------------
+const _lockPoll = 50 * time.Millisecond //interval between the attempts to lock a busy output file
+func (r *sortRun) lockOutput(file string) *os.File {
+    //Opens an output file, locks it exclusively, waiting for the lock up to lockWait, then truncates it
+    fh, err := os.OpenFile(file, os.O_WRONLY | os.O_CREATE, 0666)
+    if err != nil { halt("os.OpenFile - " + err.Error()) }
+    deadline := time.Now().Add(r.lockWait)
+    for {
+        locked, err := tryLock(fh)
+        if err != nil {
+            fh.Close()
+            halt("tryLock - " + err.Error())
+        }
+        if locked { break }
+        if !time.Now().Before(deadline) {
+            fh.Close()
+            haltKind(ErrOutputBusy, "the output file " + file + " is locked by another sort", nil)
+        }
+        time.Sleep(_lockPoll)
+    }
+    if err := fh.Truncate(0); err != nil {
+        fh.Close()
+        halt("fh.Truncate - " + err.Error())
+    }
+    return fh
+} //end func lockOutput
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file outputlock.go
@@ -0,0 +1,231 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     pack.go
+ * Overview:
+ *     packing of the initial runs of a sort into a few spill files: the runs are appended one after the other, each as the
+ *     bytes of a key file of its own, the file ends with an index of its runs, and the merges read each run as a range of
+ *     its spill file, named after the file and the range.
+ * Functions:
+ *     WithPackedRuns(runsPerFile int) Option
+ *         Option appending the initial runs of a sort to shared spill files.
+ * History:
+ *     v1.91.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "errors"
+    "fmt"
+    "io"
+    "strings"
+    "sync"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func WithPackedRuns(runsPerFile int) Option {
+/*         Purpose : Appends the initial runs of a sort to shared spill files, runsPerFile at most to a file.
+ *       Arguments : runsPerFile = the most runs appended to a spill file, or 0 or 1 for a key file per run, the default.
+ *         Returns : The option.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : None.
+ *         Remarks : Each run is appended to the open spill file exactly as it would be written to a key file of its own,
+ *                   with its codec header and trailer, and without a sync. Once it holds runsPerFile runs, or the keying
+ *                   ends, the spill file is closed on an index of its runs, one line "mergesort-pack-v1", offset, length
+ *                   and number of keys per run, separated by ASCII GS, then a line "mergesort-pack-v1", "index" and the
+ *                   offset of the index, and synced once. Its runs are then handed to the merges, which read each as the
+ *                   range of the file named after it, e.g. "keys_4242-1_123456@1048576+65536", and the file is removed
+ *                   with the last of them. A sort of small keysPerSort thus creates, opens, syncs and removes a spill file
+ *                   per runsPerFile runs rather than a key file per run, e.g. for a file system slow on its metadata or a
+ *                   quota on the number of files. The price is that the merges of the runs of a spill file wait for it to
+ *                   be closed, and that its bytes are given back to WithMaxTempSpace only once all its runs are merged;
+ *                   WithMaxLiveRuns counts the runs, those of the open spill file once it is closed, and Estimate does
+ *                   not model the files. The files of the merges, of Reverse and of a SpillQueue are written as before.
+ *         History : v1.91.0 - October 15, 2026 - Original release.
+ */
+    return func(s *Sorter) { s.packRuns = runsPerFile }
+} //end func WithPackedRuns
+//Private ----------------------------------------------------------------------------------------------------------------------
+const _packMagic = "mergesort-pack-v1" //first field of the lines of the index of a spill file
+type runPack struct {
+    name   string                                                 //name of the spill file
+    fh     TempFile                                               //the file, open for appending, nil once closed
+    size   int64                                                  //bytes written to it
+    runs   []packedRun                                            //its runs, in order
+    live   int                                                    //its runs not yet removed
+}
+type packedRun struct {
+    pack   *runPack                                               //spill file of the run
+    name   string                                                 //name of the run, the file's followed by its range
+    offset int64                                                  //offset of the run in the file
+    length int64                                                  //bytes of the run
+    keys   int                                                    //number of keys of the run
+}
+type runPacks struct {
+    mutex sync.Mutex
+    open  *runPack                                                //spill file being appended to, nil if none
+    packs map[string]*runPack                                     //spill files present, by name
+    runs  map[string]*packedRun                                   //runs not yet removed, by name
+}
+type packedRunFile struct {
+    TempFile                                                      //the spill file
+    name   string                                                 //name of the run
+    offset int64                                                  //offset of the run in the file
+    length int64                                                  //bytes of the run
+    pos    int64                                                  //position within the run
+}
+func newRunPacks() *runPacks {
+    return &runPacks{packs:map[string]*runPack{}, runs:map[string]*packedRun{}}
+} //end func newRunPacks
+func (r *sortRun) flushRun(keys []string) []string {
+    //Writes a run of sorted keys, returning the key files ready for merging: the run's, or those of a spill file closed
+    if r.packRuns < 2 { return []string{r.writeRunFile(keys)} }
+    r.giveWay()
+    r.sortRunKeys(keys)
+    r.packs.mutex.Lock()
+    pack := r.packs.open
+    r.packs.mutex.Unlock()
+    if pack == nil {
+        fh, name := r.createTemp(r.prefix)
+        pack      = &runPack{name:name, fh:fh}
+        r.packs.mutex.Lock()
+        r.packs.open, r.packs.packs[name] = pack, pack
+        r.packs.mutex.Unlock()
+    }
+    writer := r.newKeyWriter(pack.fh)
+    for _, v := range keys { writer.write(v) }
+    writer.close()
+    end, err := pack.fh.Seek(0, io.SeekCurrent)
+    if err != nil { haltTemp("Seek", err) }
+    run := packedRun{pack:pack, name:fmt.Sprintf("%s@%d+%d", pack.name, pack.size, end - pack.size), offset:pack.size,
+                     length:end - pack.size, keys:len(keys)}
+    pack.runs, pack.size = append(pack.runs, run), end
+    if r.verbose { fmt.Println("func Sort - packed", r.tempLabel(run.name) + r.placement(run.name)) }
+    if len(pack.runs) < r.packRuns { return nil }
+    return r.closePack()
+} //end func flushRun
+func (r *sortRun) closePack() []string {
+    //Ends the open spill file with the index of its runs and returns the names of its runs, none if no file is open
+    if r.packs == nil { return nil }
+    r.packs.mutex.Lock()
+    pack := r.packs.open
+    r.packs.mutex.Unlock()
+    if pack == nil { return nil }
+    index := &strings.Builder{}
+    for _, run := range pack.runs {
+        fmt.Fprintf(index, "%s%s%d%s%d%s%d\n", _packMagic, _asciiGS, run.offset, _asciiGS, run.length, _asciiGS, run.keys)
+    }
+    fmt.Fprintf(index, "%s%sindex%s%d\n", _packMagic, _asciiGS, _asciiGS, pack.size)
+    if _, err := io.WriteString(pack.fh, index.String()); err != nil { haltTemp("writing the index of the runs", err) }
+    if err := pack.fh.Sync();  err != nil { haltTemp("fhPack.Sync", err) }
+    if err := pack.fh.Close(); err != nil { haltTemp("fhPack.Close", err) }
+    names := make([]string, len(pack.runs))
+    r.packs.mutex.Lock()
+    for k := range pack.runs {
+        names[k]               = pack.runs[k].name
+        r.packs.runs[names[k]] = &pack.runs[k]
+    }
+    pack.fh, pack.live, r.packs.open = nil, len(pack.runs), nil
+    r.packs.mutex.Unlock()
+    if r.verbose { fmt.Println("func Sort - created", r.tempLabel(pack.name) + r.placement(pack.name), "with",
+                               len(pack.runs), "runs") }
+    return names
+} //end func closePack
+func (r *sortRun) abandonPack() {
+    //Closes the open spill file of a failed sort, leaving its removal to the cleanup of the key files
+    if r.packs == nil { return }
+    r.packs.mutex.Lock()
+    pack := r.packs.open
+    r.packs.open = nil
+    r.packs.mutex.Unlock()
+    if pack != nil { pack.fh.Close() }
+} //end func abandonPack
+func (p *runPacks) lookup(name string) *packedRun {
+    //Returns the run of a spill file of a name, nil if the name is not that of such a run
+    if p == nil { return nil }
+    p.mutex.Lock()
+    defer p.mutex.Unlock()
+    return p.runs[name]
+} //end func lookup
+func (r *sortRun) removeRun(name string) bool {
+    //Removes a run of a spill file, the file with its last run; returns false if the name is not that of such a run
+    if r.packs == nil { return false }
+    r.packs.mutex.Lock()
+    run := r.packs.runs[name]
+    if run == nil {
+        r.packs.mutex.Unlock()
+        return false
+    }
+    delete(r.packs.runs, name)
+    run.pack.live--
+    last := run.pack.live == 0
+    if last { delete(r.packs.packs, run.pack.name) }
+    r.packs.mutex.Unlock()
+    if last {
+        r.storage.Remove(run.pack.name)
+        r.space.release(run.pack.name)
+    }
+    return true
+} //end func removeRun
+func (r *sortRun) packedKeys(name string, keys int) int64 {
+    //Returns the number of keys of a packed run, or keys for a key file of its own
+    if run := r.packs.lookup(name); run != nil { return int64(run.keys) }
+    return int64(keys)
+} //end func packedKeys
+func (r *sortRun) listKeys(prefix string) []string {
+    //Returns the key files of a prefix, the spill files being replaced by their runs not yet removed
+    names := r.listTemp(prefix)
+    if r.packs == nil { return names }
+    keys  := make([]string, 0, len(names))
+    r.packs.mutex.Lock()
+    defer r.packs.mutex.Unlock()
+    for _, name := range names {
+        pack := r.packs.packs[name]
+        if pack == nil {
+            keys = append(keys, name)
+            continue
+        }
+        for _, run := range pack.runs {
+            if r.packs.runs[run.name] != nil { keys = append(keys, run.name) }
+        }
+    }
+    return keys
+} //end func listKeys
+func (r *sortRun) openStored(name string) (TempFile, error) {
+    //Opens a temporary file, or the range of its spill file for a packed run
+    run := r.packs.lookup(name)
+    if run == nil { return r.storage.Open(name) }
+    fh, err := r.storage.Open(run.pack.name)
+    if err != nil { return nil, err }
+    if _, err = fh.Seek(run.offset, io.SeekStart); err != nil {
+        fh.Close()
+        return nil, err
+    }
+    return &packedRunFile{TempFile:fh, name:name, offset:run.offset, length:run.length}, nil
+} //end func openStored
+func (f *packedRunFile) Read(p []byte) (int, error) {
+    if f.pos >= f.length { return 0, io.EOF }
+    if int64(len(p)) > f.length - f.pos { p = p[:f.length - f.pos] }
+    n, err := f.TempFile.Read(p)
+    f.pos += int64(n)
+    if err == io.EOF && f.pos < f.length { err = io.ErrUnexpectedEOF }
+    return n, err
+} //end func Read
+func (f *packedRunFile) Write(p []byte) (int, error) {
+    return 0, errors.New("mergesort: the packed run " + f.name + " is read-only")
+} //end func Write
+func (f *packedRunFile) Seek(offset int64, whence int) (int64, error) {
+    //Positions the file within the range of the run
+    switch whence {
+        case io.SeekCurrent: offset += f.pos
+        case io.SeekEnd:     offset += f.length
+    }
+    if offset < 0 { return f.pos, errors.New("mergesort: seek before the start of the packed run " + f.name) }
+    if _, err := f.TempFile.Seek(f.offset + offset, io.SeekStart); err != nil { return f.pos, err }
+    f.pos = offset
+    return offset, nil
+} //end func Seek
+func (f *packedRunFile) Name() string { return f.name }
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file pack.go
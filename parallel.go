@@ -0,0 +1,107 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     parallel.go
+ * Overview:
+ *     degree of parallelism of a sort, and parallel fetching of the records in the order of their keys.
+ * Functions:
+ *     WithParallelism(n int) Option
+ *         Option setting the number of goroutines working at once on a sort.
+ * History:
+ *     v1.36.0 - October 15, 2026 - Original release.
+ *     v1.37.0 - October 15, 2026 - The records are fetched by clusters.
+ *     v1.83.0 - October 15, 2026 - The fetching goroutines are labelled for the profiler.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "bufio"
+    "io"
+    "runtime"
+    "strconv"
+    "sync"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func WithParallelism(n int) Option {
+/*         Purpose : Sets the number of goroutines working at once on a sort.
+ *       Arguments : n = the degree of parallelism. Must be at least 1. Defaults to the number of CPUs.
+ *         Returns : The option.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : None.
+ *         Remarks : In the output phase, n goroutines fetch the records of the upcoming keys at once, each through its
+ *                   own positioned reads, while the writer outputs them in key order from a reordering buffer of
+ *                   _fetchAhead clusters of records per goroutine. The output is the same as that of a single
+ *                   goroutine, which n = 1 requests. Inputs that do not implement io.ReaderAt, e.g. those spooled to
+ *                   the temporary storage, are read by the writer alone.
+ *         History : v1.36.0 - October 15, 2026 - Original release.
+ */
+    return func(s *Sorter) { s.parallelism = n }
+} //end func WithParallelism
+//Private ----------------------------------------------------------------------------------------------------------------------
+const _fetchAhead = 16 //clusters of records fetched ahead of the writer per fetching goroutine
+func defaultParallelism() int {
+    return runtime.NumCPU()
+} //end func defaultParallelism
+func (r *sortRun) fetchRecords(fhIn io.ReaderAt, keysFile string, scannerKeys *bufio.Scanner,
+                               emit func(key, record string, lastInGroup bool) bool) {
+    //Feeds emit with the records of fhIn in the order of the keys of keysFile, fetched by r.parallelism goroutines, until
+    //the keys run out or emit returns false
+    var(
+        at        RecordError                                             //key being processed
+        jobs      = make(chan *recordCluster, _fetchAhead * r.parallelism) //clusters to be fetched
+        pending   = make(chan *recordCluster, _fetchAhead * r.parallelism) //clusters to be emitted, in key order
+        quit      = make(chan struct{})                                   //closed when the writer stops
+        sync4Jobs sync.WaitGroup                                          //completion of the feeder & fetchers
+    )
+
+    defer blame(&at)
+    defer func() {
+        close(quit)
+        sync4Jobs.Wait()
+    }()
+    //Feed the clusters in key order
+    sync4Jobs.Add(1)
+    go func() {
+        defer sync4Jobs.Done()
+        r.labelWorker("output", "feeder")
+        defer close(pending)
+        defer close(jobs)
+        clusters := newClusterScanner(scannerKeys)
+        for {
+            cluster, ok := clusters.next()
+            if !ok { return }
+            cluster.done = make(chan struct{})
+            select {
+                case pending<- cluster:
+                case <-quit: return
+            }
+            jobs<- cluster
+        }
+    }()
+    //Fetch the clusters
+    for k := 0; k < r.parallelism; k++ {
+        sync4Jobs.Add(1)
+        go func(worker string) {
+            defer sync4Jobs.Done()
+            r.labelWorker("output", worker)
+            for cluster := range jobs {
+                select {
+                    case <-quit:
+                    default:     cluster.readAt(fhIn)
+                }
+                close(cluster.done)
+            }
+        }(strconv.Itoa(k))
+    }
+    //Emit the records in key order
+    emitClusters(func() (*recordCluster, bool) {
+        cluster, ok := <-pending
+        if ok { <-cluster.done }
+        return cluster, ok
+    }, keysFile, &at, emit)
+    return
+} //end func fetchRecords
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file parallel.go
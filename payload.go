@@ -0,0 +1,55 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     payload.go
+ * Overview:
+ *     short records carried in their composite keys, so that the output phase need not read them back from the input.
+ * Functions:
+ *     WithPayloadKeys(maxRecordLen int) Option
+ *         Option embedding the records of up to maxRecordLen bytes in their composite keys.
+ * History:
+ *     v1.38.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "fmt"
+    "strings"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func WithPayloadKeys(maxRecordLen int) Option {
+/*         Purpose : Embeds the records of up to maxRecordLen bytes in their composite keys.
+ *       Arguments : maxRecordLen = the longest record embedded, its terminator included. 0, the default, embeds none.
+ *         Returns : The option.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : None.
+ *         Remarks : An embedded record travels through the run and merge files with its key and is written from it in
+ *                   the output phase, which then reads nothing from the input. Longer records, and those holding the
+ *                   ascii group or record separators, keep the key form pointing at their offset, so that a file
+ *                   mixing short and long records is output partly from its keys and partly from the input. The key
+ *                   files grow by the embedded records, as does the memory taken by each run of keysPerSort keys.
+ *                   The index files of SortIndex never embed records.
+ *         History : v1.38.0 - October 15, 2026 - Original release.
+ */
+    return func(s *Sorter) { s.payloadMax = maxRecordLen }
+} //end func WithPayloadKeys
+//Private ----------------------------------------------------------------------------------------------------------------------
+var _asciiRS = fmt.Sprintf("%c", 30) //ascii character for record separator, enclosing an embedded record
+func (r *sortRun) payloadKey(key, record string) string {
+    //Appends the record, without its line feed, to its composite key if it is to be embedded. The record is enclosed by ascii
+    //record separators, the closing one protecting a carriage return from the scanners of the key files.
+    if r.payloadMax == 0 || r.indexing || len(record) > r.payloadMax { return key }
+    body := strings.TrimSuffix(record, "\n")
+    if strings.ContainsAny(body, _asciiGS + _asciiRS) { return key }
+    return key + _asciiRS + body + _asciiRS
+} //end func payloadKey
+func splitPayload(key string) (string, string, bool) {
+    //Returns the composite key without its embedded record, the record with its line feed, and whether there was one
+    k := strings.Index(key, _asciiRS)
+    if k < 0 || !strings.HasSuffix(key, _asciiRS) || k == len(key) - 1 { return key, "", false }
+    return key[:k], key[k + 1:len(key) - 1] + "\n", true
+} //end func splitPayload
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file payload.go
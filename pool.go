@@ -0,0 +1,175 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     pool.go
+ * Overview:
+ *     pool of workers, memory for the runs and temporary space shared by the sorts of a long-running process, the sorts
+ *     queueing for their share of the limits rather than each claiming them whole.
+ * Functions:
+ *     NewPool(limits PoolLimits) (*Pool, error)
+ *         Creates a pool of sorts sharing the limits.
+ *     (p *Pool) Sort(ctx context.Context, inFile, outFile string, opts ...Option) (SortStats, error)
+ *         Sorts a text file once its share of the limits of the pool is free.
+ * Types:
+ *     Pool
+ *         Sorts sharing workers, memory for their runs and temporary space.
+ *     PoolLimits
+ *         Limits shared by the sorts of a pool.
+ * History:
+ *     v1.98.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "context"
+    "fmt"
+    "sync"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+type PoolLimits struct {
+    Workers   int                                                 //goroutines working at once, 0 for the number of CPUs
+    RunMemory int64                                               //bytes of the runs held at once, 0 for no cap
+    TempSpace int64                                               //bytes of temporary files present at once, 0 for no cap
+}
+type Pool struct {
+    limits  PoolLimits
+    mutex   sync.Mutex
+    queue   []*poolJob                                            //sorts waiting for their share, in order of arrival
+    workers int                                                   //workers claimed by the sorts running
+    temp    int64                                                 //temporary space reserved by the sorts running
+    space   *tempSpace                                            //bytes of the temporary files of all the sorts
+}
+
+func NewPool(limits PoolLimits) (p *Pool, err error) {
+/*         Purpose : Creates a pool of sorts sharing workers, memory for their runs and temporary space.
+ *       Arguments : limits = the limits shared by the sorts, none of which may be negative.
+ *         Returns : The pool, and any error in the limits.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : catch, defaultParallelism, halt
+ *         Remarks : A pool is created once, e.g. by a service sorting many files at once, and used from any number of
+ *                   goroutines through its Sort method.
+ *         History : v1.98.0 - October 15, 2026 - Original release.
+ */
+    defer catch(&err)
+    if limits.Workers < 0 || limits.RunMemory < 0 || limits.TempSpace < 0 { halt("the limits of a pool cannot be negative") }
+    if limits.Workers == 0 { limits.Workers = defaultParallelism() }
+    space := &tempSpace{limit:limits.TempSpace, files:map[string]int64{}, owner:"the pool"}
+    return &Pool{limits:limits, space:space}, nil
+} //end func NewPool
+func (p *Pool) Sort(ctx context.Context, inFile, outFile string, opts ...Option) (stats SortStats, err error) {
+/*         Purpose : Sorts a text file once its share of the limits of the pool is free.
+ *       Arguments : ctx     = context whose cancellation aborts the sort, whether queued or running.
+ *                   inFile  = path of the file with the data to be sorted.
+ *                   outFile = path of the file for the sorted data.
+ *                   opts    = options, see NewSorter. WithFields is mandatory.
+ *         Returns : The statistics of the sort, and any error encountered.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : NewSorter, catch, estimate, halt, interrupted
+ *         Remarks : Each sort claims as many workers as its WithParallelism, 1 by default within a pool and at most
+ *                   Workers, and as much temporary space as its peak worked out as Estimate does. The sorts start in
+ *                   order of arrival as soon as their claims fit within the limits beside those of the sorts running, a
+ *                   sort whose claims do not fit holding up those after it, so that none starves. A sort whose estimated
+ *                   peak exceeds TempSpace fails at once with ErrTempSpace. The memory of a sort is its share of
+ *                   RunMemory in proportion to its workers: it caps the budget of its runs, which grow per
+ *                   WithAdaptiveRunSize from keysPerSort, the size of the first run and the floor of the others, so that
+ *                   keysPerSort should fit within the share, and the in-memory budget of WithInMemorySpillThreshold. The
+ *                   bytes of the temporary files of all the sorts are counted against TempSpace while they run, the sort
+ *                   writing past it failing with ErrTempSpace as with WithMaxTempSpace, which still caps each sort.
+ *                   Each sort keeps its own sorter, temporary file prefix and statistics, which are also stored per
+ *                   WithStats. A cancellation while queued returns at once, and while running, stops the sort at its
+ *                   next record or merged key, its temporary files being removed as on any failure; both errors match
+ *                   ErrInterrupted and ctx.Err().
+ *         History : v1.98.0 - October 15, 2026 - Original release.
+ */
+    defer catch(&err)
+    sorter, err := NewSorter(append([]Option{WithParallelism(1)}, opts...)...)
+    if err != nil { panic(haltError{err}) }
+    job := &poolJob{workers:sorter.parallelism, ready:make(chan struct{})}
+    if job.workers > p.limits.Workers { job.workers = p.limits.Workers }
+    if p.limits.RunMemory > 0 {
+        share := p.limits.RunMemory * int64(job.workers) / int64(p.limits.Workers)
+        sorter.adaptiveRuns = true
+        if sorter.runBudget == 0 || sorter.runBudget > share { sorter.runBudget = share }
+        if sorter.memBudget > share {                             //the tiered storage, built last, rebuilt on the share
+            sorter.memBudget = share
+            sorter.storage   = newTieredStorage(sorter.storage.(*tieredStorage).backing, share)
+        }
+    }
+    if p.limits.TempSpace > 0 {
+        job.temp = sorter.newRun().estimate(EstimateInput{File:inFile}).PeakTempBytes
+        if job.temp > p.limits.TempSpace {
+            panic(haltError{&kindError{kind:ErrTempSpace, err:fmt.Errorf("mergesort: the estimated peak of %d bytes " +
+                                       "of temporary space exceeds the %d bytes of the pool", job.temp, p.limits.TempSpace)}})
+        }
+    }
+    if err := p.wait(ctx, job); err != nil { return stats, err }
+    defer p.release(job)
+    statsOut := sorter.statsOut
+    sorter.statsOut, sorter.jobCtx, sorter.poolSpace = &stats, ctx, p.space
+    err = sorter.Run(inFile, outFile)
+    if statsOut != nil && err == nil { *statsOut = stats }
+    return stats, err
+} //end func Sort
+//Private ----------------------------------------------------------------------------------------------------------------------
+const _doneEvery = 4096 //keys merged between two checks of the cancellation of a sort of a pool
+type poolJob struct {
+    workers int                                                   //workers claimed
+    temp    int64                                                 //temporary space reserved
+    ready   chan struct{}                                         //closed once the claims are granted
+}
+func (p *Pool) wait(ctx context.Context, job *poolJob) error {
+    //Queues a sort until its claims are granted, returning the error of a cancellation meanwhile, the claims being given back
+    //if granted all the same
+    p.mutex.Lock()
+    p.queue = append(p.queue, job)
+    p.admit()
+    p.mutex.Unlock()
+    select {
+        case <-job.ready:
+            return nil
+        case <-ctx.Done():
+    }
+    p.mutex.Lock()
+    defer p.mutex.Unlock()
+    select {
+        case <-job.ready:                                         //granted meanwhile
+            p.workers, p.temp = p.workers - job.workers, p.temp - job.temp
+        default:
+            for k, queued := range p.queue {
+                if queued == job { p.queue = append(p.queue[:k], p.queue[k + 1:]...); break }
+            }
+    }
+    p.admit()
+    return interrupted(ctx.Err())
+} //end func wait
+func (p *Pool) release(job *poolJob) {
+    //Gives back the claims of a completed sort and starts the sorts queued that now fit
+    p.mutex.Lock()
+    p.workers, p.temp = p.workers - job.workers, p.temp - job.temp
+    p.admit()
+    p.mutex.Unlock()
+} //end func release
+func (p *Pool) admit() {
+    //Grants their claims to the sorts at the head of the queue as long as they fit; to be called with the mutex held
+    for len(p.queue) > 0 {
+        job := p.queue[0]
+        if p.workers + job.workers > p.limits.Workers { return }
+        if p.limits.TempSpace > 0 && p.temp + job.temp > p.limits.TempSpace { return }
+        p.workers, p.temp = p.workers + job.workers, p.temp + job.temp
+        p.queue = p.queue[1:]
+        close(job.ready)
+    }
+} //end func admit
+func (s *Sorter) checkDone() {
+    //Halts with ErrInterrupted once the context of a sort of a pool is cancelled
+    if s.jobCtx == nil { return }
+    select {
+        case <-s.jobCtx.Done(): panic(haltError{interrupted(s.jobCtx.Err())})
+        default:
+    }
+} //end func checkDone
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file pool.go
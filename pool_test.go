@@ -0,0 +1,194 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     pool_test.go
+ * Overview:
+ *     tests of the sorts of a pool: their share of the memory, their order of arrival, the temporary space they claim and
+ *     their cancellation while queued.
+ * Functions:
+ *     TestPoolMemoryShare(t *testing.T)
+ *         Checks that the in-memory budget of a sort is capped by its share of RunMemory.
+ *     TestPoolQueueOrder(t *testing.T)
+ *         Checks that queued sorts start in their order of arrival.
+ *     TestPoolTempSpace(t *testing.T)
+ *         Checks that a sort whose estimated peak exceeds TempSpace fails at once with ErrTempSpace.
+ *     TestPoolCancelQueued(t *testing.T)
+ *         Checks that a sort cancelled while queued returns at once and gives back its place.
+ * History:
+ *     v1.98.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "context"
+    "errors"
+    "fmt"
+    "math/rand"
+    "os"
+    "path/filepath"
+    "sync"
+    "testing"
+    "time"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func TestPoolMemoryShare(t *testing.T) {
+    //An input sorted in memory with its own budget, but not within the share of 1 worker out of 2
+    const runMemory = 256 << 10
+    inFile := writeInput(t, randomInput(rand.New(rand.NewSource(15)), 5000))
+    info, err := os.Stat(inFile)
+    if err != nil { t.Fatal(err) }
+    if 2 * info.Size() <= runMemory / 2 || 2 * info.Size() > 1 << 30 { t.Fatalf("input of %d bytes", info.Size()) }
+    for _, pooled := range []bool{false, true} {
+        var stats SortStats
+        opts := []Option{WithFields("2,1"), WithInMemorySpillThreshold(1 << 30), WithTempDir(t.TempDir()), WithStats(&stats)}
+        if pooled {
+            pool, err := NewPool(PoolLimits{Workers:2, RunMemory:runMemory})
+            if err != nil { t.Fatal(err) }
+            if _, err := pool.Sort(context.Background(), inFile, filepath.Join(t.TempDir(), "out.txt"), opts...); err != nil {
+                t.Fatal(err)
+            }
+        } else {
+            sorter, err := NewSorter(opts...)
+            if err != nil { t.Fatal(err) }
+            if err := sorter.Run(inFile, filepath.Join(t.TempDir(), "out.txt")); err != nil { t.Fatal(err) }
+        }
+        if inMemory := stats.TempBytes == 0; inMemory == pooled {
+            t.Errorf("pooled %v: %d temporary bytes written, expected the in-memory path to be %v", pooled, stats.TempBytes,
+                     !pooled)
+        }
+    }
+    //The budget of the tiered storage itself capped by the share
+    pool, err := NewPool(PoolLimits{Workers:2, RunMemory:runMemory})
+    if err != nil { t.Fatal(err) }
+    var sorter *Sorter
+    capture := func(s *Sorter) { sorter = s }                     //the sorter of the pool's sort, as built by NewSorter
+    if _, err := pool.Sort(context.Background(), inFile, filepath.Join(t.TempDir(), "out.txt"), WithFields("2,1"),
+                           WithInMemorySpillThreshold(1 << 30), WithTempDir(t.TempDir()), capture); err != nil {
+        t.Fatal(err)
+    }
+    if budget := sorter.storage.(*tieredStorage).budget; budget != runMemory / 2 {
+        t.Errorf("in-memory budget of %d bytes, expected the share of %d", budget, runMemory / 2)
+    }
+} //end func TestPoolMemoryShare
+func TestPoolQueueOrder(t *testing.T) {
+    //A sort holding the only worker, then sorts queued behind it, which must start in their order of arrival
+    pool, err := NewPool(PoolLimits{Workers:1})
+    if err != nil { t.Fatal(err) }
+    inFile := writeInput(t, randomInput(rand.New(rand.NewSource(16)), 200))
+    var(
+        mutex   sync.Mutex
+        started []string
+        wg      sync.WaitGroup
+    )
+    release := make(chan struct{})
+    sortAs  := func(name string, ctx context.Context, block <-chan struct{}) error {
+        var once sync.Once
+        normalizer := func(value string) string {
+            once.Do(func() {
+                mutex.Lock()
+                started = append(started, name)
+                mutex.Unlock()
+                if block != nil { <-block }
+            })
+            return value
+        }
+        _, err := pool.Sort(ctx, inFile, filepath.Join(t.TempDir(), name + ".txt"), WithFields("2,1"),
+                            WithKeyNormalizer(2, normalizer), WithTempDir(t.TempDir()))
+        return err
+    }
+    errs := make([]error, 4)
+    wg.Add(1)
+    go func() { defer wg.Done(); errs[0] = sortAs("first", context.Background(), release) }()
+    waitFor(t, func() bool { mutex.Lock(); defer mutex.Unlock(); return len(started) == 1 })
+    for k, name := range []string{"second", "third", "fourth"} {
+        wg.Add(1)
+        go func(k int, name string) { defer wg.Done(); errs[k] = sortAs(name, context.Background(), nil) }(k + 1, name)
+        waitFor(t, func() bool { return queued(pool) == k + 1 })
+    }
+    close(release)
+    wg.Wait()
+    for k, err := range errs {
+        if err != nil { t.Fatalf("sort %d: %v", k, err) }
+    }
+    if order := fmt.Sprint(started); order != "[first second third fourth]" {
+        t.Errorf("sorts started in the order %s", order)
+    }
+} //end func TestPoolQueueOrder
+func TestPoolTempSpace(t *testing.T) {
+    pool, err := NewPool(PoolLimits{Workers:1, TempSpace:1000})
+    if err != nil { t.Fatal(err) }
+    inFile  := writeInput(t, randomInput(rand.New(rand.NewSource(17)), 2000))
+    outFile := filepath.Join(t.TempDir(), "out.txt")
+    start   := time.Now()
+    _, err   = pool.Sort(context.Background(), inFile, outFile, WithFields("2,1"), WithKeysPerSort(100),
+                         WithTempDir(t.TempDir()))
+    if !errors.Is(err, ErrTempSpace) { t.Fatalf("error %v, expected ErrTempSpace", err) }
+    if elapsed := time.Since(start); elapsed > time.Second { t.Errorf("failed after %v, expected at once", elapsed) }
+    if _, err := os.Stat(outFile); !os.IsNotExist(err) { t.Errorf("output created by a sort that failed at once (%v)", err) }
+} //end func TestPoolTempSpace
+func TestPoolCancelQueued(t *testing.T) {
+    pool, err := NewPool(PoolLimits{Workers:1})
+    if err != nil { t.Fatal(err) }
+    inFile  := writeInput(t, randomInput(rand.New(rand.NewSource(18)), 200))
+    release := make(chan struct{})
+    running := make(chan struct{})
+    var once sync.Once
+    blocker := func(value string) string {
+        once.Do(func() {
+            close(running)
+            <-release
+        })
+        return value
+    }
+    errFirst := make(chan error, 1)
+    go func() {
+        _, err := pool.Sort(context.Background(), inFile, filepath.Join(t.TempDir(), "first.txt"), WithFields("2,1"),
+                            WithKeyNormalizer(2, blocker), WithTempDir(t.TempDir()))
+        errFirst <- err
+    }()
+    <-running
+    //A sort queued behind the first, cancelled before it gets the worker
+    ctx, cancel := context.WithCancel(context.Background())
+    errQueued   := make(chan error, 1)
+    outQueued   := filepath.Join(t.TempDir(), "queued.txt")
+    go func() {
+        _, err := pool.Sort(ctx, inFile, outQueued, WithFields("2,1"), WithTempDir(t.TempDir()))
+        errQueued <- err
+    }()
+    waitFor(t, func() bool { return queued(pool) == 1 })
+    cancel()
+    select {
+        case err := <-errQueued:
+            if !errors.Is(err, ErrInterrupted) || !errors.Is(err, context.Canceled) {
+                t.Errorf("error %v, expected ErrInterrupted and context.Canceled", err)
+            }
+        case <-time.After(5 * time.Second):
+            t.Fatal("the cancelled sort is still queued")
+    }
+    if n := queued(pool); n != 0 { t.Errorf("%d sorts still queued", n) }
+    if _, err := os.Stat(outQueued); !os.IsNotExist(err) { t.Errorf("output created by the cancelled sort (%v)", err) }
+    //The first sort completing, and the worker free for the next
+    close(release)
+    if err := <-errFirst; err != nil { t.Fatal(err) }
+    if _, err := pool.Sort(context.Background(), inFile, filepath.Join(t.TempDir(), "next.txt"), WithFields("2,1"),
+                           WithTempDir(t.TempDir())); err != nil {
+        t.Fatal(err)
+    }
+} //end func TestPoolCancelQueued
+//Private ----------------------------------------------------------------------------------------------------------------------
+func queued(p *Pool) int {
+    //Returns the number of sorts queued by a pool
+    p.mutex.Lock()
+    defer p.mutex.Unlock()
+    return len(p.queue)
+} //end func queued
+func waitFor(t *testing.T, cond func() bool) {
+    //Waits up to 5 seconds for a condition to hold, failing the test otherwise
+    t.Helper()
+    for deadline := time.Now().Add(5 * time.Second); !cond(); time.Sleep(time.Millisecond) {
+        if time.Now().After(deadline) { t.Fatal("timed out waiting") }
+    }
+} //end func waitFor
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file pool_test.go
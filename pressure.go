@@ -0,0 +1,73 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     pressure.go
+ * Overview:
+ *     early flushing of the initial runs under memory pressure: while a run accumulates its keys, the heap is read at
+ *     regular intervals and the run is flushed as soon as it nears a fraction of the memory limit.
+ * Functions:
+ *     WithMemoryPressure(fraction float64) Option
+ *         Option flushing the initial runs early when the heap nears a fraction of the memory limit.
+ * History:
+ *     v1.93.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "fmt"
+    "math"
+    "runtime/debug"
+    "runtime/metrics"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func WithMemoryPressure(fraction float64) Option {
+/*         Purpose : Flushes the initial runs of the sorts early when the heap nears a fraction of the memory limit.
+ *       Arguments : fraction = the fraction of the memory limit, above 0 and at most 1, e.g. 0.8, or 0 to never flush
+ *                              early, the default.
+ *         Returns : The option.
+ * Externals -  In : _pressureEvery
+ * Externals - Out : None.
+ *       Functions : None.
+ *         Remarks : Every _pressureEvery keys added to a run, the bytes of the objects on the heap, per runtime/metrics,
+ *                   are compared with the fraction of the memory limit: the Go memory limit set by debug.SetMemoryLimit or
+ *                   GOMEMLIMIT, else the MemTotal of /proc/meminfo; without either, the runs are never flushed early. At or
+ *                   above it, the run is flushed as it stands rather than once it holds keysPerSort keys, unless no
+ *                   garbage collection has completed since the last such flush, whose keys would still count. The heap thus
+ *                   covers whatever else the process holds, which a fixed keysPerSort or WithAdaptiveRunSize budget does
+ *                   not see, at the price of more, smaller runs to merge. Each early flush is counted in
+ *                   SortStats.PressureFlushes and echoed with the heap and limit in verbose mode. The runs then depend on
+ *                   the memory of the process rather than on the keys only, as WithDeterministic requires. The runs of
+ *                   Reverse and the spills of a SpillQueue are not flushed early.
+ *         History : v1.93.0 - October 15, 2026 - Original release.
+ */
+    return func(s *Sorter) { s.pressure = fraction }
+} //end func WithMemoryPressure
+//Private ----------------------------------------------------------------------------------------------------------------------
+const _pressureEvery = 1024 //keys added to a run between two readings of the heap
+func (r *sortRun) underPressure(keys int) bool {
+    //Returns whether a run of keys is to be flushed early, the heap having reached the fraction of the memory limit
+    if r.pressure == 0 || keys == 0 || keys % _pressureEvery != 0 { return false }
+    if r.memLimit == 0 { r.memLimit = memoryLimit() }
+    if r.memLimit < 0 { return false }
+    samples := []metrics.Sample{{Name:"/memory/classes/heap/objects:bytes"}, {Name:"/gc/cycles/total:gc-cycles"}}
+    metrics.Read(samples)
+    if samples[0].Value.Kind() != metrics.KindUint64 || samples[1].Value.Kind() != metrics.KindUint64 { return false }
+    heap, cycles := int64(samples[0].Value.Uint64()), samples[1].Value.Uint64()
+    if float64(heap) < r.pressure * float64(r.memLimit) || cycles == r.gcCycles { return false }
+    r.stats.PressureFlushes++
+    r.gcCycles = cycles
+    if r.verbose {
+        fmt.Println("func Sort - memory pressure:", heap, "heap bytes of a limit of", r.memLimit, "- flushing a run of",
+                    keys, "keys")
+    }
+    return true
+} //end func underPressure
+func memoryLimit() int64 {
+    //Returns the Go memory limit, else the memory of the machine per /proc/meminfo, -1 if neither is known
+    if limit := debug.SetMemoryLimit(-1); limit < math.MaxInt64 { return limit }
+    if total, ok := memInfo("MemTotal:"); ok { return total }
+    return -1
+} //end func memoryLimit
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file pressure.go
@@ -0,0 +1,35 @@
+//go:build linux
+// +build linux
+
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     priority_linux.go
+ * Overview:
+ *     lowest CPU and idle I/O priorities for the thread of a goroutine, through setpriority and ioprio_set.
+ * History:
+ *     v1.85.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "runtime"
+    "syscall"
+)
+//Private ----------------------------------------------------------------------------------------------------------------------
+const(
+    _nicest         = 19                                          //lowest CPU priority
+    _ioprioWhoProc  = 1                                           //IOPRIO_WHO_PROCESS, a thread id on Linux
+    _ioprioIdle     = 3 << 13                                     //IOPRIO_CLASS_IDLE, served when the disk is otherwise idle
+)
+func lowerThreadPriority() {
+    //Locks the calling goroutine to its thread for good and gives the thread the lowest CPU and I/O priorities, where
+    //permitted; the thread ends with the goroutine, so that the priorities never outlive it
+    runtime.LockOSThread()
+    tid := syscall.Gettid()
+    syscall.Setpriority(syscall.PRIO_PROCESS, tid, _nicest)
+    syscall.Syscall(syscall.SYS_IOPRIO_SET, _ioprioWhoProc, uintptr(tid), _ioprioIdle)
+} //end func lowerThreadPriority
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file priority_linux.go
@@ -0,0 +1,21 @@
+//go:build !linux
+// +build !linux
+
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     priority_other.go
+ * Overview:
+ *     priority of the thread of a goroutine, on the systems where it is left as is.
+ * History:
+ *     v1.85.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+//Private ----------------------------------------------------------------------------------------------------------------------
+func lowerThreadPriority() {
+    //Leaves the priority of the thread as is, the threads of a process not having their own elsewhere
+} //end func lowerThreadPriority
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file priority_other.go
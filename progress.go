@@ -0,0 +1,208 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     progress.go
+ * Overview:
+ *     progress of the merge phase, as the pass under way out of those expected and the task completed out of those of the
+ *     pass, and of the width scan and key generation, as the bytes of the input read, echoed in verbose mode and handed to
+ *     callbacks.
+ * Functions:
+ *     WithMergeProgress(fn func(MergeProgress)) Option
+ *         Option calling fn as each merge task completes.
+ *     WithReadProgress(fn func(ReadProgress)) Option
+ *         Option calling fn as the width scan and key generation read the input.
+ * Types:
+ *     MergeProgress
+ *         Progress of the merge phase of a sort.
+ *     ReadProgress
+ *         Progress of the width scan or key generation of a sort.
+ * History:
+ *     v1.77.0 - October 15, 2026 - Original release.
+ *     v1.87.0 - October 15, 2026 - A last pass merges the smallest files first.
+ *     v1.95.0 - October 15, 2026 - Added WithReadProgress & the progress bars of the width scan and key generation.
+ *     v1.99.0 - October 15, 2026 - The reports timed for the silences of WithHeartbeat.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "fmt"
+    "sync"
+    "time"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+type MergeProgress struct {
+    Pass   int                                                    //pass under way, the first being 1
+    Passes int                                                    //expected number of passes, 0 while still unknown
+    Task   int                                                    //tasks completed in the pass
+    Tasks  int                                                    //tasks of the pass, 0 while still unknown
+}
+type ReadProgress struct {
+    Phase string                                                  //"width scan" or "key generation", as in SortStats
+    Bytes int64                                                   //bytes of the input read by the phase so far
+    Size  int64                                                   //bytes of the input the phase reads
+}
+
+func WithMergeProgress(fn func(MergeProgress)) Option {
+/*         Purpose : Calls fn as each merge task completes.
+ *       Arguments : fn = the callback, nil for none, the default.
+ *         Returns : The option.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : None.
+ *         Remarks : A merge task merges up to WithMergeFanIn key files into one. The first pass merges the runs in tasks of
+ *                   that size as they are created, and a last pass merges the files left down to one, each task taking the
+ *                   smallest files. As the first pass overlaps the creation of the runs, its tasks and the passes expected
+ *                   are unknown, and reported as 0, until the last run is written. They are then worked out from the number
+ *                   of files left, and the tasks of the last pass at its start from the files pending and the fan-in, so
+ *                   that a fan-in lowered by WithMaxOpenTempFiles updates the estimate. fn is called with Task set to the
+ *                   tasks completed so far as each task completes, and whenever the estimate is worked out, e.g. with Task 0
+ *                   at the start of the last pass. The verbose echo of each merge reports the same progress, e.g. "pass 2 of
+ *                   2, task 4 of 9". fn is called one call at a time, from the goroutine running the merges or the sort, and
+ *                   should return quickly. Sorts held in a single run have no merges, and MergeRuns merges its runs in a
+ *                   single pass. WithFrequencyOrder sorts the keys twice, each with its own passes.
+ *         History : v1.77.0 - October 15, 2026 - Original release.
+ *                   v1.87.0 - October 15, 2026 - A last pass merges the smallest files first.
+ */
+    return func(s *Sorter) { s.onMerge = fn }
+} //end func WithMergeProgress
+func WithReadProgress(fn func(ReadProgress)) Option {
+/*         Purpose : Calls fn as the width scan and the key generation read the input.
+ *       Arguments : fn = the callback, nil for none, the default.
+ *         Returns : The option.
+ * Externals -  In : _echoEvery, _progressEvery
+ * Externals - Out : None.
+ *       Functions : None.
+ *         Remarks : The two passes over the input that precede the merges read it whole before the first merge, so that a large
+ *                   input otherwise shows no progress for minutes. Each reports the bytes it has read out of those it reads,
+ *                   i.e. the input, or the byte range of GenerateRuns for its key generation, at most every _progressEvery and
+ *                   once more, with the bytes read in all, as it ends. The Phase names it as SortStats.Phases does, and Bytes
+ *                   never exceeds Size. The verbose echo prints the progress as a bar in kilobytes, e.g. "func Sort - key
+ *                   generation (KB): 5120 / 61440", at most every _echoEvery and as the phase ends if echoed before, each on a
+ *                   line of its own as the runs are echoed meanwhile. The width scan of a sample of WithSampledWidths, which
+ *                   reads little, is not reported. fn is called from the goroutine reading the input, one call at a time, and
+ *                   should return quickly. The key generation includes the sorting and writing of its runs, the merges
+ *                   reporting their own progress through WithMergeProgress.
+ *         History : v1.95.0 - October 15, 2026 - Original release.
+ */
+    return func(s *Sorter) { s.onRead = fn }
+} //end func WithReadProgress
+//Private ----------------------------------------------------------------------------------------------------------------------
+const(
+    _progressEvery = 250 * time.Millisecond //least time between two calls of the callback of the bytes of the input read
+    _echoEvery     = 2 * time.Second        //least time between two verbose echoes of the bytes of the input read
+)
+type mergeReporter func(MergeProgress)
+type readReporter func(ReadProgress)
+type readTracker struct {
+    fn       readReporter                                         //callback, nil if none
+    title    string                                               //title of the verbose echo, "" if none
+    progress ReadProgress
+    next     int64                                                //bytes read at which to check the time again
+    called   time.Time                                            //time of the last call of fn, or of the start of the phase
+    echoed   time.Time                                            //time of the last echo, or of the start of the phase
+    drawn    bool                                                 //progress echoed at least once
+    spoke    *int64                                               //time of the last report, for the heartbeat
+}
+type mergeTracker struct {
+    mutex    sync.Mutex                                           //held while fn is called, so one call at a time
+    fn       mergeReporter                                        //callback, nil if none
+    progress MergeProgress
+    spoke    *int64                                               //time of the last report, for the heartbeat
+}
+func (t *mergeTracker) begin(tasks int) {
+    //Starts the last pass, of a known number of tasks, and reports it
+    t.mutex.Lock()
+    defer t.mutex.Unlock()
+    t.progress = MergeProgress{Pass:t.progress.Pass + 1}
+    t.settleLocked(tasks, 1)
+} //end func begin
+func (t *mergeTracker) beginOpen() {
+    //Starts a pass whose tasks are enqueued as the runs are created, so that their number is unknown
+    t.mutex.Lock()
+    t.progress = MergeProgress{Pass:t.progress.Pass + 1}
+    t.mutex.Unlock()
+} //end func beginOpen
+func (t *mergeTracker) settle(tasks, filesAfter int) {
+    //Sets the number of tasks of the current pass, once known, and the passes expected, and reports them
+    t.mutex.Lock()
+    defer t.mutex.Unlock()
+    t.settleLocked(tasks, filesAfter)
+} //end func settle
+func (t *mergeTracker) settleLocked(tasks, filesAfter int) {
+    t.progress.Tasks, t.progress.Passes = tasks, t.progress.Pass
+    if filesAfter > 1 { t.progress.Passes++ }                     //the files left merged by one more pass
+    if t.fn != nil {
+        t.fn(t.progress)
+        stamp(t.spoke)
+    }
+} //end func settleLocked
+func (t *mergeTracker) done() string {
+    //Counts a completed task, reports it and returns the progress as text for the verbose echo
+    t.mutex.Lock()
+    t.progress.Task++
+    progress := t.progress
+    if t.fn != nil {
+        t.fn(progress)
+        stamp(t.spoke)
+    }
+    t.mutex.Unlock()
+    text := fmt.Sprintf("pass %d", progress.Pass)
+    if progress.Passes > 0 { text += fmt.Sprintf(" of %d", progress.Passes) }
+    text += fmt.Sprintf(", task %d", progress.Task)
+    if progress.Tasks > 0 { text += fmt.Sprintf(" of %d", progress.Tasks) }
+    return text
+} //end func done
+func (r *sortRun) trackRead(phase string, size int64) *readTracker {
+    //Starts tracking the bytes read by a phase of a size, returning nil if neither a callback nor verbose mode reports them
+    if r.onRead == nil && !r.verbose { return nil }
+    now := time.Now()
+    t   := &readTracker{fn:r.onRead, progress:ReadProgress{Phase:phase, Size:size}, called:now, echoed:now,
+                        spoke:&r.counters.spoke}
+    if r.verbose { t.title = "func Sort - " + phase + " (KB)" }
+    return t
+} //end func trackRead
+func (t *readTracker) advance(bytes int64) {
+    //Records the bytes read so far, reporting them if _progressEvery has elapsed since the last call of the callback, and
+    //echoing them if _echoEvery has elapsed since the last echo
+    if t == nil || bytes < t.next { return }
+    t.next = bytes + 64 << 10                                     //the clock read once per 64KB at most
+    now   := time.Now()
+    t.setBytes(bytes)
+    if t.fn != nil && now.Sub(t.called) >= _progressEvery {
+        t.called = now
+        t.fn(t.progress)
+        stamp(t.spoke)
+    }
+    if t.title != "" && now.Sub(t.echoed) >= _echoEvery {
+        t.echoed, t.drawn = now, true
+        t.echo()
+    }
+} //end func advance
+func (t *readTracker) finish(bytes int64) {
+    //Reports the bytes read by the phase in all, and echoes them if the phase was echoed before
+    if t == nil { return }
+    t.setBytes(bytes)
+    if t.fn != nil {
+        t.fn(t.progress)
+        stamp(t.spoke)
+    }
+    if t.drawn { t.echo() }
+} //end func finish
+func (t *readTracker) echo() {
+    //Echoes the kilobytes read out of those of the phase as a bar on a line of its own
+    fmt.Println(t.title + ": " + progressBar(kilobytes(t.progress.Bytes), kilobytes(t.progress.Size)))
+    stamp(t.spoke)
+} //end func echo
+func (t *readTracker) setBytes(bytes int64) {
+    //Sets the bytes read, at most the size, the last record of a byte range of GenerateRuns running past its end
+    if bytes > t.progress.Size { bytes = t.progress.Size }
+    t.progress.Bytes = bytes
+} //end func setBytes
+func kilobytes(bytes int64) int {
+    //Returns the kilobytes of a number of bytes, rounded up, at least 1
+    if bytes <= 0 { return 1 }
+    return int((bytes + 1023) >> 10)
+} //end func kilobytes
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file progress.go
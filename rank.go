@@ -0,0 +1,102 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     rank.go
+ * Overview:
+ *     annotation of the output records with their rank and percentile in the sort order.
+ * Functions:
+ *     WithRankColumn(sep string) Option
+ *         Option appending to each output record its 1-based rank.
+ *     WithRankTies(mode RankMode) Option
+ *         Option setting how the records with equal index fields are ranked.
+ *     WithPercentileColumn(precision int) Option
+ *         Option appending to each output record the percentage of the records ranked below it.
+ * Types:
+ *     RankMode
+ *         Ranking of the records with equal index fields.
+ * History:
+ *     v1.44.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "strconv"
+    "strings"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+type RankMode int
+const(
+    RankOrdinal     RankMode = iota //distinct ranks in output order, i.e. 1 2 3 4, the default
+    RankCompetition                 //ties sharing the rank of their first record, i.e. 1 2 2 4
+    RankDense                       //ties sharing a rank, without gaps, i.e. 1 2 2 3
+)
+
+func WithRankColumn(sep string) Option {
+/*         Purpose : Appends to each output record its 1-based rank in the sort order.
+ *       Arguments : sep = separator between the record and its rank, and between the rank and the percentile if any. If
+ *                         empty, the field separator is used.
+ *         Returns : The option.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : None.
+ *         Remarks : Only the sorted records are ranked, so the records removed by the filters, those outside the line
+ *                   range and the blank lines are not counted. The ranks follow any line number appended by
+ *                   WithOriginalLineNumbers. The records passed to the reducer of SortAndReduce are not ranked.
+ *         History : v1.44.0 - October 15, 2026 - Original release.
+ */
+    return func(s *Sorter) { s.rankCol, s.rankSep = true, sep }
+} //end func WithRankColumn
+//Sets how the records with equal index fields are ranked by WithRankColumn and WithPercentileColumn
+func WithRankTies(mode RankMode) Option { return func(s *Sorter) { s.rankTies = mode } }
+func WithPercentileColumn(precision int) Option {
+/*         Purpose : Appends to each output record the percentage of the records ranked below it.
+ *       Arguments : precision = number of decimals of the percentage. Must be at least 0.
+ *         Returns : The option.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : None.
+ *         Remarks : The percentile of the record of rank r among n sorted records is 100 * (n - r) / n, e.g. 99.9 for
+ *                   rank 1523 of 1.2M, so that the first record approaches 100 and the last one is 0. It is appended
+ *                   after the rank, if any, with the separator of WithRankColumn, or else with the field separator.
+ *                   With RankCompetition or RankDense, ties share the percentile of their first record; with
+ *                   RankOrdinal, each record has its own. The records counted are those WithRankColumn ranks.
+ *         History : v1.44.0 - October 15, 2026 - Original release.
+ */
+    return func(s *Sorter) { s.pctCol, s.pctPrec = true, precision }
+} //end func WithPercentileColumn
+//Private ----------------------------------------------------------------------------------------------------------------------
+type ranking struct {
+    total    int                     //number of records sorted
+    position int                     //position of the last record output
+    first    int                     //position of the first record of its tie group
+    dense    int                     //number of tie groups output
+    prevIdx  string                  //index part of the key of the last record output
+}
+func (r *sortRun) rankRecord(key, record string) string {
+    //Appends the rank and percentile of a record per its key, before its terminator, when requested
+    if !r.rankCol && !r.pctCol { return record }
+    rk     := &r.ranking
+    idx    := keyIndexPart(key)
+    rk.position++
+    if rk.position == 1 || idx != rk.prevIdx { rk.first, rk.dense = rk.position, rk.dense + 1 }
+    rk.prevIdx = idx
+    shared     := rk.first                                        //rank of the record's tie group
+    if r.rankTies == RankOrdinal { shared = rk.position }
+    sep        := r.rankSep
+    if sep == "" { sep = r.sep }
+    body       := strings.TrimRight(record, "\r\n")
+    columns    := ""
+    if r.rankCol {
+        rank := shared
+        if r.rankTies == RankDense { rank = rk.dense }
+        columns += sep + strconv.Itoa(rank)
+    }
+    if r.pctCol {
+        percentile := 100 * float64(rk.total - shared) / float64(rk.total)
+        columns    += sep + strconv.FormatFloat(percentile, 'f', r.pctPrec, 64)
+    }
+    return body + columns + record[len(body):]
+} //end func rankRecord
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file rank.go
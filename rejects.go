@@ -0,0 +1,96 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     rejects.go
+ * Overview:
+ *     copy of the records left out of a sort, i.e. blank, filtered out, malformed or replaced, to a reject file, so that no
+ *     input record vanishes unaccounted for.
+ * Functions:
+ *     WithRejectFile(path string, includeReason bool) Option
+ *         Option writing the records left out of each sort to a file.
+ * History:
+ *     v1.68.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "bufio"
+    "io"
+    "os"
+    "strings"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func WithRejectFile(path string, includeReason bool) Option {
+/*         Purpose : Writes the records left out of each sort to a file.
+ *       Arguments : path          = path of the reject file, created or truncated by each sort. "" for none, the default.
+ *                   includeReason = whether each record is preceded by the reason of its rejection and the field separator.
+ *         Returns : The option.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : None.
+ *         Remarks : The records left out are the blank ones, except outside the range of WithLineRange where all lines are
+ *                   copied to the output, those removed by a key filter, the malformed ones skipped per
+ *                   WithRecordErrorHandler, WithMaxErrors or WithMaxErrorRate, and, for MergeInto, the blank lines of the
+ *                   master file and the master records replaced per WithUpsert. Their reasons are "blank", "filtered",
+ *                   "replaced" and, for the malformed ones, their category: "missing field", "unparsed value", "overlong
+ *                   value", "derived value" or "bad value". Each record is written as read, continuation lines included, and
+ *                   followed by a line feed if it lacks one, so that the output and the reject file together hold every
+ *                   input record. Records of a group exceeding the limit of WithContinuationLines still fail the sort. A
+ *                   sorter sorting several inputs at once, e.g. through SortGlob, and the producers of GenerateRuns must
+ *                   each be given their own reject file.
+ *         History : v1.68.0 - October 15, 2026 - Original release.
+ */
+    return func(s *Sorter) { s.rejectPath, s.rejectReason = path, includeReason }
+} //end func WithRejectFile
+//Private ----------------------------------------------------------------------------------------------------------------------
+type rejectFile struct {
+    fh     *os.File                                               //the reject file
+    writer *bufio.Writer
+}
+func (r *sortRun) openRejects() func() {
+    //Creates the run's reject file, if requested and not yet open, and returns the function closing it
+    if r.rejectPath == "" || r.rejects != nil { return func() {} }
+    fh, err := os.Create(r.rejectPath)
+    if err != nil { halt("the reject file cannot be created - " + err.Error()) }
+    r.rejects = &rejectFile{fh:fh, writer:bufio.NewWriter(fh)}
+    return func() {
+        rejects  := r.rejects
+        r.rejects = nil
+        err      := rejects.writer.Flush()
+        if err == nil { err = rejects.fh.Sync() }
+        if errClose := rejects.fh.Close(); err == nil { err = errClose }
+        if err != nil { halt("the reject file cannot be written - " + err.Error()) }
+    }
+} //end func openRejects
+func (r *sortRun) reject(reason, record string) {
+    //Writes a record left out of the sort to the reject file, if any
+    if r.rejects == nil { return }
+    if r.rejectReason { record = reason + r.sep + record }
+    if !strings.HasSuffix(record, "\n") { record += "\n" }
+    if _, err := r.rejects.writer.WriteString(record); err != nil {
+        halt("the reject file cannot be written - " + err.Error())
+    }
+} //end func reject
+func (r *sortRun) resetRejects() {
+    //Empties the reject file, for a restart of the keying
+    if r.rejects == nil { return }
+    r.rejects.writer.Reset(r.rejects.fh)
+    if err := r.rejects.fh.Truncate(0); err != nil { halt("the reject file cannot be truncated - " + err.Error()) }
+    if _, err := r.rejects.fh.Seek(0, io.SeekStart); err != nil { halt("fh.Seek - " + err.Error()) }
+} //end func resetRejects
+func (r *sortRun) admits(record, trimmed string, lineNum int) bool {
+    //Returns whether a record read is to be keyed, rejecting it if blank or filtered out; the records outside the line
+    //range are copied to the output as they are
+    var reason string
+    switch {
+        case len(record) == 0 || !r.inRange(lineNum): return false    //end of the input, or record outside the range
+        case len(trimmed) == 0:                       reason = "blank"
+        case !r.passesFilters(trimmed):               reason = "filtered"
+        default:                                      return true
+    }
+    r.reject(reason, record)
+    return false
+} //end func admits
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file rejects.go
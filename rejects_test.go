@@ -0,0 +1,84 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     rejects_test.go
+ * Overview:
+ *     tests of the reject file, checking that the output and the rejects together reconstruct the input.
+ * Functions:
+ *     TestRejectsReconstructInput(t *testing.T)
+ *         Checks that the lines of the output and of the reject file are the multiset of the input lines.
+ * History:
+ *     v1.68.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "fmt"
+    "io/ioutil"
+    "math/rand"
+    "path/filepath"
+    "sort"
+    "strings"
+    "testing"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func TestRejectsReconstructInput(t *testing.T) {
+    //Random records among blank lines, filtered ones and malformed ones of each kind, the last lacking its line feed
+    rng     := rand.New(rand.NewSource(7))
+    reasons := map[string]int{}
+    var input strings.Builder
+    for k := 0; k < 3000; k++ {
+        record := randomRecord(rng)
+        switch rng.Intn(20) {
+            case 0: record, reasons["blank"]          = []string{"\n", "  \n", "\r\n"}[rng.Intn(3)], reasons["blank"] + 1
+            case 1: record, reasons["filtered"]       = "blocked\t" + record, reasons["filtered"] + 1
+            case 2: record, reasons["missing field"]  = "lone\n", reasons["missing field"] + 1
+            case 3: record, reasons["unparsed value"] = "word\tnan-ish\t1\n", reasons["unparsed value"] + 1
+            case 4: record, reasons["bad value"]      = "word\t7\t12x\n", reasons["bad value"] + 1
+        }
+        input.WriteString(record)
+    }
+    input.WriteString("last\t1\t2")
+    dir    := t.TempDir()
+    filter := filepath.Join(dir, "blocked.txt")
+    if err := ioutil.WriteFile(filter, []byte("blocked\n"), 0644); err != nil { t.Fatal(err) }
+    for _, includeReason := range []bool{false, true} {
+        for _, keysPerSort := range []int{100, 1000000} {
+            name    := fmt.Sprintf("reason=%v/keys=%d", includeReason, keysPerSort)
+            rejects := filepath.Join(dir, "rejects.txt")
+            var stats SortStats
+            output  := sortBytes(t, input.String(), WithFields("2,3"), WithFieldType(2, FieldNumeric),
+                                 WithZeroPadding(3, true), WithKeyFilterFile(filter, FilterBlock, 1),
+                                 WithRecordErrorHandler(func(int64, string, error) error { return nil }),
+                                 WithRejectFile(rejects, includeReason), WithKeysPerSort(keysPerSort), WithStats(&stats))
+            rejected, err := ioutil.ReadFile(rejects)
+            if err != nil { t.Fatal(err) }
+            //Lines of the rejects, counted by reason and stripped of it if included
+            lines := strings.SplitAfter(string(rejected), "\n")
+            lines  = lines[:len(lines) - 1]
+            if includeReason {
+                counts := map[string]int{}
+                for k, line := range lines {
+                    reason := line[:strings.Index(line, "\t")]
+                    counts[reason]++
+                    lines[k] = line[len(reason) + 1:]
+                }
+                if fmt.Sprint(counts) != fmt.Sprint(reasons) { t.Errorf("%s: reasons %v, expected %v", name, counts, reasons) }
+            }
+            expected := strings.SplitAfter(input.String() + "\n", "\n")
+            actual   := append(strings.SplitAfter(string(output), "\n"), lines...)
+            sort.Strings(expected)
+            sort.Strings(actual)
+            if strings.Join(actual, "") != strings.Join(expected, "") {
+                t.Errorf("%s: the output and the rejects do not reconstruct the input", name)
+            }
+            if stats.OutputRecords + len(lines) != len(expected) - 1 {
+                t.Errorf("%s: %d records output and %d rejected, for %d input lines", name, stats.OutputRecords, len(lines),
+                         len(expected) - 1)
+            }
+        }
+    }
+} //end func TestRejectsReconstructInput
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file rejects_test.go
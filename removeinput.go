@@ -0,0 +1,66 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     removeinput.go
+ * Overview:
+ *     removal of the input files once their sorted output is complete.
+ * Functions:
+ *     WithRemoveInput() Option
+ *         Option removing the input files after a successful sort.
+ * History:
+ *     v1.74.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "fmt"
+    "os"
+    "path/filepath"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func WithRemoveInput() Option {
+/*         Purpose : Removes the input files once their sorted output is complete.
+ *       Arguments : None.
+ *         Returns : The option.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : None.
+ *         Remarks : Applies to Sort, SortAndReduce, Run, SortWithKeys, MergeInto, MergeRuns and SortGlob. An input is
+ *                   removed last, after the output has been written, synced, closed and checked per WithVerifyOutput,
+ *                   and after the input itself has been closed; a sort that fails or is interrupted leaves it in place.
+ *                   MergeInto removes both the master and the delta files, and SortGlob removes its inputs only once
+ *                   every one of them has been sorted, none being removed if any sort fails. A sort whose output is
+ *                   one of its inputs fails before reading it, as do SortFS and RunFS, whose inputs are not on the OS
+ *                   file system. A failure to remove an input is returned as an error, the output being complete, or,
+ *                   for SortGlob, reported in the results of the inputs not removed. The other functions ignore the
+ *                   option.
+ *         History : v1.74.0 - October 15, 2026 - Original release.
+ */
+    return func(s *Sorter) { s.removeInput = true }
+} //end func WithRemoveInput
+//Private ----------------------------------------------------------------------------------------------------------------------
+func (r *sortRun) checkRemoval(outFile string, inFiles ...string) {
+    //Halts if the inputs are to be removed and one of them is the output
+    if !r.removeInput { return }
+    for _, inFile := range inFiles {
+        if sameFile(inFile, outFile) { halt("WithRemoveInput does not apply to a sort whose output is its input") }
+    }
+} //end func checkRemoval
+func (r *sortRun) removeInputs(inFiles ...string) {
+    //Removes the inputs of a successful sort if requested, halting on the first that cannot be removed
+    if !r.removeInput { return }
+    for _, inFile := range inFiles {
+        if err := os.Remove(inFile); err != nil { halt("os.Remove - " + err.Error()) }
+        if r.verbose { fmt.Println("func Sort - removed", inFile) }
+    }
+} //end func removeInputs
+func sameFile(a, b string) bool {
+    //Returns whether two paths name the same file, through links or otherwise
+    if filepath.Clean(a) == filepath.Clean(b) { return true }
+    fiA, errA := os.Stat(a)
+    fiB, errB := os.Stat(b)
+    return errA == nil && errB == nil && os.SameFile(fiA, fiB)
+} //end func sameFile
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file removeinput.go
@@ -0,0 +1,136 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     retry.go
+ * Overview:
+ *     retry of the transient I/O errors on the temporary and output files.
+ * Functions:
+ *     WithIORetry(attempts int, delay time.Duration) Option
+ *         Option retrying the transient I/O errors on the temporary and output files.
+ * Types:
+ *     IOError
+ *         I/O failure on a temporary or output file, with the offset at which it occurred.
+ * History:
+ *     v1.32.0 - October 15, 2026 - Original release.
+ *     v1.84.0 - October 15, 2026 - The transfers are drawn from the bucket of WithMaxIORate.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "errors"
+    "fmt"
+    "io"
+    "time"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+type IOError struct {
+    Op     string //failed operation: "read", "write" or "seek"
+    File   string //name of the file
+    Offset int64  //offset of the file at which the operation failed
+    Err    error  //last error encountered
+    temp   bool   //file on the temporary storage
+}
+
+func (e *IOError) Error() string {
+    return fmt.Sprintf("mergesort: %s %s at offset %d: %v", e.Op, e.File, e.Offset, e.Err)
+} //end func Error
+func (e *IOError) Unwrap() error { return e.Err }
+func (e *IOError) Is(target error) bool {
+    //Matches ErrTempSpace when a temporary file ran out of space
+    return target == ErrTempSpace && e.temp && isNoSpace(e.Err)
+} //end func Is
+func WithIORetry(attempts int, delay time.Duration) Option {
+/*         Purpose : Retries the transient I/O errors on the temporary and output files.
+ *       Arguments : attempts = maximum number of attempts of a read or write, the first included. 1 disables the retries.
+ *                   delay    = pause before the first retry, doubled before each of the following ones.
+ *         Returns : The option.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : None.
+ *         Remarks : The defaults are _defaultIOAttempts attempts and a delay of _defaultIODelay. EIO, ETIMEDOUT, EAGAIN,
+ *                   EINTR and errors reporting a timeout are transient. Before a retry, a file being read is reopened
+ *                   and one being written is sought back, both to the offset following the last byte transferred, so
+ *                   that the operation resumes where it failed. Other errors, and transient ones outlasting the
+ *                   attempts, end the sort with an *IOError naming the file and the offset.
+ *         History : v1.32.0 - October 15, 2026 - Original release.
+ */
+    return func(s *Sorter) { s.ioAttempts, s.ioDelay = attempts, delay }
+} //end func WithIORetry
+//Private ----------------------------------------------------------------------------------------------------------------------
+const(
+    _defaultIOAttempts = 3                                        //attempts of a read or write
+    _defaultIODelay    = 100 * time.Millisecond                   //pause before the first retry
+)
+type retryFile struct {
+    TempFile
+    reopen   func() (TempFile, error)                             //reopens the file, nil to reuse its handle
+    temp     bool                                                 //file on the temporary storage
+    offset   int64                                                //offset following the last byte transferred
+    attempts int
+    delay    time.Duration
+    throttle *ioThrottle                                          //bucket of the capped I/O rate, nil if uncapped
+}
+func (r *sortRun) retrying(fh TempFile, reopen func() (TempFile, error), temp bool) TempFile {
+    //Wraps a file so that its transient read & write errors are retried
+    return &retryFile{TempFile:fh, reopen:reopen, temp:temp, attempts:r.ioAttempts, delay:r.ioDelay, throttle:r.throttle}
+} //end func retrying
+func (r *sortRun) createOutput(file string) TempFile {
+    //Creates & locks an output file whose writes are retried
+    return r.retrying(r.lockOutput(file), nil, false)
+} //end func createOutput
+func (f *retryFile) Read(p []byte) (int, error) {
+    for attempt := 1; ; {
+        n, err   := f.TempFile.Read(p)
+        f.offset += int64(n)
+        f.throttle.pay(n)
+        if err == nil || err == io.EOF { return n, err }
+        if n > 0 { return n, nil }                                //the failure recurs on the next read
+        f.resume("read", &attempt, err)
+    }
+} //end func Read
+func (f *retryFile) Write(p []byte) (int, error) {
+    written := 0
+    for attempt := 1; ; {
+        n, err   := f.TempFile.Write(p[written:])
+        written  += n
+        f.offset += int64(n)
+        f.throttle.pay(n)
+        if err == nil { return written, nil }
+        f.resume("write", &attempt, err)
+    }
+} //end func Write
+func (f *retryFile) Seek(offset int64, whence int) (int64, error) {
+    pos, err := f.TempFile.Seek(offset, whence)
+    if err != nil { return pos, &IOError{Op:"seek", File:f.Name(), Offset:f.offset, Err:err, temp:f.temp} }
+    f.offset = pos
+    return pos, nil
+} //end func Seek
+func (f *retryFile) resume(op string, attempt *int, err error) {
+    //Waits & repositions the file for the next attempt, or halts if the error is not transient or the attempts are spent
+    for {
+        if !isTransient(err) || *attempt >= f.attempts {
+            panic(haltError{&IOError{Op:op, File:f.Name(), Offset:f.offset, Err:err, temp:f.temp}})
+        }
+        time.Sleep(f.delay << uint(*attempt - 1))
+        *attempt++
+        if f.reopen != nil {
+            fh, errOpen := f.reopen()
+            if errOpen != nil {
+                err = errOpen
+                continue
+            }
+            f.TempFile.Close()
+            f.TempFile = fh
+        }
+        if _, err = f.TempFile.Seek(f.offset, io.SeekStart); err == nil { return }
+    }
+} //end func resume
+func isTransient(err error) bool {
+    //Returns true if the I/O error may not recur
+    if isTransientErrno(err) { return true }
+    var timeout interface{ Timeout() bool }
+    return errors.As(err, &timeout) && timeout.Timeout()
+} //end func isTransient
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file retry.go
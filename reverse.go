@@ -0,0 +1,106 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     reverse.go
+ * Overview:
+ *     reversal of the physical order of the records of a text file, as done by tac.
+ * Functions:
+ *     Reverse(inFile, outFile string, opts ...Option) error
+ *         Copies a text file with its records in reverse order.
+ * History:
+ *     v1.15.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "bufio"
+    "fmt"
+    "io"
+    "strconv"
+    "time"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func Reverse(inFile, outFile string, opts ...Option) (err error) {
+/*         Purpose : Copies a text file with its records in reverse order.
+ *       Arguments : inFile  = path of the file with the records to be reversed.
+ *                   outFile = path of the file for the reversed records.
+ *                   opts    = options, see NewSorter. Only those for the temporary files, keysPerSort and verbose apply.
+ *         Returns : Any error encountered.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : catch, createTemp, halt, makeCompositeKeyFn, newSorter, openInput, openTemp, readString, removeTemp,
+ *                   useScratchDir, writeRecords
+ *         Remarks : A single pass records the offset of every record, blank ones included, in runs of keysPerSort keys
+ *                   spilled to temporary files. The runs are then gathered back to front into one key file, each being
+ *                   reversed in memory, and the records are copied in that order as Sort copies them. A last record
+ *                   lacking its terminator is given one.
+ *         History : v1.15.0 - October 15, 2026 - Original release.
+ */
+    var(
+        keys        = []string{}                              //offset keys of the current run
+        runs        = []string{}                              //key files, in physical order
+        recordStart int64                                     //record offset relative to the origin of the file
+        numKeys     int
+    )
+
+    defer catch(&err)
+    if inFile  == "" { halt("the input file was not specified") }
+    if outFile == "" { halt("the output file was not specified") }
+    run := newSorter(opts).newRun()
+    defer run.useScratchDir(outFile)()
+    if run.keysPerSort <= 0 { halt("the number of keys for in-place sorting was not specified") }
+    run.rankCol, run.pctCol = false, false                    //records without index fields to rank
+    defer func() {
+        for _, v := range runs { run.removeTemp(v) }
+    }()
+    fhIn, size := run.openInput(inFile)
+    defer fhIn.Close()
+    //Record the offsets of the records in runs
+    compositeKeyFn := makeCompositeKeyFn(run.keyFields(), nil, len(strconv.FormatInt(size, 10)))
+    readerIn       := bufio.NewReader(fhIn)
+    for errIn := error(nil); errIn != io.EOF; {
+        var record string
+        record, errIn = readString(readerIn)
+        if len(record) > 0 {
+            keys = append(keys, compositeKeyFn(record, recordStart, len(record)))
+            numKeys++
+        }
+        recordStart += int64(len(record))
+        if len(keys) > 0 && (len(keys) == run.keysPerSort || errIn == io.EOF) {
+            fhKeys, tempFile := run.createTemp(run.prefix)
+            runs              = append(runs, tempFile)
+            writer           := bufio.NewWriter(fhKeys)
+            for _, v := range keys { fmt.Fprintln(writer, v) }
+            if err := writer.Flush(); err != nil { haltTemp("writer.Flush", err) }
+            if err := fhKeys.Close(); err != nil { haltTemp("fhKeys.Close", err) }
+            keys = nil
+        }
+    }
+    if run.verbose { fmt.Println("func Reverse - recorded", numKeys, "offsets in", len(runs), "runs") }
+    //Gather the runs back to front, each reversed
+    fhKeys, keysFile := run.createTemp(run.prefix)
+    writer           := bufio.NewWriter(fhKeys)
+    for k := len(runs) - 1; k >= 0; k-- {
+        fhRun   := run.openTemp(runs[k])
+        scanner := bufio.NewScanner(fhRun)
+        for scanner.Scan() { keys = append(keys, scanner.Text()) }
+        fhRun.Close()
+        if err := scanner.Err(); err != nil { halt("scanner.Scan - " + err.Error()) }
+        for j := len(keys) - 1; j >= 0; j-- { fmt.Fprintln(writer, keys[j]) }
+        keys = nil
+        run.removeTemp(runs[k])
+        runs = runs[:k]
+    }
+    runs = append(runs, keysFile)
+    if err := writer.Flush(); err != nil { haltTemp("writer.Flush", err) }
+    if err := fhKeys.Close(); err != nil { haltTemp("fhKeys.Close", err) }
+    //Copy the records in reverse order
+    fhKeys = run.openTemp(keysFile)
+    defer fhKeys.Close()
+    run.writeRecords(fhIn, outFile, keysFile, bufio.NewScanner(fhKeys), numKeys, nil)
+    if run.verbose { fmt.Println("func Reverse - created", outFile, "in", time.Since(run.start)) }
+    return
+} //end func Reverse
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file reverse.go
@@ -0,0 +1,58 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     reverse_test.go
+ * Overview:
+ *     tests of the reversal of the records of a text file.
+ * Functions:
+ *     TestReverse(t *testing.T)
+ *         Checks the records reversed, blank and unterminated ones included, their offsets spilled in runs or not.
+ * History:
+ *     v1.15.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "fmt"
+    "io/ioutil"
+    "path/filepath"
+    "strings"
+    "testing"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func TestReverse(t *testing.T) {
+    var long strings.Builder                                        //25 records, their offsets spilled in runs of 3 keys
+    for k := 0; k < 25; k++ { fmt.Fprintf(&long, "record %d\n", k) }
+    cases := []struct {
+        input, expected string
+    }{
+        {"a\nb\nc\n", "c\nb\na\n"},
+        {"a\nb\nc", "c\nb\na\n"},                                   //last record lacking its terminator
+        {"a", "a\n"},
+        {"a\n\nb\n\n", "\nb\n\na\n"},                               //blank records kept
+        {long.String(), ""},
+        {long.String() + "last", ""},
+    }
+    for _, test := range cases {
+        expected := test.expected
+        if expected == "" {                                         //the records reversed line by line
+            lines := strings.SplitAfter(test.input, "\n")
+            if lines[len(lines) - 1] == "" { lines = lines[:len(lines) - 1] }
+            lines[len(lines) - 1] = strings.TrimSuffix(lines[len(lines) - 1], "\n") + "\n"
+            for k := len(lines) - 1; k >= 0; k-- { expected += lines[k] }
+        }
+        inFile  := writeInput(t, test.input)
+        outFile := filepath.Join(t.TempDir(), "out.txt")
+        tempDir := t.TempDir()
+        if err := Reverse(inFile, outFile, WithKeysPerSort(3), WithTempDir(tempDir)); err != nil { t.Fatal(err) }
+        output, err := ioutil.ReadFile(outFile)
+        if err != nil { t.Fatal(err) }
+        if string(output) != expected { t.Errorf("input %q: output %q, expected %q", test.input, output, expected) }
+        if left, err := ioutil.ReadDir(tempDir); err != nil || len(left) > 0 {
+            t.Errorf("input %q: %d temporary files left (%v)", test.input, len(left), err)
+        }
+    }
+} //end func TestReverse
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file reverse_test.go
@@ -0,0 +1,22 @@
+//go:build !aix && !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !solaris
+// +build !aix,!darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!solaris
+
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     rlimit_other.go
+ * Overview:
+ *     limit of the process on open files, on the systems not reporting it through getrlimit.
+ * History:
+ *     v1.31.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+//Private ----------------------------------------------------------------------------------------------------------------------
+func openFilesLimit() (uint64, bool) {
+    //Returns that the limit of the process on open files is unknown
+    return 0, false
+} //end func openFilesLimit
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file rlimit_other.go
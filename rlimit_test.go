@@ -0,0 +1,85 @@
+//go:build darwin || linux
+// +build darwin linux
+
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     rlimit_test.go
+ * Overview:
+ *     tests of sorts under an artificially low limit of the process on open files, on the systems listing the open
+ *     descriptors in /dev/fd.
+ * Functions:
+ *     TestLowOpenFilesLimit(t *testing.T)
+ *         Checks that sorts of many runs complete within a soft limit barely above the descriptors already open, and
+ *         release every descriptor counted.
+ * History:
+ *     v1.31.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "fmt"
+    "io/ioutil"
+    "math/rand"
+    "path/filepath"
+    "sync"
+    "syscall"
+    "testing"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func TestLowOpenFilesLimit(t *testing.T) {
+    input := randomInput(rand.New(rand.NewSource(4)), 5000)
+    dir   := t.TempDir()
+    inFile, outFile := filepath.Join(dir, "in.txt"), filepath.Join(dir, "out.txt")
+    if err := ioutil.WriteFile(inFile, []byte(input), 0644); err != nil { t.Fatal(err) }
+    opts := []Option{WithFields("2,1"), WithKeysPerSort(50), WithMergeFanIn(64), WithParallelism(4), WithTempDir(dir)}
+    //The cap of WithMaxOpenTempFiles, then the default cap derived from the lowered limit, each leaving room for the input,
+    //the output and a few descriptors of the runtime
+    for _, explicit := range []bool{true, false} {
+        t.Run(fmt.Sprintf("explicit=%v", explicit), func(t *testing.T) {
+            open := openDescriptors(t)
+            soft := open + 12
+            if !explicit {
+                soft = 2 * (open + 8)
+                //The shared cap derived anew from the lowered limit, then again from the restored one
+                _sharedLimiterOnce, _sharedLimiter = sync.Once{}, nil
+                defer func() { _sharedLimiterOnce, _sharedLimiter = sync.Once{}, nil }()
+            }
+            defer lowerOpenFilesLimit(t, soft)()
+            sorterOpts := opts
+            if explicit { sorterOpts = append(opts[:len(opts):len(opts)], WithMaxOpenTempFiles(4)) }
+            sorter, err := NewSorter(sorterOpts...)
+            if err != nil { t.Fatal(err) }
+            if err := sorter.Run(inFile, outFile); err != nil { t.Fatalf("soft limit %d, %d open: %v", soft, open, err) }
+            if !explicit && sorter.limiter.max != int(soft / 2) {
+                t.Errorf("default cap %d, expected half the soft limit %d", sorter.limiter.max, soft)
+            }
+            if open, held := sorter.limiter.open, sorter.limiter.held; open != 0 || held != 0 {
+                t.Errorf("%d temporary files still counted open and %d handles of anonymous files kept", open, held)
+            }
+            checkSorted(t, input, outFile, "2,1")
+        })
+    }
+} //end func TestLowOpenFilesLimit
+//Private ----------------------------------------------------------------------------------------------------------------------
+func openDescriptors(t *testing.T) uint64 {
+    //Returns the number of descriptors open in the process
+    entries, err := ioutil.ReadDir("/dev/fd")
+    if err != nil { t.Skip("the open descriptors cannot be listed - " + err.Error()) }
+    return uint64(len(entries))
+} //end func openDescriptors
+func lowerOpenFilesLimit(t *testing.T, soft uint64) func() {
+    //Lowers the soft limit of the process on open files, returning the function restoring it
+    var limit syscall.Rlimit
+    if err := syscall.Getrlimit(syscall.RLIMIT_NOFILE, &limit); err != nil { t.Skip("syscall.Getrlimit - " + err.Error()) }
+    if limit.Cur <= soft { t.Skipf("the soft limit %d is already below %d", limit.Cur, soft) }
+    lowered    := limit
+    lowered.Cur = soft
+    if err := syscall.Setrlimit(syscall.RLIMIT_NOFILE, &lowered); err != nil { t.Skip("syscall.Setrlimit - " + err.Error()) }
+    return func() {
+        if err := syscall.Setrlimit(syscall.RLIMIT_NOFILE, &limit); err != nil { t.Fatal("syscall.Setrlimit - " + err.Error()) }
+    }
+} //end func lowerOpenFilesLimit
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file rlimit_test.go
@@ -0,0 +1,27 @@
+//go:build aix || darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+// +build aix darwin dragonfly freebsd linux netbsd openbsd solaris
+
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     rlimit_unix.go
+ * Overview:
+ *     limit of the process on open files, on the systems reporting it through getrlimit.
+ * History:
+ *     v1.31.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "syscall"
+)
+//Private ----------------------------------------------------------------------------------------------------------------------
+func openFilesLimit() (uint64, bool) {
+    //Returns the soft limit of the process on open files, if known
+    var limit syscall.Rlimit
+    if err := syscall.Getrlimit(syscall.RLIMIT_NOFILE, &limit); err != nil { return 0, false }
+    return uint64(limit.Cur), true
+} //end func openFilesLimit
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file rlimit_unix.go
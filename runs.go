@@ -0,0 +1,377 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     runs.go
+ * Overview:
+ *     cooperative sort of a file by several processes, each keying a byte range of the input into a shared directory of
+ *     run files, which a last process merges and outputs.
+ * Functions:
+ *     GenerateRuns(inFile string, byteRange ByteRange, runDir string, opts ...Option) error
+ *         Sorts the keys of the records starting within a byte range of a file into a run file of a shared directory.
+ *     MergeRuns(runDir, inFile, outFile string, opts ...Option) error
+ *         Merges the run files of a shared directory covering a file and outputs its sorted records.
+ * Types:
+ *     ByteRange
+ *         Range of bytes of an input file.
+ * History:
+ *     v1.57.0 - October 15, 2026 - Original release.
+ *     v1.73.0 - October 15, 2026 - Manifests listed without globbing the run directory's path; renamed over on Windows.
+ *     v1.76.0 - October 15, 2026 - Manifests created 0600, as the run files.
+ *     v1.87.0 - October 15, 2026 - The run files merged the smallest first.
+ *     v1.95.0 - October 15, 2026 - The key generation of a byte range reported per WithReadProgress.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "bufio"
+    "encoding/json"
+    "fmt"
+    "hash/crc32"
+    "io"
+    "io/ioutil"
+    "os"
+    "path/filepath"
+    "runtime"
+    "sort"
+    "strconv"
+    "strings"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+type ByteRange struct {
+    Start int64 //offset of the first byte of the range
+    End   int64 //offset following the last byte of the range, -1 for the end of the input
+}
+
+func GenerateRuns(inFile string, byteRange ByteRange, runDir string, opts ...Option) (err error) {
+/*         Purpose : Sorts the keys of the records starting within a byte range of a file into a run file of a shared
+ *                   directory.
+ *       Arguments : inFile    = path of the file with the data to be sorted, the same for all the producers.
+ *                   byteRange = range of the input assigned to the producer. Must lie within the input.
+ *                   runDir    = path of the existing directory shared by the producers and the merger.
+ *                   opts      = options, see NewSorter. WithFields is mandatory.
+ *         Returns : Any error encountered.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : NewSorter, catch, generateRuns, halt
+ *         Remarks : The producer keys the records whose first byte lies within the range, a record straddling the start
+ *                   of the range belonging to the previous one, so that ranges cut anywhere share the records out
+ *                   exactly once. The field widths are measured over the whole input, so that the keys of all the
+ *                   producers compare alike. The keys are written to a run file of runDir, along with a manifest giving
+ *                   the range, the CRC-32 checksum of its bytes, the statistics and digest of its records and the
+ *                   options of the sort. The manifest is renamed into place last, so that a run file without one is
+ *                   ignored.
+ *                   WithLineRange, WithOriginalLineNumbers and WithRestartOnOverflow are not supported.
+ *         History : v1.57.0 - October 15, 2026 - Original release.
+ */
+    defer catch(&err)
+    if inFile == "" { halt("the input file was not specified") }
+    if runDir == "" { halt("the run directory was not specified") }
+    sorter, err := NewSorter(opts...)
+    if err != nil { panic(haltError{err}) }
+    if sorter.lineRange || sorter.lineNumbers || sorter.sampleRestart {
+        halt("WithLineRange, WithOriginalLineNumbers and WithRestartOnOverflow do not apply to a run generation")
+    }
+    sorter.verify = true                                          //digests recorded for a merger verifying its output
+    run          := sorter.newRun()
+    run.producing = true
+    run.generateRuns(inFile, byteRange, runDir)
+    return
+} //end func GenerateRuns
+func MergeRuns(runDir, inFile, outFile string, opts ...Option) (err error) {
+/*         Purpose : Merges the run files of a shared directory covering a file and outputs its sorted records.
+ *       Arguments : runDir  = path of the directory holding the run files and manifests written by GenerateRuns.
+ *                   inFile  = path of the file that the runs were generated from.
+ *                   outFile = path of the file for the sorted data.
+ *                   opts    = options, the same as the producers'. See NewSorter.
+ *         Returns : Any error encountered, ErrRunMismatch if the runs do not cover the input.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : NewSorter, catch, checkRemoval, halt, mergeRuns, preserveAttributes, removeInputs, useScratchDir
+ *         Remarks : The manifests of runDir must cover the input exactly once, their ranges abutting from its first byte
+ *                   to its last, and be written with the options given here and for an input of the same size and
+ *                   checksums; a gap, an overlap, an option mismatch or a changed input fails the merge before any
+ *                   output. The run files are then merged as Sort merges its own and left in place, the output matching
+ *                   that of Sort with the same options. The statistics sum those of the producers.
+ *                   WithLineRange and WithOriginalLineNumbers are not supported.
+ *         History : v1.57.0 - October 15, 2026 - Original release.
+ *                   v1.87.0 - October 15, 2026 - The run files merged the smallest first.
+ */
+    defer catch(&err)
+    if runDir  == "" { halt("the run directory was not specified") }
+    if inFile  == "" { halt("the input file was not specified") }
+    if outFile == "" { halt("the output file was not specified") }
+    sorter, err := NewSorter(opts...)
+    if err != nil { panic(haltError{err}) }
+    if sorter.lineRange || sorter.lineNumbers {
+        halt("WithLineRange and WithOriginalLineNumbers do not apply to a merge of runs")
+    }
+    run := sorter.newRun()
+    run.checkRemoval(outFile, inFile)
+    defer run.useScratchDir(outFile)()
+    run.mergeRuns(runDir, inFile, outFile)
+    run.preserveAttributes(inFile, outFile)
+    run.removeInputs(inFile)
+    return
+} //end func MergeRuns
+//Private ----------------------------------------------------------------------------------------------------------------------
+const(
+    _runsMagic    = "mergesort-runs-v1" //format of the manifests
+    _runsManifest = ".manifest"         //extension of the manifests
+)
+type runManifest struct {
+    Magic          string
+    InputSize      int64                  //size of the input
+    Start          int64                  //range of the input keyed by the producer
+    End            int64
+    Checksum       uint32                 //CRC-32 checksum of the bytes of the range
+    Keys           int                    //number of keys of the run file
+    InputRecords   int                    //statistics of the records of the range
+    InputDigest    uint64
+    InvalidValues  int
+    SkippedRecords int
+    FaultAt        []int                  //ordinals of the records skipped as malformed among those screened
+    FaultKinds     []string               //categories of the records skipped
+    Examples       []string               //first records skipped, located
+    Options        string                 //settings shaping the composite keys
+    Formats        []string               //formats of the index fields within the composite keys
+    RunFile        string                 //base name of the run file
+    path           string                 //path of the manifest, once read
+}
+type runStorage struct {
+    TempStorage
+    runs map[string]bool                  //paths of the run files, read in place and never removed
+}
+func (s runStorage) Open(name string) (TempFile, error) {
+    if s.runs[name] { return os.Open(name) }
+    return s.TempStorage.Open(name)
+} //end func Open
+func (s runStorage) Remove(name string) error {
+    if s.runs[name] { return nil }
+    return s.TempStorage.Remove(name)
+} //end func Remove
+func (s *Sorter) runOptions() string {
+    //Returns the settings that the producers of runs and their merger must share, i.e. those shaping the composite keys
+    var normalized, filters []string
+    var descending []bool
+    for colIdx := range s.normalizers { normalized = append(normalized, strconv.Itoa(colIdx + 1)) }
+    for _, key := range s.keys { descending = append(descending, key.Desc) }
+    sort.Strings(normalized)
+    for _, f := range s.filters { filters = append(filters, fmt.Sprintf("%s:%d:%d", f.path, f.mode, f.colIdx + 1)) }
+    return fmt.Sprintf("asc=%v fields=%v sep=%q collapse=%v/%v escape=%q trim=%v types=%v groups=%v decimals=%v " +
+                       "booleans=%v folds=%v normalized=%v keylens=%v nulls=%v zeropads=%v aligns=%v invalid=%v/%v " +
+                       "hash=%v/%d payload=%d filters=%v derived=%v desc=%v", s.sortAsc, s.colIdxs, s.sep, s.collapseSeps,
+                       s.leadingField, s.escape, s.trim, s.fieldTypes, s.groupings, s.decimals, s.boolTokens, s.folds,
+                       normalized, s.keyLens, s.nulls, s.zeroPads, s.aligns, s.invalidPlaced, s.invalidLast, s.hashOrder,
+                       s.hashSeed, s.payloadMax, filters, s.derive != nil, descending)
+} //end func runOptions
+func (r *sortRun) generateRuns(inFile string, byteRange ByteRange, runDir string) {
+    //Keys the records starting within the range, sorts the keys and writes them with their manifest to the run directory
+    r.resolveSeparator(inFile)
+    fhIn, size := r.openInput(inFile)
+    defer fhIn.Close()
+    if size == 0 { haltKind(ErrEmptyInput, "the input file is empty", nil) }
+    start, end := byteRange.Start, byteRange.End
+    if end < 0 { end = size }
+    if start < 0 || start > end || end > size {
+        halt(fmt.Sprintf("the byte range [%d, %d) is not within the %d bytes of the input", start, end, size))
+    }
+    if r.verbose { fmt.Println("func GenerateRuns - temporary directory =", r.tempDir) }
+    readerIn    := bufio.NewReader(fhIn)
+    scanned     := r.timePhase("width scan")
+    keySpecs, _ := r.scanFields(fhIn, readerIn, r.sampled)         //widths of the whole input, as for every producer
+    scanned(r.scanLen(size))
+    r.stats.InvalidValues = 0                                     //counted over the range only
+    manifest := runManifest{Magic:_runsMagic, InputSize:size, Start:start, End:end, Options:r.runOptions()}
+    for _, spec := range keySpecs { manifest.Formats = append(manifest.Formats, spec.FORMAT) }
+    defer r.openRejects()()
+    //Key the records starting within the range, checksumming its bytes
+    hash   := crc32.NewIEEE()
+    hashIn := func(chunk string, offset int64) {                  //adds the part of a chunk lying within the range
+        lo, hi := start - offset, end - offset
+        if lo < 0                 { lo = 0 }
+        if hi > int64(len(chunk)) { hi = int64(len(chunk)) }
+        if lo < hi { hash.Write([]byte(chunk[lo:hi])) }
+    }
+    maxCol         := r.lastIndexField()
+    compositeKeyFn := makeCompositeKeyFn(r.keyFields(), keySpecs, len(strconv.FormatInt(size, 10)))
+    sortedKeysFile := r.externalSort(func(emit func(key string)) {
+        var at RecordError                                        //record being keyed
+
+        defer blame(&at)
+        keyed    := r.timePhase("key generation")                 //run flushes included
+        progress := r.trackRead("key generation", end - start)
+        offset   := start
+        if start > 0 { offset-- }                                 //the previous byte tells whether a record starts here
+        if _, err := fhIn.Seek(offset, io.SeekStart); err != nil { halt("fhIn.Seek - " + err.Error()) }
+        readerIn.Reset(fhIn)
+        if start > 0 {
+            if prev, _ := readerIn.ReadByte(); prev != '\n' {     //record straddling the start: the previous range's
+                partial, _ := readString(readerIn)
+                hashIn(partial, start)
+                offset = start + int64(len(partial))
+            } else {
+                offset = start
+            }
+            skipped := r.skipContinuations(readerIn)              //lines of the previous range's last record
+            hashIn(skipped, offset)
+            offset  += int64(len(skipped))
+        }
+        for offset < end {
+            at             = RecordError{Offset:offset}
+            record, errIn := r.readRecord(readerIn)
+            if len(record) == 0 { break }
+            at.Record      = strings.TrimRight(record, "\r\n")
+            hashIn(record, offset)
+            trimmed := r.trimRecord(record)
+            if len(trimmed) > 0 { r.countInvalid(r.splitFields(trimmed, maxCol + 2)) }
+            if r.admits(record, trimmed, 0) {
+                if key, ok := r.keyRecord(compositeKeyFn, &at, record, trimmed); ok {
+                    emit(r.payloadKey(key, record))
+                    r.countInput(record)
+                    manifest.Keys++
+                }
+            }
+            offset += int64(len(record))
+            progress.advance(offset - start)
+            if errIn == io.EOF { break }
+        }
+        progress.finish(offset - start)
+        keyed(offset - start)
+    })
+    defer r.removeTemp(sortedKeysFile)
+    manifest.Checksum, manifest.InputRecords = hash.Sum32(), r.stats.InputRecords
+    manifest.InputDigest, manifest.InvalidValues = r.stats.InputDigest, r.stats.InvalidValues
+    manifest.SkippedRecords                      = r.stats.SkippedRecords
+    manifest.FaultAt, manifest.FaultKinds        = r.faults.at, r.faults.kinds
+    manifest.Examples                            = r.faults.examples
+    //Copy the run file to the run directory, then write its manifest
+    fhRun, err := ioutil.TempFile(runDir, fmt.Sprintf("run_%d-%d_*.keys", start, end))
+    if err != nil { halt("the run directory cannot hold the run files - " + err.Error()) }
+    defer fhRun.Close()
+    fhKeys := r.openTemp(sortedKeysFile)
+    defer fhKeys.Close()
+    if _, err := io.Copy(fhRun, fhKeys); err != nil { halt("io.Copy - " + err.Error()) }
+    if err := fhRun.Sync();  err != nil { halt("fhRun.Sync - " + err.Error()) }
+    if err := fhRun.Close(); err != nil { halt("fhRun.Close - " + err.Error()) }
+    manifest.RunFile = filepath.Base(fhRun.Name())
+    data, err       := json.MarshalIndent(manifest, "", "    ")
+    if err != nil { halt("json.MarshalIndent - " + err.Error()) }
+    manifestFile    := strings.TrimSuffix(fhRun.Name(), ".keys") + _runsManifest
+    if err := ioutil.WriteFile(manifestFile + ".tmp", data, 0600); err != nil { halt("ioutil.WriteFile - " + err.Error()) }
+    if err := replaceFile(manifestFile + ".tmp", manifestFile); err != nil { halt("replaceFile - " + err.Error()) }
+    if r.verbose {
+        fmt.Printf("func GenerateRuns - wrote %d keys of bytes [%d, %d) to %s\n", manifest.Keys, start, end, fhRun.Name())
+    }
+    r.publishStats()
+} //end func generateRuns
+func (r *sortRun) mergeRuns(runDir, inFile, outFile string) {
+    //Checks that the manifests of the run directory cover the input, merges their run files and outputs the records
+    r.resolveSeparator(inFile)
+    fhIn, size := r.openInput(inFile)
+    defer fhIn.Close()
+    if size == 0 { haltKind(ErrEmptyInput, "the input file is empty", nil) }
+    manifests := r.readManifests(runDir, size)
+    checkRunInput(fhIn, manifests)
+    r.replayFaults(manifests)
+    //Merge the run files, read in place, then output the records in the order of the keys
+    var runFiles []string
+    numKeys := 0
+    runs    := map[string]bool{}
+    for _, m := range manifests {
+        runFile := filepath.Join(runDir, m.RunFile)
+        runs[runFile], runFiles = true, append(runFiles, runFile)
+        numKeys                += m.Keys
+        r.stats.InputRecords   += m.InputRecords
+        r.stats.InputDigest    += m.InputDigest
+        r.stats.InvalidValues  += m.InvalidValues
+        r.stats.SkippedRecords += m.SkippedRecords
+    }
+    settings        := *r.Sorter                                  //private copy, the sorter being shared
+    settings.storage = runStorage{TempStorage:settings.storage, runs:runs}
+    r.Sorter         = &settings
+    if r.verbose { fmt.Println("func MergeRuns - merging", len(runFiles), "run files of", numKeys, "keys") }
+    sortedKeysFile  := r.mergeRunFiles(runFiles)
+    if r.freqOrder { sortedKeysFile = r.orderByFrequency(sortedKeysFile) }
+    defer r.removeTemp(sortedKeysFile)
+    fhKeys, scannerKeys := r.openKeys(sortedKeysFile)
+    defer fhKeys.Close()
+    r.writeRecords(fhIn, outFile, sortedKeysFile, scannerKeys, numKeys, nil)
+    r.checkOutput(nil)
+} //end func mergeRuns
+func (r *sortRun) readManifests(runDir string, size int64) []*runManifest {
+    //Returns the manifests of the run directory by range, halting unless they cover the input exactly once with the run's
+    //options
+    names, err := listDir(runDir, "", _runsManifest)
+    if err != nil { halt("listDir - " + err.Error()) }
+    if len(names) == 0 { haltKind(ErrRunMismatch, "no run manifest was found in " + runDir, nil) }
+    options   := r.runOptions()
+    manifests := make([]*runManifest, len(names))
+    for k, name := range names {
+        m := &runManifest{path:name}
+        data, err := ioutil.ReadFile(name)
+        if err == nil { err = json.Unmarshal(data, m) }
+        if err != nil || m.Magic != _runsMagic { haltKind(ErrRunMismatch, name + " is not a run manifest", err) }
+        switch {
+            case m.InputSize != size:
+                haltKind(ErrRunMismatch, fmt.Sprintf("%s was generated from an input of %d bytes, not %d", name,
+                                                     m.InputSize, size), nil)
+            case m.Options != options:
+                haltKind(ErrRunMismatch, name + " was generated with other options than those of the merge", nil)
+            case k > 0 && strings.Join(m.Formats, "\n") != strings.Join(manifests[0].Formats, "\n"):
+                haltKind(ErrRunMismatch, name + " has other field widths than " + manifests[0].path, nil)
+        }
+        if _, err := os.Stat(filepath.Join(runDir, m.RunFile)); err != nil {
+            haltKind(ErrRunMismatch, "the run file of " + name + " is missing", err)
+        }
+        manifests[k] = m
+    }
+    sort.Slice(manifests, func(i, j int) bool {
+        if manifests[i].Start != manifests[j].Start { return manifests[i].Start < manifests[j].Start }
+        return manifests[i].End < manifests[j].End
+    })
+    covered := int64(0)                                           //end of the bytes covered so far
+    for _, m := range manifests {
+        if m.Start > covered {
+            haltKind(ErrRunMismatch, fmt.Sprintf("the bytes [%d, %d) are not covered by any run", covered, m.Start), nil)
+        }
+        if m.Start < covered {
+            haltKind(ErrRunMismatch, fmt.Sprintf("the range [%d, %d) of %s overlaps another run", m.Start, m.End,
+                                                 m.path), nil)
+        }
+        covered = m.End
+    }
+    if covered < size {
+        haltKind(ErrRunMismatch, fmt.Sprintf("the bytes [%d, %d) are not covered by any run", covered, size), nil)
+    }
+    return manifests
+} //end func readManifests
+func checkRunInput(fhIn io.ReadSeeker, manifests []*runManifest) {
+    //Halts unless the checksum of each range of the input matches that of its manifest
+    if _, err := fhIn.Seek(0, io.SeekStart); err != nil { halt("fhIn.Seek - " + err.Error()) }
+    reader := bufio.NewReader(fhIn)
+    for _, m := range manifests {
+        hash := crc32.NewIEEE()
+        if _, err := io.CopyN(hash, reader, m.End - m.Start); err != nil { halt("io.CopyN - " + err.Error()) }
+        if hash.Sum32() != m.Checksum {
+            haltKind(ErrRunMismatch, fmt.Sprintf("the bytes [%d, %d) of the input changed since %s was written", m.Start,
+                                                 m.End, m.path), nil)
+        }
+    }
+} //end func checkRunInput
+func (r *sortRun) mergeRunFiles(runFiles []string) string {
+    //Merges the run files into a temporary file, the smallest files first, returning its name
+    if len(runFiles) == 1 { return runFiles[0] }
+    r.progress = &mergeTracker{fn:r.onMerge, spoke:&r.counters.spoke}
+    return r.mergeSmallestFirst(runFiles, "MergeRuns", false)
+} //end func mergeRunFiles
+func replaceFile(oldPath, newPath string) error {
+    //Renames a file over another. Windows refusing to replace a file that is open, e.g. by a scanner, or read-only, the
+    //rename is retried there once the other file is removed.
+    err := os.Rename(oldPath, newPath)
+    if err == nil || runtime.GOOS != "windows" { return err }
+    if os.Remove(newPath) != nil { return err }
+    return os.Rename(oldPath, newPath)
+} //end func replaceFile
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file runs.go
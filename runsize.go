@@ -0,0 +1,93 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     runsize.go
+ * Overview:
+ *     adaptive size of the initial runs of a sort: from keysPerSort, the runs grow between flushes while a budget or the
+ *     memory free allows, and shrink back as it tightens.
+ * Functions:
+ *     WithAdaptiveRunSize(budget int64) Option
+ *         Option growing the initial runs past keysPerSort while memory allows.
+ * History:
+ *     v1.89.0 - October 15, 2026 - Original release.
+ *     v1.93.0 - October 15, 2026 - The fields of /proc/meminfo are read by memInfo.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "fmt"
+    "io/ioutil"
+    "math"
+    "runtime"
+    "runtime/debug"
+    "strconv"
+    "strings"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func WithAdaptiveRunSize(budget int64) Option {
+/*         Purpose : Grows the initial runs of the sorts past keysPerSort while memory allows.
+ *       Arguments : budget = the most bytes the keys of a run may take in memory, or 0 for half the memory free.
+ *         Returns : The option.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : None.
+ *         Remarks : The first run holds keysPerSort keys. After each flush, the bytes the keys of the run took, with their
+ *                   string headers and the spare capacity of their slice, give the bytes per key, and the next run holds as
+ *                   many keys as the allowance fits: the budget, or, for 0, the bytes of the run flushed plus half the
+ *                   memory free, i.e. the Go memory limit set by debug.SetMemoryLimit or GOMEMLIMIT less the memory held by
+ *                   the runtime, else the MemAvailable of /proc/meminfo; without either, the runs keep their size. A run at
+ *                   most doubles the size of the one before, so that a wrong reading costs little, and never falls below
+ *                   keysPerSort, which thus remains the floor: as the memory free or the bytes per key change, the runs
+ *                   shrink back towards it. Go reporting no failed allocation, the sort cannot back off from one, only from
+ *                   the memory free falling before it. The keys of each run are listed in SortStats.RunSizes, and the
+ *                   verbose echo reports each change of size. Fewer, larger runs mean fewer merges; Estimate still plans
+ *                   runs of keysPerSort, an upper bound on their number. With a budget, the sizes depend on the keys only,
+ *                   as WithDeterministic requires. The runs of Reverse and the spills of a SpillQueue keep keysPerSort keys.
+ *         History : v1.89.0 - October 15, 2026 - Original release.
+ */
+    return func(s *Sorter) { s.adaptiveRuns, s.runBudget = true, budget }
+} //end func WithAdaptiveRunSize
+//Private ----------------------------------------------------------------------------------------------------------------------
+func (r *sortRun) resizeRun(keys []string) {
+    //Records the size of a run flushed and, if adaptive, sizes the next one after the bytes its keys take
+    r.stats.RunSizes = append(r.stats.RunSizes, len(keys))
+    if !r.adaptiveRuns || len(keys) == 0 { return }
+    bytes := int64(cap(keys)) * 16                                //string headers of the slice
+    for _, key := range keys { bytes += int64(len(key)) }
+    allowance := r.runBudget
+    if allowance == 0 {
+        free, ok := memoryFree()
+        if !ok { return }
+        allowance = bytes + free / 2
+    }
+    size := int(math.Min(float64(allowance) / (float64(bytes) / float64(len(keys))), float64(2 * r.runSize)))
+    if size < r.keysPerSort { size = r.keysPerSort }
+    if size != r.runSize && r.verbose { fmt.Println("func Sort - next runs of", size, "keys") }
+    r.runSize = size
+} //end func resizeRun
+func memoryFree() (int64, bool) {
+    //Returns the bytes the process may still allocate: the Go memory limit less the memory held by the runtime, else the
+    //memory available per /proc/meminfo; false if neither is known
+    if limit := debug.SetMemoryLimit(-1); limit < math.MaxInt64 {
+        var stats runtime.MemStats
+        runtime.ReadMemStats(&stats)
+        held := int64(stats.Sys - stats.HeapReleased)
+        if held > limit { return 0, true }
+        return limit - held, true
+    }
+    return memInfo("MemAvailable:")
+} //end func memoryFree
+func memInfo(field string) (int64, bool) {
+    //Returns the bytes of a field of /proc/meminfo, e.g. "MemTotal:", false if it cannot be read
+    data, err := ioutil.ReadFile("/proc/meminfo")
+    if err != nil { return 0, false }
+    for _, line := range strings.Split(string(data), "\n") {
+        if fields := strings.Fields(line); len(fields) >= 2 && fields[0] == field {
+            if kB, err := strconv.ParseInt(fields[1], 10, 64); err == nil { return kB * 1024, true }
+        }
+    }
+    return 0, false
+} //end func memInfo
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file runsize.go
@@ -0,0 +1,60 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     scratch.go
+ * Overview:
+ *     placement of the temporary files in a scratch directory next to the output file.
+ * Functions:
+ *     WithTempNextToOutput() Option
+ *         Option placing the temporary files in a hidden subdirectory of the output file's directory.
+ * History:
+ *     v1.30.0 - October 15, 2026 - Original release.
+ *     v1.76.0 - October 15, 2026 - Scratch storage encrypted per WithTempEncryption.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "fmt"
+    "io/ioutil"
+    "os"
+    "path/filepath"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func WithTempNextToOutput() Option {
+/*         Purpose : Places the temporary files in a hidden subdirectory of the output file's directory.
+ *       Arguments : None.
+ *         Returns : The option.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : None.
+ *         Remarks : Each run creates its own subdirectory, named ".mergesort_" followed by a random suffix, and removes it
+ *                   with its content when it ends, failed or not. The temporary files thus share the volume of the
+ *                   output rather than that of the OS temporary directory. The option takes precedence over WithTempDir
+ *                   and WithTempStorage, while WithInMemorySpillThreshold still applies. A run whose output directory
+ *                   is not writable fails before reading its input. SortChan and the SpillQueue, which have no output
+ *                   file, keep the sorter's temporary storage.
+ *         History : v1.30.0 - October 15, 2026 - Original release.
+ */
+    return func(s *Sorter) { s.tempNextToOut = true }
+} //end func WithTempNextToOutput
+//Private ----------------------------------------------------------------------------------------------------------------------
+const _scratchPrefix = ".mergesort_" //prefix of the scratch directories next to the outputs
+func (r *sortRun) useScratchDir(outFile string) func() {
+    //Redirects the run's temporary files to a new scratch directory next to the output, returning the function removing it
+    if !r.tempNextToOut { return func() {} }
+    outDir, err := filepath.Abs(filepath.Dir(outFile))
+    if err != nil { halt("filepath.Abs - " + err.Error()) }
+    dir, err := ioutil.TempDir(outDir, _scratchPrefix)
+    if err != nil { halt(fmt.Sprintf("the output directory %s cannot hold the temporary files - %s", outDir, err.Error())) }
+    settings        := *r.Sorter                                  //private copy, the sorter being shared
+    settings.tempDir = dir
+    settings.storage = newOSStorage(dir, settings.limiter)
+    if settings.encrypt       { settings.storage = newCipherStorage(settings.storage) }
+    if settings.memBudget > 0 { settings.storage = newTieredStorage(settings.storage, settings.memBudget) }
+    r.Sorter = &settings
+    if r.verbose { fmt.Println("func Sort - created scratch directory", dir) }
+    return func() { os.RemoveAll(dir) }
+} //end func useScratchDir
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file scratch.go
@@ -0,0 +1,127 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     separators.go
+ * Overview:
+ *     splitting of the records into fields, with runs of separators optionally counting as one and escaped separators
+ *     optionally belonging to their fields.
+ * Functions:
+ *     WithCollapseSeparators() Option
+ *         Option treating each run of field separators as a single field boundary.
+ *     WithLeadingSeparatorField() Option
+ *         Option having leading separators open an empty first field when they are collapsed.
+ *     WithEscapedSeparators(escape rune) Option
+ *         Option not splitting the records on the separators preceded by an escape character.
+ * History:
+ *     v1.48.0 - October 15, 2026 - Original release.
+ *     v1.49.0 - October 15, 2026 - Added WithEscapedSeparators.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "strings"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func WithCollapseSeparators() Option {
+/*         Purpose : Treats each run of field separators as a single field boundary, as in hand-aligned files.
+ *       Arguments : None.
+ *         Returns : The option.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : None.
+ *         Remarks : The fields are extracted so for the width scan, the composite keys and the filters alike. Leading
+ *                   separators are skipped, so that the first field is the first value of the record, unless
+ *                   WithLeadingSeparatorField is given. Trailing separators end the record with one empty field. The
+ *                   records are output unchanged.
+ *         History : v1.48.0 - October 15, 2026 - Original release.
+ */
+    return func(s *Sorter) { s.collapseSeps = true }
+} //end func WithCollapseSeparators
+//Has the leading separators of a record open an empty first field when WithCollapseSeparators is given
+func WithLeadingSeparatorField() Option { return func(s *Sorter) { s.leadingField = true } }
+func WithEscapedSeparators(escape rune) Option {
+/*         Purpose : Does not split the records on the separators preceded by an escape character, e.g. a backslash.
+ *       Arguments : escape = the escape character.
+ *         Returns : The option.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : None.
+ *         Remarks : Within a field, the escape character followed by the separator stands for the separator, and doubled
+ *                   for itself, e.g. a backslash then a tab for a tab within a value and two backslashes for one.
+ *                   Followed by any other character, or ending the record, it stands for itself, so a field ending in
+ *                   an escape character must have it doubled when a separator follows. The fields are unescaped before
+ *                   they are measured by the width scan, keyed or filtered. The records are output unchanged.
+ *         History : v1.49.0 - October 15, 2026 - Original release.
+ */
+    return func(s *Sorter) { s.escape = string(escape) }
+} //end func WithEscapedSeparators
+//Private ----------------------------------------------------------------------------------------------------------------------
+func (s *Sorter) splitFields(record string, n int) []string {
+    //Splits a trimmed record into fields as strings.SplitN does, n < 0 returning all of them, the fields being unescaped
+    if !s.collapseSeps && s.escape == "" { return strings.SplitN(record, s.sep, n) }
+    fields := []string{}
+    if s.collapseSeps && strings.HasPrefix(record, s.sep) {
+        if s.leadingField { fields = append(fields, "") }
+        record = skipSeparators(record, s.sep)
+    }
+    for n < 0 || len(fields) < n - 1 {
+        k := s.indexSeparator(record)
+        if k < 0 { break }
+        fields = append(fields, s.unescape(record[:k]))
+        record = record[k + len(s.sep):]
+        if s.collapseSeps { record = skipSeparators(record, s.sep) }
+    }
+    return append(fields, s.unescape(record))
+} //end func splitFields
+func (s *Sorter) indexSeparator(record string) int {
+    //Returns the offset of the first separator of a record that is not escaped, or -1
+    if s.escape == "" { return strings.Index(record, s.sep) }
+    for k := 0; k < len(record); k++ {
+        switch {
+            case strings.HasPrefix(record[k:], s.escape):
+                k += len(s.escape) - 1
+                if next := record[k + 1:]; strings.HasPrefix(next, s.sep) {
+                    k += len(s.sep)
+                } else if strings.HasPrefix(next, s.escape) {
+                    k += len(s.escape)
+                }
+            case strings.HasPrefix(record[k:], s.sep):
+                return k
+        }
+    }
+    return -1
+} //end func indexSeparator
+func (s *Sorter) unescape(value string) string {
+    //Replaces the escaped separators and escape characters of a field by the characters themselves; an escape character
+    //followed by any other one, or ending the field, stands for itself
+    if s.escape == "" || !strings.Contains(value, s.escape) { return value }
+    var unescaped strings.Builder
+    for len(value) > 0 {
+        if !strings.HasPrefix(value, s.escape) {
+            unescaped.WriteByte(value[0])
+            value = value[1:]
+            continue
+        }
+        value = value[len(s.escape):]
+        switch {
+            case strings.HasPrefix(value, s.sep):    unescaped.WriteString(s.sep);    value = value[len(s.sep):]
+            case strings.HasPrefix(value, s.escape): unescaped.WriteString(s.escape); value = value[len(s.escape):]
+            default:                                 unescaped.WriteString(s.escape)
+        }
+    }
+    return unescaped.String()
+} //end func unescape
+func (s *Sorter) keyFields() func(record string) []string {
+    //Returns the splitter of the trimmed records for their composite keys, which goes no further than the last index field
+    //unless a derived key needs them all
+    n := s.lastIndexField() + 2
+    if s.derive != nil { n = -1 }
+    return func(record string) []string { return s.splitFields(record, n) }
+} //end func keyFields
+func skipSeparators(record, sep string) string {
+    for strings.HasPrefix(record, sep) { record = record[len(sep):] }
+    return record
+} //end func skipSeparators
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file separators.go
@@ -0,0 +1,51 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     separators_test.go
+ * Overview:
+ *     tests of the splitting of the records on the separators that are not escaped.
+ * Functions:
+ *     TestEscapedSeparators(t *testing.T)
+ *         Checks the fields split and unescaped per WithEscapedSeparators, and a sort keyed on escaped values.
+ * History:
+ *     v1.49.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "fmt"
+    "testing"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func TestEscapedSeparators(t *testing.T) {
+    //Trimmed records split by a tab, escaped by a backslash
+    sorter, err := NewSorter(WithFields("1"), WithSeparator("\t"), WithEscapedSeparators('\\'))
+    if err != nil { t.Fatal(err) }
+    cases := []struct {
+        record string
+        fields []string
+    }{
+        {"a\\\tb\tc", []string{"a\tb", "c"}},                       //escaped separator within a field
+        {"a\\\\\tb", []string{"a\\", "b"}},                         //escaped escape ending a field
+        {"a\\\\\\\tb", []string{"a\\\tb"}},                         //escaped escape then escaped separator
+        {"a\\b\tc", []string{"a\\b", "c"}},                         //escape followed by another character
+        {"a\tb\\", []string{"a", "b\\"}},                           //lone escape ending the record
+        {"\\", []string{"\\"}},                                     //lone escape as the whole record
+        {"a\\\t", []string{"a\t"}},                                 //escaped separator ending the record
+    }
+    for _, test := range cases {
+        if fields := sorter.splitFields(test.record, -1); fmt.Sprintf("%q", fields) != fmt.Sprintf("%q", test.fields) {
+            t.Errorf("record %q split into %q, expected %q", test.record, fields, test.fields)
+        }
+    }
+    //Keys on escaped values, the last record ending in a lone escape without a final line feed; the records are output
+    //unchanged
+    input  := "k\\\tz\t1\n" + "kk\\\\\t2\n" + "k\\\tb\t3\\\n" + "k\\\ta\tx\\"
+    output := sortBytes(t, input, WithFields("1"), WithSeparator("\t"), WithEscapedSeparators('\\'))
+    if expected := "k\\\ta\tx\\\n" + "k\\\tb\t3\\\n" + "k\\\tz\t1\n" + "kk\\\\\t2\n"; string(output) != expected {
+        t.Errorf("output %q, expected %q", output, expected)
+    }
+} //end func TestEscapedSeparators
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file separators_test.go
@@ -0,0 +1,370 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     sorter.go
+ * Overview:
+ *     reusable sorter configured once through functional options.
+ * Functions:
+ *     NewSorter(opts ...Option) (*Sorter, error)
+ *         Creates a sorter from the given options.
+ *     (*Sorter) Run(inFile, outFile string) error
+ *         Sorts a text file with the sorter's settings.
+ *     (*Sorter) RunFS(fsys fs.FS, name, outFile string) error
+ *         Sorts a text file read from a file system with the sorter's settings.
+ *     WithAscending(sortAsc bool) Option
+ *     WithFields(usingFields string) Option
+ *     WithKeysPerSort(keysPerSort int) Option
+ *     WithMergeFanIn(fanIn int) Option
+ *     WithSeparator(sep string) Option
+ *     WithTempDir(dir string) Option
+ *     WithVerbose(verbose bool) Option
+ *         Options for NewSorter and the Sort family of functions.
+ * Types:
+ *     Option
+ *         Function setting one of a sorter's parameters.
+ *     Sorter
+ *         Sort settings reusable across many files, and concurrently.
+ * History:
+ *     v1.8.0 - October 15, 2026 - Original release.
+ *     v1.10.0 - October 15, 2026 - Added RunFS.
+ *     v1.29.0 - October 15, 2026 - Added WithMergeFanIn.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "context"
+    "fmt"
+    "io"
+    "io/fs"
+    "os"
+    "regexp"
+    "sync/atomic"
+    "time"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+type Option func(*Sorter)
+
+type Sorter struct {
+    sortAsc       bool                        //ascending sort if true, descending otherwise
+    usingFields   string                      //CSV of the index field numbers, parsed into keys
+    keys          []KeySpec                   //specs of the index fields
+    colIdxs       []int                       //parsed index field numbers, 0-based
+    sep           string                      //field separator
+    collapseSeps  bool                        //runs of separators counting as one field boundary
+    leadingField  bool                        //leading separators opening an empty field when collapsed
+    escape        string                      //escape character of the separators within fields, "" if none
+    keysPerSort   int                         //number of keys per initial run
+    adaptiveRuns  bool                        //initial runs grown past keysPerSort while memory allows
+    runBudget     int64                       //most bytes of the keys of an adaptive run, 0 for half the memory free
+    maxBuckets    int                         //most distinct values of the index fields of a run sorted by buckets
+    packRuns      int                         //most initial runs appended to a spill file, below 2 for a file per run
+    pressure      float64                     //fraction of the memory limit flushing a run early, 0 for never
+    fanIn         int                         //number of key files merged by each merge task
+    maxOpen       int                         //cap on the open temporary files, 0 for the shared one
+    limiter       *fileLimiter                //counter of the open temporary files
+    ioAttempts    int                         //attempts of a read or write of the temporary & output files
+    ioRate        int64                       //cap on the bytes read & written per second, 0 for none
+    throttle      *ioThrottle                 //bucket of the capped I/O rate, nil if uncapped
+    lowPriority   bool                        //sorts giving way to the other work of the system
+    continuous    bool                        //key files merged continuously by a pool rather than in passes
+    maxLiveRuns   int                         //cap on the key files present while the runs are written, 0 for none
+    ioDelay       time.Duration               //pause before the first retry of a transient I/O error
+    lockWait      time.Duration               //longest wait for the lock of a busy output file
+    parallelism   int                         //number of goroutines working at once on a sort
+    mmapInput     bool                        //input mapped into memory for the output phase
+    payloadMax    int                         //longest record embedded in its composite key, 0 for none
+    deterministic bool                        //sequential temporary file names & merges in a fixed order
+    tempDir       string                      //directory of the temporary files
+    tempDirs      []TempDir                   //directories of the temporary files, in order of preference, if several
+    tempPlacement TempPlacement               //placement of the temporary files over tempDirs
+    tempNextToOut bool                        //temporary files in a scratch directory next to each output
+    storage       TempStorage                 //storage of the temporary files
+    memBudget     int64                       //number of bytes of temporary files that may be held in memory
+    maxTemp       int64                       //cap on the bytes of the temporary files present at once, 0 for none
+    codec         Codec                       //compression of the composite-key files, nil if none
+    encrypt       bool                        //temporary files encrypted with an ephemeral key
+    sampled       bool                        //field widths estimated from a sample of the input
+    sampleHead    int64                       //number of bytes at the start of the input sampled whole
+    sampleBlocks  int                         //number of blocks sampled past the head
+    sampleMargin  int                         //bytes added to each sampled width
+    sampleRestart bool                        //restart with exact widths on a value exceeding its sampled width
+    hashOrder     bool                        //ordering on keyed digests of the index fields
+    hashSeed      uint64                      //key of the digests
+    verify        bool                        //check that the output is a permutation of the input
+    rejectPath    string                      //file of the records left out of a sort, "" if none
+    rejectReason  bool                        //reasons of the rejections prepended to the rejected records
+    onRecordError recordHandler               //handler of the malformed records, nil to fail the sort on them
+    errorCap      bool                        //abort once more than maxErrors records are malformed
+    maxErrors     int                         //number of malformed records tolerated
+    rateCap       bool                        //abort once the fraction of malformed records exceeds errorRate
+    errorRate     float64                     //fraction of malformed records tolerated
+    rateSample    int                         //number of records read before errorRate applies
+    statsOut      *SortStats                  //destination of the statistics of the last run
+    traceCtx      context.Context             //context of the trace regions & profiler labels, nil for none
+    jobCtx        context.Context             //context cancelling the sorts of a Pool, nil for none
+    poolSpace     *tempSpace                  //temporary space shared by the sorts of a Pool, nil for none
+    lineNumbers   bool                        //tagging of the output records with their input line numbers
+    lineNumPre    bool                        //number placed before the record rather than after it
+    lineNumSep    string                      //separator between the number and the record
+    lineRange     bool                        //sorting of a range of input lines only
+    lineFrom      int64                       //first line of the range, 1-based
+    lineTo        int64                       //last line of the range, inclusive
+    rankCol       bool                        //annotation of the output records with their rank
+    rankSep       string                      //separator of the rank & percentile columns
+    rankTies      RankMode                    //ranking of the records with equal index fields
+    expectUnique  bool                        //check that the index fields of the output records are unique
+    uniqueMode    UniqueMode                  //outcome of the duplicates found by the check
+    dedupPolicy   DedupPolicy                 //record kept of each group of equal index fields
+    dupsPath      string                      //file of the records suppressed by the deduplication, "" if none
+    pctCol        bool                        //annotation of the output records with their percentile
+    pctPrec       int                         //number of decimals of the percentiles
+    fieldTypes    map[int]FieldType           //types of the index fields other than text, by 0-based column
+    invalidPlaced bool                        //placement of the typed values that do not parse requested
+    invalidLast   bool                        //values that do not parse placed at the end of the output
+    folds         map[int]bool                //folded index fields, by 0-based column, with their stripping of diacritics
+    normalizers   map[int]func(string) string //transformations of index fields, by 0-based column
+    keyLens       map[int]int                 //numbers of characters of index fields that are compared, by 0-based column
+    groupings     map[int]Grouping            //thousands separators of numeric index fields, by 0-based column
+    decimals      map[int]rune                //decimal separators of numeric index fields, by 0-based column
+    zeroPads      map[int]bool                //zero-padded index fields, true if strict, by 0-based column
+    aligns        map[int]Alignment           //alignments of text index fields, by 0-based column
+    derive        keyDeriver                  //computation of a key from the fields, ahead of the index fields, or nil
+    boolTokens    map[int]map[string]string   //encodings of the lowercased tokens of boolean index fields, by 0-based column
+    trim          TrimMode                    //trimming of the records before they are split into fields
+    grouped       bool                        //continuation lines joined to the record before them
+    startsRecord  *regexp.Regexp              //pattern of the lines starting a record, nil for those not indented
+    maxGroupLen   int                         //longest record with its continuation lines, 0 for the default
+    nulls         map[int]bool                //index fields whose empty values are placed, by 0-based column, true if last
+    filters       []*keyFilter                //filters of the records on listed field values
+    freqOrder     bool                        //groups of equal index fields output by decreasing size
+    upsert        bool                        //delta records of MergeInto replacing the equal master ones
+    joinColumns   string                      //CSV of the output columns of Join, "" for all the fields of both sides
+    joinSep       string                      //output separator of Join, "" for the separator of its inputs
+    onMerge       mergeReporter               //callback of the completed merge tasks, nil if none
+    onRead        readReporter                //callback of the bytes read by the width scan & key generation, nil if none
+    heartbeat     time.Duration               //longest silence of a sort before a heartbeat line is logged, 0 for none
+    removeInput   bool                        //input files removed once their output is complete
+    preserveAttrs bool                        //mode bits, modification time & owner of the input copied to the output
+    verbose       bool                        //echo of the main execution stages to Stdout
+}
+
+func NewSorter(opts ...Option) (s *Sorter, err error) {
+/*         Purpose : Creates a sorter from the given options.
+ *       Arguments : opts = the options, applied in order. WithFields is mandatory unless WithDerivedKey is given.
+ *         Returns : The sorter, and any error in the options.
+ * Externals -  In : _defaultFanIn, _defaultKeysPerSort
+ * Externals - Out : None.
+ *       Functions : catch, checkFieldTypes, checkKeys, halt, loadFilters, newSorter, ParseFieldSpec
+ *         Remarks : The defaults are an ascending sort, a tab separator, _defaultKeysPerSort keys per initial run, merges
+ *                   of _defaultFanIn files and the temporary directory reported by the OS as the temporary storage. The
+ *                   field specification is parsed once, here.
+ *         History : v1.8.0 - October 15, 2026 - Original release.
+ */
+    defer catch(&err)
+    s = newSorter(opts)
+    if s.usingFields == "" && len(s.keys) == 0 && s.derive == nil {
+        haltKind(ErrBadFieldSpec, "the index fields columns were not specified", nil)
+    }
+    if s.keysPerSort <= 0  { halt("the number of keys for in-place sorting was not specified") }
+    if s.sep         == "" { halt("the field separator was not specified") }
+    if s.fanIn       <  2  { halt("the merge fan-in must be at least 2") }
+    if s.maxOpen != 0 && s.maxOpen < 3 { halt("the cap on open temporary files must be at least 3") }
+    if s.maxTemp < 0 { halt("the cap on the temporary space cannot be negative") }
+    if s.runBudget < 0 { halt("the memory budget of a run cannot be negative") }
+    if s.maxBuckets < 0 { halt("the number of distinct values sorted by buckets cannot be negative") }
+    if s.packRuns < 0 { halt("the number of runs per spill file cannot be negative") }
+    if s.pressure < 0 || s.pressure > 1 { halt("the fraction of the memory limit must lie between 0 and 1") }
+    if s.maxLiveRuns != 0 && s.maxLiveRuns < 2 { halt("the cap on the live run files must be at least 2") }
+    for _, dir := range s.tempDirs {
+        if dir.Path == "" || dir.MinFree < 0 { halt("a temporary directory needs a path and a floor that is not negative") }
+    }
+    if s.tempPlacement != PlaceFirstFit && s.tempPlacement != PlaceRoundRobin {
+        halt("the placement of the temporary files is unknown")
+    }
+    if s.uniqueMode != UniqueFail && s.uniqueMode != UniqueCount { halt("the outcome of the duplicate keys is unknown") }
+    if s.dedupPolicy < KeepAll || s.dedupPolicy > KeepLast { halt("the deduplication policy is unknown") }
+    if s.dupsPath != "" && s.dedupPolicy == KeepAll { halt("the duplicates file requires a deduplication policy") }
+    if s.ioAttempts < 1 { halt("the number of I/O attempts must be at least 1") }
+    if s.ioRate < 0 { halt("the cap on the I/O rate cannot be negative") }
+    if s.heartbeat < 0 { halt("the interval of the heartbeat cannot be negative") }
+    if s.parallelism < 1 { halt("the degree of parallelism must be at least 1") }
+    if s.payloadMax  < 0 { halt("the length of the records embedded in the keys cannot be negative") }
+    if s.lineRange && s.lineFrom < 1 { halt("the line range must start at line 1 or later") }
+    if s.pctPrec < 0 { halt("the precision of the percentiles cannot be negative") }
+    if s.maxGroupLen < 0 { halt("the longest record with its continuation lines cannot be negative") }
+    if s.errorCap && s.maxErrors < 0 { halt("the number of malformed records tolerated cannot be negative") }
+    if s.rateCap && (!(s.errorRate >= 0 && s.errorRate <= 1) || s.rateSample < 0) {
+        halt("the rate of malformed records must lie from 0 to 1, with a sample that is not negative")
+    }
+    if s.sampled && (s.sampleHead < 0 || s.sampleBlocks < 0 || s.sampleMargin < 0) {
+        halt("the sample of the field widths cannot have a negative size or margin")
+    }
+    if s.usingFields != "" {
+        s.keys, err = ParseFieldSpec(s.usingFields)
+        if err != nil { panic(haltError{err}) }
+    }
+    s.checkKeys()
+    s.checkFieldTypes()
+    s.checkGroupings()
+    s.checkBoolTokens()
+    s.checkZeroPads()
+    s.checkAligns()
+    s.checkCodec()
+    s.loadFilters()
+    return s, nil
+} //end func NewSorter
+func (s *Sorter) Run(inFile, outFile string) (err error) {
+/*         Purpose : Sorts a text file with the sorter's settings.
+ *       Arguments : inFile  = path of the file with the data to be sorted.
+ *                   outFile = path of the file for the sorted data.
+ *         Returns : Any error encountered.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : catch, checkRemoval, halt, preserveAttributes, removeInputs, sortFile, useScratchDir
+ *         Remarks : All the state of a sort lives in the run it creates, including the prefix of its temporary files, so
+ *                   a sorter can be used repeatedly and from several goroutines at once.
+ *         History : v1.8.0 - October 15, 2026 - Original release.
+ */
+    defer catch(&err)
+    if outFile == "" { halt("the output file was not specified") }
+    run := s.newRun()
+    run.checkRemoval(outFile, inFile)
+    defer run.useScratchDir(outFile)()
+    run.sortFile(inFile, outFile, nil)
+    run.preserveAttributes(inFile, outFile)
+    run.removeInputs(inFile)
+    return
+} //end func Run
+func (s *Sorter) RunFS(fsys fs.FS, name, outFile string) (err error) {
+/*         Purpose : Sorts a text file read from a file system with the sorter's settings.
+ *       Arguments : fsys    = the file system holding the data to be sorted, e.g. an embed.FS or an fstest.MapFS.
+ *                   name    = name of the file with the data to be sorted, per the conventions of fs.FS.
+ *                   outFile = path of the file for the sorted data, on the OS file system.
+ *         Returns : Any error encountered.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : catch, createTemp, halt, removeTemp, sortFile, useScratchDir
+ *         Remarks : The file is read in place when it implements io.Seeker. Otherwise it is first copied to a temporary
+ *                   file prefixed as "spool_". The temporary files reside on the sorter's temporary storage.
+ *         History : v1.10.0 - October 15, 2026 - Original release.
+ */
+    defer catch(&err)
+    if fsys    == nil { halt("the file system was not specified") }
+    if outFile == ""  { halt("the output file was not specified") }
+    if s.removeInput   { halt("WithRemoveInput does not apply to the files of a fs.FS") }
+    if s.preserveAttrs { halt("WithPreserveAttributes does not apply to the files of a fs.FS") }
+    run     := s.newRun()
+    defer run.useScratchDir(outFile)()
+    fh, err := fsys.Open(name)
+    if err != nil { haltKind(ErrInputNotFound, "the input file cannot be located", err) }
+    if _, ok := fh.(io.Seeker); ok {
+        fh.Close()
+        run.fsys = fsys
+        run.sortFile(name, outFile, nil)
+        return
+    }
+    //Spool the input to the OS file system
+    fhSpool, spoolFile := run.createTemp("spool_")
+    defer run.removeTemp(spoolFile)
+    _, errCopy := io.Copy(fhSpool, fh)
+    fh.Close()
+    if errClose := fhSpool.Close(); errCopy == nil { errCopy = errClose }
+    if errCopy != nil { haltTemp("io.Copy", errCopy) }
+    run.spooled = true
+    run.sortFile(spoolFile, outFile, nil)
+    return
+} //end func RunFS
+func WithAscending(sortAsc bool) Option {
+    //Requests an ascending alphanumeric sort if true, a descending one otherwise
+    return func(s *Sorter) { s.sortAsc = sortAsc }
+} //end func WithAscending
+func WithFields(usingFields string) Option {
+    //Sets the CSV of field numbers to use as indexes, ordered as primary, secondary, etc., with the first field referenced as 1
+    return func(s *Sorter) { s.usingFields, s.keys = usingFields, nil }
+} //end func WithFields
+func WithKeysPerSort(keysPerSort int) Option {
+    //Sets the number of elements for in-place sorting of the initial composite-key files
+    return func(s *Sorter) { s.keysPerSort = keysPerSort }
+} //end func WithKeysPerSort
+func WithMergeFanIn(fanIn int) Option {
+    //Sets the number of key files merged at once, 2 by default: fewer passes for more open files & buffers per merge
+    return func(s *Sorter) { s.fanIn = fanIn }
+} //end func WithMergeFanIn
+func WithSeparator(sep string) Option {
+    //Sets the field separator
+    return func(s *Sorter) { s.sep = sep }
+} //end func WithSeparator
+func WithTempDir(dir string) Option {
+    //Sets the directory of the temporary files
+    return func(s *Sorter) { s.tempDir = dir }
+} //end func WithTempDir
+func WithVerbose(verbose bool) Option {
+    //Echoes the main execution stages to Stdout if true
+    return func(s *Sorter) { s.verbose = verbose }
+} //end func WithVerbose
+//Private ----------------------------------------------------------------------------------------------------------------------
+const(
+    _defaultKeysPerSort = 100000
+    _defaultFanIn       = 2
+)
+var _runCount uint64 //number of runs started by the process
+type sortRun struct {
+    *Sorter                        //settings of the run
+    fsys       fs.FS               //file system of the input, or nil for the OS one
+    spooled    bool                //input copied to the temporary storage
+    progress   *mergeTracker       //progress of the merges of the current external sort
+    space      *tempSpace          //bytes & number of the temporary files present, and their peaks
+    runSize    int                 //number of keys of the next initial run
+    packs      *runPacks           //spill files of the packed runs, nil if the runs are not packed
+    memLimit   int64               //memory limit of WithMemoryPressure, 0 until read, -1 if unknown
+    gcCycles   uint64              //garbage collections completed at the last flush under memory pressure
+    indexing   bool                //keys destined to an index file, which never embed their records
+    tempSeq    uint64              //sequence number of the last temporary file, if deterministic
+    counters   *runCounters        //I/O counters, shared with the stages of the run
+    stats      SortStats           //statistics of the run
+    ranged     bool                //offsets of the line range tracked
+    rangeStart int64               //offset of the first line of the range
+    rangeEnd   int64               //offset following the last line of the range
+    ranking    ranking             //position of the output in the ranks
+    unique     uniqueCheck         //duplicates met in the output
+    dedup      dedupState          //records suppressed from the output
+    skipped    map[int]error       //faults of the lines skipped as malformed, kept over a restart of the keying
+    faults     faultLog            //malformed records skipped
+    producing  bool                //keys of a range of the input, whose error rate is left to the merger
+    rejects    *rejectFile         //reject file, once open
+    prefix     string              //prefix of the run's temporary key files
+    start      time.Time           //start of execution
+}
+func (s *Sorter) newRun() *sortRun {
+    r := &sortRun{Sorter:s, prefix:fmt.Sprintf("keys_%d-%d_", os.Getpid(), atomic.AddUint64(&_runCount, 1)),
+                  counters:&runCounters{}, start:time.Now()}
+    r.space = &tempSpace{limit:s.maxTemp, files:map[string]int64{}, pool:s.poolSpace}
+    if s.packRuns > 1 { r.packs = newRunPacks() }
+    return r
+} //end func newRun
+func newSorter(opts []Option) *Sorter {
+    //Creates a sorter with the defaults overridden by the options, without validating them
+    s := &Sorter{sortAsc:true, sep:"\t", keysPerSort:_defaultKeysPerSort, fanIn:_defaultFanIn, maxBuckets:_bucketValues,
+                 ioAttempts:_defaultIOAttempts, ioDelay:_defaultIODelay, parallelism:defaultParallelism()}
+    for _, opt := range opts { opt(s) }
+    if len(s.tempDirs) > 0 { s.tempDir = s.tempDirs[0].Path }
+    if s.tempDir   == "" { s.tempDir = os.TempDir() }
+    if s.maxOpen   == 0   { s.limiter = sharedLimiter() } else { s.limiter = newFileLimiter(s.maxOpen) }
+    if s.storage   == nil && len(s.tempDirs) > 0 { s.storage = newDirsStorage(s.tempDirs, s.tempPlacement, s.limiter) }
+    if s.storage   == nil { s.storage = newOSStorage(s.tempDir, s.limiter) }
+    if s.encrypt          { s.storage = newCipherStorage(s.storage) }
+    if s.memBudget >  0   { s.storage = newTieredStorage(s.storage, s.memBudget) }
+    if s.ioRate    >  0   { s.throttle = newIOThrottle(s.ioRate) }
+    if s.lowPriority      { s.parallelism = 1 }
+    return s
+} //end func newSorter
+func newLegacySorter(sortAsc bool, usingFields, sep string, keysPerSort int, verbose bool, opts []Option) *Sorter {
+    //Creates the sorter for the positional arguments of the Sort family of functions, followed by any further options
+    s, err := NewSorter(append([]Option{WithAscending(sortAsc), WithFields(usingFields), WithSeparator(sep),
+                                        WithKeysPerSort(keysPerSort), WithVerbose(verbose)}, opts...)...)
+    if err != nil { panic(haltError{err}) }
+    return s
+} //end func newLegacySorter
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file sorter.go
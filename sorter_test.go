@@ -0,0 +1,32 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     sorter_test.go
+ * Overview:
+ *     benchmarks of the settings of the sorters.
+ * Functions:
+ *     BenchmarkMergeFanIn(b *testing.B)
+ *         Sorts 100 runs, sweeping the number of key files per merge task.
+ * History:
+ *     v1.29.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "fmt"
+    "math/rand"
+    "testing"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func BenchmarkMergeFanIn(b *testing.B) {
+    //Sub-benchmarks named e.g. "fanIn=8": the fewer passes of a larger fan-in against its more files open per task
+    inFile := writeInput(b, randomInput(rand.New(rand.NewSource(9)), 200000))
+    for _, fanIn := range []int{2, 4, 8, 16, 64, 128} {
+        b.Run(fmt.Sprintf("fanIn=%d", fanIn), func(b *testing.B) {
+            benchSort(b, inFile, WithFields("2,1"), WithKeysPerSort(2000), WithMergeFanIn(fanIn))
+        })
+    }
+} //end func BenchmarkMergeFanIn
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file sorter_test.go
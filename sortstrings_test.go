@@ -0,0 +1,80 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     sortstrings_test.go
+ * Overview:
+ *     property tests of the external sort against SortStrings, its in-memory reference, on generated inputs.
+ * Functions:
+ *     TestSortStringsMatchesSort(t *testing.T)
+ *         Checks that Sort and SortStrings order generated records alike, both stable in either direction.
+ * History:
+ *     v1.6.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "fmt"
+    "math/rand"
+    "strconv"
+    "strings"
+    "testing"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func TestSortStringsMatchesSort(t *testing.T) {
+    //Records led by their input ordinal, followed by up to 3 fields of few values, some empty or missing past the first
+    //record whose fields Sort counts, so that many records share their index fields; the index fields are drawn among the
+    //fields after the ordinal
+    for seed := int64(1); seed <= 30; seed++ {
+        rng     := rand.New(rand.NewSource(seed))
+        sep     := []string{"\t", ",", "::"}[rng.Intn(3)]
+        records := make([]string, 1 + rng.Intn(200))
+        for k := range records {
+            fields    := []string{strconv.Itoa(k)}
+            numFields := 3
+            if k > 0 { numFields = rng.Intn(4) }
+            for ; numFields > 0; numFields-- { fields = append(fields, randomValue(rng)) }
+            records[k] = strings.Join(fields, sep) + "\n"
+        }
+        colNums     := rng.Perm(3)[:1 + rng.Intn(3)]                  //index fields, 0-based among those after the ordinal
+        specs       := make([]string, len(colNums))
+        for k, colNum := range colNums { specs[k] = strconv.Itoa(colNum + 2) }
+        usingFields := strings.Join(specs, ",")
+        for _, sortAsc := range []bool{true, false} {
+            name   := fmt.Sprintf("seed=%d/fields=%s/asc=%v", seed, usingFields, sortAsc)
+            sorted := append([]string(nil), records...)
+            if err := SortStrings(sorted, sortAsc, usingFields, sep); err != nil { t.Fatalf("%s: %v", name, err) }
+            output := sortBytes(t, strings.Join(records, ""), WithFields(usingFields), WithSeparator(sep),
+                                WithAscending(sortAsc), WithKeysPerSort(5 + rng.Intn(30)), WithMergeFanIn(2 + rng.Intn(3)))
+            if string(output) != strings.Join(sorted, "") {
+                t.Fatalf("%s: Sort output\n%s\nSortStrings\n%s", name, output, strings.Join(sorted, ""))
+            }
+            //Equal index fields in their input order, in both directions
+            for k := 1; k < len(sorted); k++ {
+                prev, next := indexValues(sorted[k - 1], sep, colNums), indexValues(sorted[k], sep, colNums)
+                if prev == next && ordinal(sorted[k - 1], sep) > ordinal(sorted[k], sep) {
+                    t.Fatalf("%s: %q follows %q, not stable", name, sorted[k], sorted[k - 1])
+                }
+            }
+        }
+    }
+} //end func TestSortStringsMatchesSort
+//Private ----------------------------------------------------------------------------------------------------------------------
+func randomValue(rng *rand.Rand) string {
+    //Returns a field value among few, possibly empty
+    return []string{"", "a", "ab", "b", "ba", "z", "10", "9"}[rng.Intn(8)]
+} //end func randomValue
+func indexValues(record, sep string, colNums []int) string {
+    //Returns the values of the index fields of a record, the missing ones empty, joined by a line feed
+    fields := strings.Split(strings.TrimSuffix(record, "\n"), sep)
+    values := make([]string, len(colNums))
+    for k, colNum := range colNums {
+        if colNum + 1 < len(fields) { values[k] = fields[colNum + 1] }
+    }
+    return strings.Join(values, "\n")
+} //end func indexValues
+func ordinal(record, sep string) int {
+    //Returns the input ordinal leading a record
+    n, _ := strconv.Atoi(strings.SplitN(strings.TrimSuffix(record, "\n"), sep, 2)[0])
+    return n
+} //end func ordinal
@@ -0,0 +1,236 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     spillqueue.go
+ * Overview:
+ *     disk-backed queue delivering the records pushed into it in sorted order.
+ * Functions:
+ *     NewSpillQueue(opts ...Option) (*SpillQueue, error)
+ *         Creates an empty spill queue sorting per the given options.
+ *     (*SpillQueue) Push(record string) error
+ *         Adds a record to the queue.
+ *     (*SpillQueue) Sorted() (*SpillIterator, error)
+ *         Ends the pushes and returns an iterator over the records in sorted order.
+ *     (*SpillQueue) Close() error
+ *         Releases the queue's files.
+ * Types:
+ *     SpillQueue
+ *         External priority queue spilling sorted runs to temporary files.
+ *     SpillIterator
+ *         Iterator merging the runs of a spill queue.
+ * History:
+ *     v1.9.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "bufio"
+    "container/heap"
+    "errors"
+    "fmt"
+    "io"
+    "os"
+    "sort"
+    "strings"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+type SpillQueue struct {
+    run     *sortRun       //settings & temporary file prefix
+    buffer  []string       //records of the current in-memory run
+    runs    []string       //paths of the spilled runs, in push order
+    files   []TempFile     //handles opened by Sorted
+    sorted  bool           //pushes are over
+    closed  bool
+}
+type SpillIterator struct {
+    sources spillHeap      //run heads, ordered on their current records
+    record  string         //current record
+    err     error          //first error encountered
+}
+
+func NewSpillQueue(opts ...Option) (*SpillQueue, error) {
+/*         Purpose : Creates an empty spill queue sorting per the given options.
+ *       Arguments : opts = the options, as for NewSorter. WithFields is mandatory.
+ *         Returns : The queue, and any error in the options.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : NewSorter
+ *         Remarks : WithKeysPerSort sets the number of records held in memory before a sorted run is spilled to disk.
+ *         History : v1.9.0 - October 15, 2026 - Original release.
+ */
+    s, err := NewSorter(opts...)
+    if err != nil { return nil, err }
+    return &SpillQueue{run:s.newRun()}, nil
+} //end func NewSpillQueue
+func (q *SpillQueue) Push(record string) (err error) {
+/*         Purpose : Adds a record to the queue.
+ *       Arguments : record = the record, with or without its line terminator.
+ *         Returns : Any error encountered, including pushing after Sorted or Close.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : catch, spill
+ *         Remarks : A record may not hold a newline other than its terminator. Once keysPerSort records are buffered,
+ *                   they are sorted and written out as a run.
+ *         History : v1.9.0 - October 15, 2026 - Original release.
+ */
+    defer catch(&err)
+    if q.closed { return errors.New("mergesort: the spill queue is closed") }
+    if q.sorted { return errors.New("mergesort: push after Sorted") }
+    record = strings.TrimRight(record, "\r\n")
+    if strings.Contains(record, "\n") { return errors.New("mergesort: the record holds a newline") }
+    q.buffer = append(q.buffer, record)
+    if len(q.buffer) == q.run.keysPerSort { q.spill() }
+    return nil
+} //end func Push
+func (q *SpillQueue) Sorted() (it *SpillIterator, err error) {
+/*         Purpose : Ends the pushes and returns an iterator over the records in sorted order.
+ *       Arguments : None.
+ *         Returns : The iterator, and any error encountered.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : catch, openTemp, sortBuffer
+ *         Remarks : The runs are merged lazily, one record per call to Next, with the last run kept in memory rather than
+ *                   spilled. Equal index fields keep their push order in both directions. Sorted can only be called once.
+ *         History : v1.9.0 - October 15, 2026 - Original release.
+ */
+    defer catch(&err)
+    if q.closed { return nil, errors.New("mergesort: the spill queue is closed") }
+    if q.sorted { return nil, errors.New("mergesort: Sorted was already called") }
+    q.sorted = true
+    q.sortBuffer()
+    it       = &SpillIterator{}
+    compare := q.run.orderComparer()
+    for k, v := range append(q.runs, "") {
+        src := &spillSource{rank:k, keyFn:q.run.orderKey, compare:compare, sortAsc:q.run.sortAsc}
+        if v == "" {
+            src.records = q.buffer
+        } else {
+            fh        := q.run.openTemp(v)
+            q.files    = append(q.files, fh)
+            src.reader = bufio.NewReader(fh)
+        }
+        if src.advance() { it.sources = append(it.sources, src) }
+        if src.err != nil { return nil, src.err }
+    }
+    heap.Init(&it.sources)
+    return it, nil
+} //end func Sorted
+func (q *SpillQueue) Close() error {
+/*         Purpose : Releases the queue's files.
+ *       Arguments : None.
+ *         Returns : The first error encountered when closing or removing the files.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : None.
+ *         Remarks : All the temporary files are removed. Any iterator returned by Sorted becomes unusable.
+ *         History : v1.9.0 - October 15, 2026 - Original release.
+ */
+    var err error
+    if q.closed { return nil }
+    q.closed = true
+    for _, fh := range q.files {
+        if errClose := fh.Close(); err == nil { err = errClose }
+    }
+    for _, v := range q.runs {
+        if errRemove := q.run.storage.Remove(v); err == nil && !os.IsNotExist(errRemove) { err = errRemove }
+        q.run.space.release(v)
+    }
+    q.buffer, q.runs, q.files = nil, nil, nil
+    return err
+} //end func Close
+func (it *SpillIterator) Next() bool {
+/*         Purpose : Advances the iterator to the next record in sorted order.
+ *       Arguments : None.
+ *         Returns : True if a record is available, false once all have been delivered or on error.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : advance
+ *         Remarks : None.
+ *         History : v1.9.0 - October 15, 2026 - Original release.
+ */
+    if it.err != nil || len(it.sources) == 0 { return false }
+    src      := it.sources[0]
+    it.record = src.record
+    if src.advance() {
+        heap.Fix(&it.sources, 0)
+    } else {
+        heap.Pop(&it.sources)
+        it.err = src.err
+    }
+    return true
+} //end func Next
+func (it *SpillIterator) Record() string { return it.record } //the current record, without its terminator
+func (it *SpillIterator) Err() error     { return it.err }    //the first error encountered, if any
+//Private ----------------------------------------------------------------------------------------------------------------------
+func (q *SpillQueue) sortBuffer() {
+    keys    := make([][]string, len(q.buffer))
+    order   := make([]int,      len(q.buffer))
+    compare := q.run.orderComparer()
+    for k, record := range q.buffer {
+        keys[k], order[k] = q.run.orderKey(record), k
+    }
+    sort.SliceStable(order, func(i, j int) bool {
+        return precedes(compare(keys[order[i]], keys[order[j]]), q.run.sortAsc)
+    })
+    sorted := make([]string, len(q.buffer))
+    for k, v := range order { sorted[k] = q.buffer[v] }
+    q.buffer = sorted
+} //end func sortBuffer
+func (q *SpillQueue) spill() {
+    q.sortBuffer()
+    fh, tempFile := q.run.createTemp("spill_")
+    q.runs        = append(q.runs, tempFile)
+    writer       := bufio.NewWriter(fh)
+    for _, v := range q.buffer { fmt.Fprintln(writer, v) }
+    if err := writer.Flush(); err != nil { haltTemp("writer.Flush", err) }
+    if err := fh.Sync();      err != nil { haltTemp("fh.Sync", err) }
+    if err := fh.Close();     err != nil { haltTemp("fh.Close", err) }
+    if q.run.verbose { fmt.Println("func SpillQueue - spilled", len(q.buffer), "records to", q.run.tempLabel(tempFile)) }
+    q.buffer = nil
+} //end func spill
+////Run heads
+type spillSource struct {
+    rank    int            //position of the run in push order
+    reader  *bufio.Reader  //spilled run, or nil for the in-memory one
+    records []string       //remaining records of the in-memory run
+    keyFn   func(record string) []string //index-field values as compared
+    compare func(a, b []string) int      //comparison of the index-field values of two records
+    sortAsc bool
+    record  string         //current record
+    key     []string       //index-field values of the current record
+    err     error
+}
+func (src *spillSource) advance() bool {
+    //Moves to the run's next record, returning false at the end of the run or on error
+    if src.reader == nil {
+        if len(src.records) == 0 { return false }
+        src.record, src.records = src.records[0], src.records[1:]
+    } else {
+        record, err := src.reader.ReadString('\n')
+        if err != nil {
+            if err != io.EOF { src.err = err }
+            return false
+        }
+        src.record = strings.TrimSuffix(record, "\n")
+    }
+    src.key = src.keyFn(src.record)
+    return true
+} //end func advance
+type spillHeap []*spillSource
+func (h spillHeap) Len() int { return len(h) }
+func (h spillHeap) Less(i, j int) bool {
+    //Orders on the index fields per the sort direction, then on the run rank so that pushes keep their order
+    if cmp := h[i].compare(h[i].key, h[j].key); cmp != 0 { return precedes(cmp, h[i].sortAsc) }
+    return h[i].rank < h[j].rank
+}
+func (h spillHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *spillHeap) Push(x interface{}) { *h = append(*h, x.(*spillSource)) }
+func (h *spillHeap) Pop() interface{} {
+    old   := *h
+    src   := old[len(old) - 1]
+    *h     = old[:len(old) - 1]
+    return src
+}
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file spillqueue.go
@@ -0,0 +1,73 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     spillqueue_test.go
+ * Overview:
+ *     tests of the disk-backed queue delivering its records in sorted order.
+ * Functions:
+ *     TestSpillQueue(t *testing.T)
+ *         Checks the records delivered in either direction, their runs spilled, as SortStrings orders them.
+ *     TestSpillQueueMisuse(t *testing.T)
+ *         Checks the errors of the pushes after Sorted or Close and of a second Sorted, and the removal of the runs.
+ * History:
+ *     v1.9.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "io/ioutil"
+    "math/rand"
+    "strings"
+    "testing"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func TestSpillQueue(t *testing.T) {
+    //200 records spilled in runs of 30, the last 20 kept in memory
+    records := strings.SplitAfter(randomInput(rand.New(rand.NewSource(24)), 200), "\n")
+    records  = records[:len(records) - 1]
+    for _, sortAsc := range []bool{true, false} {
+        queue, err := NewSpillQueue(WithFields("2,1"), WithSeparator("\t"), WithAscending(sortAsc), WithKeysPerSort(30),
+                                    WithTempDir(t.TempDir()))
+        if err != nil { t.Fatal(err) }
+        for _, record := range records {
+            if err := queue.Push(record); err != nil { t.Fatal(err) }
+        }
+        if len(queue.runs) != 6 { t.Errorf("asc %v: %d runs spilled, expected 6", sortAsc, len(queue.runs)) }
+        it, err := queue.Sorted()
+        if err != nil { t.Fatal(err) }
+        var output strings.Builder
+        for it.Next() { output.WriteString(it.Record() + "\n") }
+        if err := it.Err(); err != nil { t.Fatal(err) }
+        if err := queue.Close(); err != nil { t.Fatal(err) }
+        sorted := append([]string(nil), records...)
+        if err := SortStrings(sorted, sortAsc, "2,1", "\t"); err != nil { t.Fatal(err) }
+        if output.String() != strings.Join(sorted, "") { t.Errorf("asc %v: records not delivered in sorted order", sortAsc) }
+    }
+} //end func TestSpillQueue
+func TestSpillQueueMisuse(t *testing.T) {
+    tempDir := t.TempDir()
+    queue, err := NewSpillQueue(WithFields("1"), WithSeparator("\t"), WithKeysPerSort(2), WithTempDir(tempDir))
+    if err != nil { t.Fatal(err) }
+    for _, record := range []string{"c\n", "a\n", "b"} {
+        if err := queue.Push(record); err != nil { t.Fatal(err) }
+    }
+    if err := queue.Push("x\ny"); err == nil { t.Error("record holding a newline pushed") }
+    it, err := queue.Sorted()
+    if err != nil { t.Fatal(err) }
+    if err := queue.Push("d"); err == nil || !strings.Contains(err.Error(), "push after Sorted") {
+        t.Errorf("push after Sorted: error %v", err)
+    }
+    if _, err := queue.Sorted(); err == nil { t.Error("Sorted called twice without an error") }
+    var delivered []string
+    for it.Next() { delivered = append(delivered, it.Record()) }
+    if joined := strings.Join(delivered, ","); joined != "a,b,c" { t.Errorf("delivered %s, expected a,b,c", joined) }
+    if err := queue.Close(); err != nil { t.Fatal(err) }
+    if err := queue.Push("e"); err == nil { t.Error("push after Close without an error") }
+    if err := queue.Close(); err != nil { t.Errorf("second Close: %v", err) }
+    if left, err := ioutil.ReadDir(tempDir); err != nil || len(left) > 0 {
+        t.Errorf("%d temporary files left by Close (%v)", len(left), err)
+    }
+} //end func TestSpillQueueMisuse
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file spillqueue_test.go
@@ -0,0 +1,131 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     stable.go
+ * Overview:
+ *     check that a file is a stable sort of another, i.e. ordered, and holding the records of equal index fields in their
+ *     input order.
+ * Functions:
+ *     VerifyStable(inFile, outFile string, usingFields, sep string, sortAsc bool) (bool, error)
+ *         Checks that a file is a stable sort of another on the given index fields.
+ * History:
+ *     v1.69.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "bufio"
+    "encoding/binary"
+    "errors"
+    "fmt"
+    "hash/fnv"
+    "io"
+    "strings"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func VerifyStable(inFile, outFile string, usingFields, sep string, sortAsc bool) (stable bool, err error) {
+/*         Purpose : Checks that a file is a stable sort of another on the given index fields.
+ *       Arguments : inFile      = path of the input of the sort.
+ *                   outFile     = path of the output of the sort.
+ *                   usingFields = CSV of field numbers used as indexes when sorting, ordered as primary, secondary, etc.,
+ *                                 with the first field referenced as 1.
+ *                   sep         = the field separator of both files.
+ *                   sortAsc     = boolean flag indicating whether the output was sorted in ascending order.
+ *         Returns : Whether the output is ordered, holds the records of the input and keeps those with equal index fields
+ *                   in their input order, and any error encountered while reading the files.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : catch, compareKeys, keyDigest, parseColumns, precedes, recordKey, scanRecords, stableDigest
+ *         Remarks : The index fields are compared as Sort compares text fields, i.e. on values right-aligned to a common
+ *                   width, and blank records are disregarded, as Sort drops them. Both files are streamed, the output
+ *                   first: its order is checked as it is read, and every record is fingerprinted along with its rank
+ *                   within its run of equal index fields, summed over the file. The input is then fingerprinted alike,
+ *                   the rank of each record being the number of records with the same index fields read before it, so
+ *                   that both sums match only if each run of the output holds the records of the input in the same
+ *                   order. Only the runs of two or more records are tracked, by a digest of their index fields, so the
+ *                   memory used grows with the number of duplicated keys rather than with the size of the files. The
+ *                   check is probabilistic, 64-bit digests colliding with a negligible probability.
+ *         History : v1.69.0 - October 15, 2026 - Original release.
+ */
+    defer catch(&err)
+    if inFile == "" || outFile == "" { return false, errors.New("mergesort: the input and output files were not specified") }
+    if usingFields == "" {
+        return false, &kindError{kind:ErrBadFieldSpec, err:errors.New("mergesort: the index fields columns were not specified")}
+    }
+    colIdxs, err := parseColumns(usingFields)
+    if err != nil { return false, fmt.Errorf("mergesort: %w", err) }
+
+    //Scan the output, checking its order and sizing its runs of equal index fields
+    var(
+        inSum, outSum   uint64                                    //sums of the fingerprints of the records
+        inRecs, outRecs int
+        prevKey         []string                                  //index fields of the previous output record
+        rank            int                                       //rank of the output record within its run
+        ranks           = map[uint64]int{}                        //next rank of the runs of two or more, by key digest
+    )
+    ordered := scanRecords(outFile, func(record string) bool {
+        key := recordKey(record, sep, colIdxs)
+        if prevKey != nil {
+            cmp := compareKeys(key, prevKey)
+            if precedes(cmp, sortAsc) { return false }             //out of order
+            if cmp == 0 {
+                rank++
+                ranks[keyDigest(key)] = 0
+            } else {
+                rank = 0
+            }
+        }
+        prevKey = key
+        outSum += stableDigest(key, rank, record)
+        outRecs++
+        return true
+    })
+    if !ordered { return false, nil }
+    //Scan the input, ranking its records within their runs
+    scanRecords(inFile, func(record string) bool {
+        key           := recordKey(record, sep, colIdxs)
+        digest        := keyDigest(key)
+        rank, tracked := ranks[digest]
+        if tracked { ranks[digest] = rank + 1 }
+        inSum += stableDigest(key, rank, record)
+        inRecs++
+        return true
+    })
+    return inRecs == outRecs && inSum == outSum, nil
+} //end func VerifyStable
+//Private ----------------------------------------------------------------------------------------------------------------------
+func scanRecords(file string, fn func(record string) bool) bool {
+    //Feeds fn the non-blank records of a file, without their line feeds, until it returns false; returns false if it did
+    fh, err := openFile(file)
+    defer fh.Close()
+    reader := bufio.NewReader(fh)
+    for err != io.EOF {
+        var record string
+        record, err = readString(reader)
+        if strings.Trim(record, " \r\n") == "" { continue }
+        if !fn(strings.TrimSuffix(record, "\n")) { return false }
+    }
+    return true
+} //end func scanRecords
+func keyDigest(key []string) uint64 {
+    //Returns the digest of a record's index fields, equal for the values that Sort compares as equal
+    hash := fnv.New64a()
+    for _, value := range key {
+        hash.Write([]byte(strings.TrimLeft(value, " ")))         //right-aligned values: leading spaces do not count
+        hash.Write([]byte{0})
+    }
+    return hash.Sum64()
+} //end func keyDigest
+func stableDigest(key []string, rank int, record string) uint64 {
+    //Returns the fingerprint of a record at a rank within its run of equal index fields
+    var buf [16]byte
+    hash := fnv.New64a()
+    binary.LittleEndian.PutUint64(buf[:8], keyDigest(key))
+    binary.LittleEndian.PutUint64(buf[8:], uint64(rank))
+    hash.Write(buf[:])
+    hash.Write([]byte(record))
+    return hash.Sum64()
+} //end func stableDigest
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file stable.go
@@ -0,0 +1,183 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     stats.go
+ * Overview:
+ *     statistics of a sort and verification of its output.
+ * Functions:
+ *     WithStats(stats *SortStats) Option
+ *         Option storing the statistics of each sort in stats.
+ *     WithVerifyOutput() Option
+ *         Option checking that the output of each sort is a permutation of its input.
+ * Types:
+ *     SortStats
+ *         Statistics of a sort.
+ *     PhaseStats
+ *         Wall time and bytes of a phase of a sort.
+ * History:
+ *     v1.16.0 - October 15, 2026 - Original release.
+ *     v1.20.0 - October 15, 2026 - Added InvalidValues.
+ *     v1.27.0 - October 15, 2026 - Added KeyFilters.
+ *     v1.41.0 - October 15, 2026 - Added TempBytes and MergePasses.
+ *     v1.50.0 - October 15, 2026 - Added Separator.
+ *     v1.66.0 - October 15, 2026 - Added SkippedRecords.
+ *     v1.82.0 - October 15, 2026 - Added Phases.
+ *     v1.83.0 - October 15, 2026 - The phases but the merge passes are traced.
+ *     v1.88.0 - October 15, 2026 - Added PeakTempBytes & PeakTempFiles.
+ *     v1.89.0 - October 15, 2026 - Added RunSizes.
+ *     v1.93.0 - October 15, 2026 - Added PressureFlushes.
+ *     v1.96.0 - October 15, 2026 - Added DuplicateKeys.
+ *     v1.97.0 - October 15, 2026 - Added SuppressedRecords.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "fmt"
+    "hash/fnv"
+    "strings"
+    "sync"
+    "sync/atomic"
+    "time"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+type SortStats struct {
+    InputRecords      int              //number of non-blank input records sorted, i.e. within any line range and filters
+    OutputRecords     int              //number of records written to the output, those suppressed by WithDedup excluded
+    InputDigest       uint64           //order-independent digest of the input records, with WithVerifyOutput only
+    OutputDigest      uint64           //order-independent digest of the output records, with WithVerifyOutput only
+    InvalidValues     int              //values of typed index fields that did not parse
+    SkippedRecords    int              //malformed records skipped by the handler of WithRecordErrorHandler
+    KeyFilters        []KeyFilterStats //outcomes of the key filters, in the order of their options
+    TempBytes         int64            //bytes written to the temporary files, whether in memory or not
+    PeakTempBytes     int64            //most bytes held at once by the temporary files, as TempBytes counts them
+    PeakTempFiles     int              //most temporary files present at once
+    MergePasses       int              //passes of merges over the key files, 0 if they fitted in a single run
+    RunSizes          []int            //keys of each initial run written, in order
+    PressureFlushes   int              //initial runs flushed early under memory pressure, per WithMemoryPressure
+    DuplicateKeys     int              //output records of the same index fields as the one before, per WithExpectUnique
+    SuppressedRecords int              //records left out of the output per WithDedup, written to any duplicates file
+    Separator         string           //field separator detected for "auto", empty otherwise
+    Phases            []PhaseStats     //phases of the sort, in order of completion
+}
+type PhaseStats struct {
+    Name     string                 //"width scan", "key generation", "merge pass N" or "output"
+    Duration time.Duration          //wall time of the phase
+    Bytes    int64                  //bytes of input read, or for a merge pass bytes of keys written, or bytes output
+}
+
+func WithStats(stats *SortStats) Option {
+    //Stores the statistics of each file sort in stats once it completes. A sorter used concurrently overwrites them.
+    return func(s *Sorter) { s.statsOut = stats }
+} //end func WithStats
+func WithVerifyOutput() Option {
+/*         Purpose : Checks that the output of each file sort is a permutation of its input.
+ *       Arguments : None.
+ *         Returns : The option.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : None.
+ *         Remarks : Each record is hashed once while its key is created and once while it is written. The sums of these
+ *                   hashes, together with the record counts, must agree or the sort fails. Blank records, which Sort
+ *                   drops, and a terminator added to the last record are disregarded. SortAndReduce is not checked as
+ *                   its reducer may rewrite the records. The records suppressed by WithDedup count as output.
+ *         History : v1.16.0 - October 15, 2026 - Original release.
+ *                   v1.97.0 - October 15, 2026 - The records suppressed by WithDedup counted as output.
+ */
+    return func(s *Sorter) { s.verify = true }
+} //end func WithVerifyOutput
+//Private ----------------------------------------------------------------------------------------------------------------------
+type runCounters struct {
+    tempBytes int64                //bytes written to the temporary files
+    passes    int64                //merge passes
+    merged    int64                //bytes written to the files of the merges
+    read      int64                //records keyed, counted for the heartbeat only
+    output    int64                //records output, counted for the heartbeat only
+    spoke     int64                //time of the last progress report or heartbeat, in Unix nanoseconds
+    mutex     sync.Mutex
+    phases    []PhaseStats         //phases completed
+    phase     string               //phase last started
+}
+type countedFile struct {
+    TempFile
+    written *int64                 //counter of the bytes written
+    space   *tempSpace             //bytes of the run's temporary files present
+    name    string                 //name of the file on the temporary storage
+}
+func (f *countedFile) Write(p []byte) (int, error) {
+    n, err := f.TempFile.Write(p)
+    atomic.AddInt64(f.written, int64(n))
+    f.space.grow(f.name, n)
+    return n, err
+} //end func Write
+func (r *sortRun) countInput(record string) {
+    r.stats.InputRecords++
+    if r.heartbeat > 0 { atomic.AddInt64(&r.counters.read, 1) }
+    if r.verify { r.stats.InputDigest += recordDigest(record) }
+} //end func countInput
+func (r *sortRun) countOutput(record string) {
+    r.stats.OutputRecords++
+    if r.heartbeat > 0 { atomic.AddInt64(&r.counters.output, 1) }
+    if r.verify { r.stats.OutputDigest += recordDigest(record) }
+} //end func countOutput
+func (r *sortRun) checkOutput(reducer *groupReducer) {
+    //Compares the digests of a completed file sort, then hands out its statistics
+    out := r.stats.OutputRecords + r.stats.SuppressedRecords       //records output, the suppressed ones included
+    if r.verify && reducer == nil && (r.stats.InputRecords != out ||
+                                      r.stats.InputDigest  != r.stats.OutputDigest + r.dedup.digest) {
+        haltKind(ErrNotPermutation, fmt.Sprintf("the output is not a permutation of the input (%d records in, %d out)",
+                                                r.stats.InputRecords, out), nil)
+    }
+    r.publishStats()
+} //end func checkOutput
+func (r *sortRun) publishStats() {
+    //Hands out the statistics of the run
+    r.stats.TempBytes                           = atomic.LoadInt64(&r.counters.tempBytes)
+    r.stats.PeakTempBytes, r.stats.PeakTempFiles = r.space.peaks()
+    r.stats.MergePasses                         = int(atomic.LoadInt64(&r.counters.passes))
+    r.counters.mutex.Lock()
+    r.stats.Phases = append([]PhaseStats(nil), r.counters.phases...)
+    r.counters.mutex.Unlock()
+    if r.verbose && len(r.stats.Phases) > 0 {
+        fmt.Println("func Sort - phases:")
+        for _, phase := range r.stats.Phases {
+            fmt.Printf("       %-16s %14v %16d bytes\n", phase.Name, phase.Duration.Round(time.Microsecond), phase.Bytes)
+        }
+    }
+    if r.statsOut != nil { *r.statsOut = r.stats }
+} //end func publishStats
+func (r *sortRun) timePhase(name string) func(bytes int64) {
+    //Starts timing a phase of the run within its trace region, returning the function recording it with the bytes it
+    //processed
+    untrace := r.traced(name)
+    done    := r.clockPhase(name)
+    return func(bytes int64) {
+        untrace()
+        done(bytes)
+    }
+} //end func timePhase
+func (r *sortRun) clockPhase(name string) func(bytes int64) {
+    //Starts timing a phase of the run, returning the function recording it with the bytes it processed
+    start := time.Now()
+    r.counters.mutex.Lock()
+    r.counters.phase = name
+    r.counters.mutex.Unlock()
+    return func(bytes int64) {
+        r.counters.mutex.Lock()
+        r.counters.phases = append(r.counters.phases, PhaseStats{Name:name, Duration:time.Since(start), Bytes:bytes})
+        r.counters.mutex.Unlock()
+    }
+} //end func clockPhase
+func (r *sortRun) timePass() func() {
+    //Starts timing the current merge pass, returning the function recording it with the bytes its merges wrote
+    merged := atomic.LoadInt64(&r.counters.merged)
+    done   := r.clockPhase(fmt.Sprint("merge pass ", atomic.LoadInt64(&r.counters.passes)))
+    return func() { done(atomic.LoadInt64(&r.counters.merged) - merged) }
+} //end func timePass
+func recordDigest(record string) uint64 {
+    hash := fnv.New64a()
+    hash.Write([]byte(strings.TrimSuffix(record, "\n")))
+    return hash.Sum64()
+} //end func recordDigest
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file stats.go
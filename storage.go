@@ -0,0 +1,389 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     storage.go
+ * Overview:
+ *     pluggable storage of the temporary files.
+ * Functions:
+ *     NewMemStorage() *MemStorage
+ *         Creates an empty RAM-backed temporary storage.
+ *     WithTempStorage(storage TempStorage) Option
+ *         Option placing the temporary files on the given storage.
+ *     WithInMemorySpillThreshold(bytes int64) Option
+ *         Option holding the temporary files in memory up to a cumulative size.
+ * Types:
+ *     TempStorage
+ *         Interface of a storage for the temporary files.
+ *     TempFile
+ *         Interface of a temporary file.
+ *     MemStorage
+ *         RAM-backed TempStorage, e.g. for tests that should not touch the disk.
+ * History:
+ *     v1.11.0 - October 15, 2026 - Original release.
+ *     v1.12.0 - October 15, 2026 - Added WithInMemorySpillThreshold.
+ *     v1.59.0 - October 15, 2026 - Anonymous files for the OS storage.
+ *     v1.73.0 - October 15, 2026 - The OS storage lists its files without globbing the path of its directory.
+ *     v1.91.0 - October 15, 2026 - Opening & removal of the runs packed by WithPackedRuns.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "errors"
+    "io"
+    "io/ioutil"
+    "os"
+    "path/filepath"
+    "sort"
+    "strconv"
+    "strings"
+    "sync"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+type TempStorage interface {
+    CreateTemp(prefix string) (TempFile, error) //creates a new file whose name starts with prefix, open for writing
+    Open(name string) (TempFile, error)         //opens an existing file for reading
+    Remove(name string) error                   //removes a file
+    List(prefix string) ([]string, error)       //returns the sorted names of the files created with prefix
+}
+type TempFile interface {
+    io.ReadWriteSeeker
+    io.Closer
+    Name() string
+    Sync() error
+}
+type MemStorage struct {
+    mutex sync.Mutex
+    files map[string]*memData
+    count int
+}
+
+func NewMemStorage() *MemStorage {
+/*         Purpose : Creates an empty RAM-backed temporary storage.
+ *       Arguments : None.
+ *         Returns : The storage.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : None.
+ *         Remarks : The storage is safe for concurrent use and can be shared by several sorters. The names of its files
+ *                   are their prefixes followed by a sequence number.
+ *         History : v1.11.0 - October 15, 2026 - Original release.
+ */
+    return &MemStorage{files:map[string]*memData{}}
+} //end func NewMemStorage
+func (m *MemStorage) CreateTemp(prefix string) (TempFile, error) {
+    m.mutex.Lock()
+    defer m.mutex.Unlock()
+    m.count++
+    name        := prefix + strconv.Itoa(m.count)
+    data        := &memData{}
+    m.files[name] = data
+    return &memFile{name:name, data:data}, nil
+} //end func CreateTemp
+func (m *MemStorage) Open(name string) (TempFile, error) {
+    m.mutex.Lock()
+    defer m.mutex.Unlock()
+    data, ok := m.files[name]
+    if !ok { return nil, &os.PathError{Op:"open", Path:name, Err:os.ErrNotExist} }
+    return &memFile{name:name, data:data}, nil
+} //end func Open
+func (m *MemStorage) Remove(name string) error {
+    m.mutex.Lock()
+    defer m.mutex.Unlock()
+    if _, ok := m.files[name]; !ok { return &os.PathError{Op:"remove", Path:name, Err:os.ErrNotExist} }
+    delete(m.files, name)
+    return nil
+} //end func Remove
+func (m *MemStorage) List(prefix string) ([]string, error) {
+    m.mutex.Lock()
+    defer m.mutex.Unlock()
+    names := []string{}
+    for name := range m.files {
+        if strings.HasPrefix(name, prefix) { names = append(names, name) }
+    }
+    sort.Strings(names)
+    return names, nil
+} //end func List
+func WithTempStorage(storage TempStorage) Option {
+/*         Purpose : Places the temporary files on the given storage rather than on the temporary directory.
+ *       Arguments : storage = the storage, e.g. a MemStorage.
+ *         Returns : The option.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : None.
+ *         Remarks : WithTempDir and WithTempDirs are then disregarded, while WithTempNextToOutput still places the files
+ *                   in its scratch directory. The storage receives every temporary file, keys, runs and spools alike, and
+ *                   must be safe for concurrent use, the merges overlapping the creation of the runs.
+ *         History : v1.11.0 - October 15, 2026 - Original release.
+ */
+    return func(s *Sorter) { s.storage = storage }
+} //end func WithTempStorage
+func WithInMemorySpillThreshold(bytes int64) Option {
+/*         Purpose : Holds the temporary files in memory up to a cumulative size.
+ *       Arguments : bytes = the memory budget of the temporary files. 0 for none, the default.
+ *         Returns : The option.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : None.
+ *         Remarks : The temporary files are created in memory as long as their cumulative size stays within bytes, any
+ *                   file that would exceed the budget being moved to the temporary storage. The budget also enables the
+ *                   in-memory fast path of sortFile: an input whose size and keys fit the budget together, i.e. with
+ *                   2*size <= bytes, is keyed, sorted and output in memory, without any temporary file.
+ *         History : v1.12.0 - October 15, 2026 - Original release.
+ *                   v1.13.0 - October 15, 2026 - Inputs within half the memory budget are sorted entirely in memory.
+ */
+    return func(s *Sorter) { s.memBudget = bytes }
+} //end func WithInMemorySpillThreshold
+//Private ----------------------------------------------------------------------------------------------------------------------
+////OS storage
+type osStorage struct {
+    dir  string
+    anon *anonFiles                                               //anonymous files of the directory, nil if all are named
+}
+func newOSStorage(dir string, limiter *fileLimiter) osStorage {
+    //Returns the storage of a directory, whose path is cleaned so that the names of its files are in the native form of
+    //filepath.Join, whichever way they are created or listed; the handles kept by its anonymous files are charged to limiter
+    return osStorage{dir:filepath.Clean(dir), anon:newAnonFiles(limiter)}
+} //end func newOSStorage
+func (o osStorage) CreateTemp(prefix string) (TempFile, error) {
+    if o.anon != nil {
+        if fh, ok, err := o.anon.create(o.dir, o.anon.nextName(prefix)); ok { return fh, err }
+    }
+    return ioutil.TempFile(o.dir, prefix)
+} //end func CreateTemp
+func (o osStorage) Open(name string) (TempFile, error) {
+    if fh, ok, err := o.anon.open(name); ok { return fh, err }
+    return os.Open(name)
+} //end func Open
+func (o osStorage) Remove(name string) error {
+    if o.anon.remove(name) { return nil }
+    return os.Remove(name)
+} //end func Remove
+func (o osStorage) List(prefix string) ([]string, error) {
+    names, err := listDir(o.dir, prefix, "")
+    if err != nil || o.anon == nil { return names, err }
+    names = append(names, o.anon.list(o.dir, prefix)...)
+    sort.Strings(names)
+    return names, nil
+} //end func List
+////Memory storage
+type memData struct {
+    mutex sync.RWMutex
+    bytes []byte
+}
+type memFile struct {
+    name   string
+    data   *memData
+    pos    int64
+    closed bool
+}
+func (f *memFile) Read(p []byte) (int, error) {
+    if f.closed { return 0, os.ErrClosed }
+    f.data.mutex.RLock()
+    defer f.data.mutex.RUnlock()
+    if f.pos >= int64(len(f.data.bytes)) { return 0, io.EOF }
+    n    := copy(p, f.data.bytes[f.pos:])
+    f.pos += int64(n)
+    return n, nil
+} //end func Read
+func (f *memFile) Write(p []byte) (int, error) {
+    if f.closed { return 0, os.ErrClosed }
+    f.data.mutex.Lock()
+    defer f.data.mutex.Unlock()
+    if end := f.pos + int64(len(p)); end > int64(len(f.data.bytes)) {
+        f.data.bytes = append(f.data.bytes, make([]byte, end - int64(len(f.data.bytes)))...)
+    }
+    n    := copy(f.data.bytes[f.pos:], p)
+    f.pos += int64(n)
+    return n, nil
+} //end func Write
+func (f *memFile) Seek(offset int64, whence int) (int64, error) {
+    if f.closed { return 0, os.ErrClosed }
+    f.data.mutex.RLock()
+    size := int64(len(f.data.bytes))
+    f.data.mutex.RUnlock()
+    switch whence {
+        case io.SeekCurrent: offset += f.pos
+        case io.SeekEnd:     offset += size
+    }
+    if offset < 0 { return 0, errors.New("mergesort: negative seek offset") }
+    f.pos = offset
+    return offset, nil
+} //end func Seek
+func (f *memFile) Close() error {
+    if f.closed { return os.ErrClosed }
+    f.closed = true
+    return nil
+} //end func Close
+func (f *memFile) Name() string { return f.name }
+func (f *memFile) Sync() error  { return nil }
+////Tiered storage
+type tieredStorage struct {
+    mem     *MemStorage
+    backing TempStorage
+    budget  int64                //maximum number of bytes held in memory
+    mutex   sync.Mutex
+    used    int64                //number of bytes held in memory
+    spilled map[string]string    //backing names of the files moved out of memory
+}
+type tieredFile struct {
+    TempFile                     //memory or backing file
+    storage *tieredStorage
+    name    string
+    prefix  string
+    inMem   bool
+    avoid   []string             //files off whose devices the file is to be moved out of memory, if placed
+}
+func newTieredStorage(backing TempStorage, budget int64) *tieredStorage {
+    return &tieredStorage{mem:NewMemStorage(), backing:backing, budget:budget, spilled:map[string]string{}}
+} //end func newTieredStorage
+func (t *tieredStorage) CreateTemp(prefix string) (TempFile, error) {
+    fh, err := t.mem.CreateTemp(prefix)
+    if err != nil { return nil, err }
+    return &tieredFile{TempFile:fh, storage:t, name:fh.Name(), prefix:prefix, inMem:true}, nil
+} //end func CreateTemp
+func (t *tieredStorage) Open(name string) (TempFile, error) {
+    t.mutex.Lock()
+    backingName, ok := t.spilled[name]
+    t.mutex.Unlock()
+    var(
+        fh  TempFile
+        err error
+    )
+    if ok { fh, err = t.backing.Open(backingName) } else { fh, err = t.mem.Open(name) }
+    if err != nil { return nil, err }
+    return &tieredFile{TempFile:fh, storage:t, name:name, inMem:!ok}, nil
+} //end func Open
+func (t *tieredStorage) Remove(name string) error {
+    t.mutex.Lock()
+    defer t.mutex.Unlock()
+    if backingName, ok := t.spilled[name]; ok {
+        delete(t.spilled, name)
+        return t.backing.Remove(backingName)
+    }
+    t.used -= t.mem.size(name)
+    return t.mem.Remove(name)
+} //end func Remove
+func (t *tieredStorage) List(prefix string) ([]string, error) {
+    names, err := t.mem.List(prefix)
+    if err != nil { return nil, err }
+    t.mutex.Lock()
+    for name := range t.spilled {
+        if strings.HasPrefix(name, prefix) { names = append(names, name) }
+    }
+    t.mutex.Unlock()
+    sort.Strings(names)
+    return names, nil
+} //end func List
+func (f *tieredFile) Write(p []byte) (int, error) {
+    if f.inMem {
+        pos, err := f.TempFile.Seek(0, io.SeekCurrent)
+        if err != nil { return 0, err }
+        t        := f.storage
+        t.mutex.Lock()
+        growth   := pos + int64(len(p)) - t.mem.size(f.name)
+        if growth < 0 { growth = 0 }
+        if t.used + growth <= t.budget {
+            t.used += growth
+            t.mutex.Unlock()
+        } else {
+            err = f.moveToBacking(pos)
+            t.mutex.Unlock()
+            if err != nil { return 0, err }
+        }
+    }
+    return f.TempFile.Write(p)
+} //end func Write
+func (f *tieredFile) Name() string { return f.name }
+func (f *tieredFile) moveToBacking(pos int64) error {
+    //Copies the memory file to the backing storage and carries on there at the same position; the storage must be locked
+    var(
+        fh  TempFile
+        err error
+    )
+    t := f.storage
+    if creator, away := t.backing.(awayCreator); away && len(f.avoid) > 0 {
+        var avoid []string                                        //backing names of the files moved out of memory
+        for _, name := range f.avoid {
+            if backingName, ok := t.spilled[name]; ok { avoid = append(avoid, backingName) }
+        }
+        fh, err = creator.createAway(f.prefix, avoid)
+    } else {
+        fh, err = t.backing.CreateTemp(f.prefix)
+    }
+    if err != nil { return err }
+    if _, err = f.TempFile.Seek(0, io.SeekStart); err == nil {
+        if _, err = io.Copy(fh, f.TempFile); err == nil { _, err = fh.Seek(pos, io.SeekStart) }
+    }
+    if err != nil {
+        fh.Close()
+        t.backing.Remove(fh.Name())
+        return err
+    }
+    f.TempFile.Close()
+    t.used -= t.mem.size(f.name)
+    t.mem.Remove(f.name)
+    t.spilled[f.name] = fh.Name()
+    f.TempFile, f.inMem = fh, false
+    return nil
+} //end func moveToBacking
+func (m *MemStorage) size(name string) int64 {
+    m.mutex.Lock()
+    data := m.files[name]
+    m.mutex.Unlock()
+    if data == nil { return 0 }
+    data.mutex.RLock()
+    defer data.mutex.RUnlock()
+    return int64(len(data.bytes))
+} //end func size
+////Run helpers
+func (r *sortRun) createTemp(prefix string, avoid ...string) (TempFile, string) {
+    //Creates a temporary file, off the devices of the files to avoid if the storage places its files
+    var(
+        fh  TempFile
+        err error
+    )
+    creator, away := r.storage.(awayCreator)
+    switch {
+        case r.deterministic:           fh, err = r.createSequential(prefix)
+        case away && len(avoid) > 0:    fh, err = creator.createAway(prefix, avoid)
+        default:                        fh, err = r.storage.CreateTemp(prefix)
+    }
+    if err != nil { haltTemp("CreateTemp", err) }
+    counted := &countedFile{TempFile:r.retrying(fh, nil, true), written:&r.counters.tempBytes, space:r.space, name:fh.Name()}
+    return r.limiter.track(counted), fh.Name()
+} //end func createTemp
+func (r *sortRun) openTemp(name string) TempFile {
+    fh, err := r.openStored(name)
+    if err != nil { halt("Open - " + err.Error()) }
+    return r.limiter.track(r.retrying(fh, func() (TempFile, error) { return r.openStored(name) }, true))
+} //end func openTemp
+func (r *sortRun) removeTemp(name string) {
+    if r.removeRun(name) { return }
+    r.storage.Remove(name)
+    r.space.release(name)
+} //end func removeTemp
+func listDir(dir, prefix, suffix string) ([]string, error) {
+    //Returns the sorted paths of the files of a directory whose names have the prefix & suffix. Unlike filepath.Glob, the
+    //directory is not read as a pattern, so that characters such as '[' in its path, e.g. a user name, are taken literally.
+    entries, err := os.ReadDir(dir)
+    if os.IsNotExist(err) { return nil, nil }
+    if err != nil { return nil, err }
+    var names []string
+    for _, entry := range entries {
+        name := entry.Name()
+        if strings.HasPrefix(name, prefix) && strings.HasSuffix(name, suffix) && len(name) >= len(prefix) + len(suffix) {
+            names = append(names, filepath.Join(dir, name))
+        }
+    }
+    sort.Strings(names)
+    return names, nil
+} //end func listDir
+func (r *sortRun) listTemp(prefix string) []string {
+    names, err := r.storage.List(prefix)
+    if err != nil { halt("List - " + err.Error()) }
+    r.fullerFirst(names)
+    return names
+} //end func listTemp
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file storage.go
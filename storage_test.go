@@ -0,0 +1,131 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     storage_test.go
+ * Overview:
+ *     tests of the discovery and replacement of the temporary files and run manifests, in directories of t.TempDir whose
+ *     names hold the pattern characters of filepath.Glob, as Windows user names often do.
+ * Functions:
+ *     TestOSStorageListing(t *testing.T)
+ *         Checks that the OS storage lists its files by native path, whatever the form of its directory.
+ *     TestSortInGlobDir(t *testing.T)
+ *         Checks that a sort merging its runs in such a directory succeeds and leaves no temporary file.
+ *     TestRunsInGlobDir(t *testing.T)
+ *         Checks that GenerateRuns and MergeRuns find their manifests in such a directory.
+ *     TestReplaceFile(t *testing.T)
+ *         Checks that a manifest is renamed over an existing one.
+ * History:
+ *     v1.73.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "bytes"
+    "io/ioutil"
+    "math/rand"
+    "os"
+    "path/filepath"
+    "runtime"
+    "strings"
+    "testing"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func TestOSStorageListing(t *testing.T) {
+    dir := globDir(t)
+    for _, form := range []string{dir, filepath.ToSlash(dir), dir + string(filepath.Separator)} {
+        storage := newOSStorage(form, sharedLimiter())
+        var created []string
+        for k := 0; k < 3; k++ {
+            fh, err := storage.CreateTemp("keys_test_")
+            if err != nil { t.Fatal(err) }
+            fh.Close()
+            created = append(created, fh.Name())
+        }
+        if err := ioutil.WriteFile(filepath.Join(dir, "other_keys_test_"), nil, 0600); err != nil { t.Fatal(err) }
+        listed, err := storage.List("keys_test_")
+        if err != nil { t.Fatal(err) }
+        if len(listed) != len(created) { t.Fatalf("%q: listed %q, created %q", form, listed, created) }
+        for _, name := range created {
+            if !contains(listed, name) { t.Fatalf("%q: %q created but not listed among %q", form, name, listed) }
+            if filepath.Dir(name) != dir { t.Fatalf("%q: %q is not a native path of %q", form, name, dir) }
+            if err := storage.Remove(name); err != nil { t.Fatal(err) }
+        }
+        if listed, _ := storage.List("keys_test_"); len(listed) != 0 { t.Fatalf("%q: %q left after removal", form, listed) }
+    }
+} //end func TestOSStorageListing
+func TestSortInGlobDir(t *testing.T) {
+    dir   := globDir(t)
+    input := randomInput(rand.New(rand.NewSource(1)), 2000)
+    inFile, outFile := filepath.Join(dir, "in.txt"), filepath.Join(dir, "out.txt")
+    if err := ioutil.WriteFile(inFile, []byte(input), 0644); err != nil { t.Fatal(err) }
+    var stats SortStats
+    sorter, err := NewSorter(WithFields("2,1"), WithKeysPerSort(100), WithTempDir(dir), WithStats(&stats))
+    if err != nil { t.Fatal(err) }
+    if err := sorter.Run(inFile, outFile); err != nil { t.Fatal(err) }
+    if stats.MergePasses == 0 { t.Fatal("the sort did not merge its runs") }
+    checkSorted(t, input, outFile, "2,1")
+    left, err := listDir(dir, "keys_", "")
+    if err != nil || len(left) > 0 { t.Fatalf("temporary files left in %q: %q (%v)", dir, left, err) }
+} //end func TestSortInGlobDir
+func TestRunsInGlobDir(t *testing.T) {
+    dir    := globDir(t)
+    runDir := filepath.Join(dir, "runs [0-9]")
+    if err := os.Mkdir(runDir, 0755); err != nil { t.Fatal(err) }
+    input  := randomInput(rand.New(rand.NewSource(2)), 2000)
+    inFile := filepath.Join(dir, "in.txt")
+    if err := ioutil.WriteFile(inFile, []byte(input), 0644); err != nil { t.Fatal(err) }
+    opts   := []Option{WithFields("2,1"), WithKeysPerSort(100), WithTempDir(dir)}
+    half   := int64(len(input) / 2)
+    for _, byteRange := range []ByteRange{{Start:0, End:half}, {Start:half, End:-1}} {
+        if err := GenerateRuns(inFile, byteRange, runDir, opts...); err != nil { t.Fatal(err) }
+    }
+    manifests, err := listDir(runDir, "", _runsManifest)
+    if err != nil || len(manifests) != 2 { t.Fatalf("manifests of %q: %q (%v)", runDir, manifests, err) }
+    outFile := filepath.Join(dir, "out.txt")
+    if err := MergeRuns(runDir, inFile, outFile, opts...); err != nil { t.Fatal(err) }
+    checkSorted(t, input, outFile, "2,1")
+} //end func TestRunsInGlobDir
+func TestReplaceFile(t *testing.T) {
+    dir      := globDir(t)
+    manifest := filepath.Join(dir, "run_0-10_1" + _runsManifest)
+    if err := ioutil.WriteFile(manifest, []byte("old"), 0600); err != nil { t.Fatal(err) }
+    if err := ioutil.WriteFile(manifest + ".tmp", []byte("new"), 0600); err != nil { t.Fatal(err) }
+    if err := replaceFile(manifest + ".tmp", manifest); err != nil { t.Fatal(err) }
+    data, err := ioutil.ReadFile(manifest)
+    if err != nil || string(data) != "new" { t.Fatalf("the manifest holds %q after its replacement (%v)", data, err) }
+    if _, err := os.Stat(manifest + ".tmp"); !os.IsNotExist(err) { t.Fatalf("the replacement was left behind (%v)", err) }
+} //end func TestReplaceFile
+//Private ----------------------------------------------------------------------------------------------------------------------
+func globDir(t *testing.T) string {
+    //Returns a directory of t.TempDir whose name holds pattern characters valid in the file names of the OS
+    name := "ann [it] {a,b}"
+    if runtime.GOOS != "windows" { name += " *?" }
+    dir := filepath.Join(t.TempDir(), name)
+    if err := os.Mkdir(dir, 0755); err != nil { t.Fatal(err) }
+    return dir
+} //end func globDir
+func randomInput(rng *rand.Rand, records int) string {
+    //Returns an input of random records per randomRecord
+    var input strings.Builder
+    for k := 0; k < records; k++ { input.WriteString(randomRecord(rng)) }
+    return input.String()
+} //end func randomInput
+func checkSorted(t *testing.T, input, outFile, usingFields string) {
+    //Fails the test unless the output file holds the records of the input as SortStrings orders them
+    t.Helper()
+    records := strings.SplitAfter(input, "\n")
+    records  = records[:len(records) - 1]                         //the empty string after the last line feed
+    if err := SortStrings(records, true, usingFields, "\t"); err != nil { t.Fatal(err) }
+    output, err := ioutil.ReadFile(outFile)
+    if err != nil { t.Fatal(err) }
+    if !bytes.Equal(output, []byte(strings.Join(records, ""))) { t.Fatalf("%s is not the sorted input", outFile) }
+} //end func checkSorted
+func contains(names []string, name string) bool {
+    for _, listed := range names {
+        if listed == name { return true }
+    }
+    return false
+} //end func contains
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file storage_test.go
@@ -0,0 +1,227 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     tempdirs.go
+ * Overview:
+ *     temporary files spread over an ordered list of directories, each used until its volume's free space falls to a floor,
+ *     the files of the fullest volumes being merged first, or used in turn, each merge writing to a device other than
+ *     that of its inputs.
+ * Functions:
+ *     WithTempDirs(dirs ...TempDir) Option
+ *         Option placing the temporary files in the first of several directories with room.
+ *     WithTempPlacement(mode TempPlacement) Option
+ *         Option choosing how the temporary files are placed over the directories of WithTempDirs.
+ * Types:
+ *     TempDir
+ *         Directory of temporary files and the free space to leave on its volume.
+ *     TempPlacement
+ *         Placement of the temporary files over several directories.
+ * History:
+ *     v1.80.0 - October 15, 2026 - Original release.
+ *     v1.81.0 - October 15, 2026 - Added WithTempPlacement.
+ *     v1.87.0 - October 15, 2026 - Fuller volumes first among the files of equal size.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "errors"
+    "fmt"
+    "math"
+    "path/filepath"
+    "sort"
+    "sync"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+type TempDir struct {
+    Path    string                                                //directory of the temporary files
+    MinFree int64                                                 //bytes of its volume to leave free, 0 for none
+}
+type TempPlacement int
+const(
+    PlaceFirstFit   TempPlacement = iota //first directory with room, the default
+    PlaceRoundRobin                      //directories in turn, the output of a merge on a device other than its inputs'
+)
+
+func WithTempDirs(dirs ...TempDir) Option {
+/*         Purpose : Places the temporary files in the first of several directories whose volume has room.
+ *       Arguments : dirs = the directories, in order of preference, e.g. a small fast volume before a large slow one.
+ *         Returns : The option.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : None.
+ *         Remarks : Each temporary file is created in the first directory whose volume has more free space than its
+ *                   MinFree, so that the files overflow to the next directories as the first ones fill up rather than
+ *                   failing with ENOSPC. A file is not moved once created, so the floors should allow for the largest
+ *                   file expected, about the bytes of all the keys for the last merge. When no directory has room, the
+ *                   sort fails with an error matching ErrTempSpace. Of the files of equal size, the last merge pass
+ *                   takes those of the directories with the least room first, freeing their volumes first, except with
+ *                   WithDeterministic, which keeps them in order of name. The temporary files are listed, and removed on
+ *                   a failure, in all the directories. The free space is that reported by statfs, on Linux, macOS,
+ *                   FreeBSD and DragonFly; elsewhere it is unknown, every directory being deemed to have room so that
+ *                   the first one is always used. The option takes precedence over WithTempDir, while WithTempStorage
+ *                   and WithTempNextToOutput take precedence over it.
+ *         History : v1.80.0 - October 15, 2026 - Original release.
+ *                   v1.87.0 - October 15, 2026 - Fuller volumes first among the files of equal size.
+ */
+    return func(s *Sorter) { s.tempDirs = dirs }
+} //end func WithTempDirs
+func WithTempPlacement(mode TempPlacement) Option {
+/*         Purpose : Chooses how the temporary files are placed over the directories of WithTempDirs.
+ *       Arguments : mode = the placement, PlaceFirstFit by default.
+ *         Returns : The option.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : None.
+ *         Remarks : PlaceRoundRobin creates the run files in each directory with room in turn, and the output of each
+ *                   merge in the next directory with room whose device holds none of the merged files, so that a
+ *                   merge reads from some devices while it writes to another rather than sharing one device's
+ *                   throughput. The directories are told apart by the device of their volume, two directories of the
+ *                   same volume counting as one device, and by their path on the systems not reporting devices. When
+ *                   every device with room holds some of the merged files, e.g. with a single directory, the output
+ *                   goes to the next directory with room, as a run would. A merge whose output first lies in memory
+ *                   per WithInMemorySpillThreshold is placed the same way once moved out of it, while with
+ *                   WithDeterministic the merge outputs are placed in turn like the runs. The verbose echo gives the
+ *                   directory of each run and merged file when there are several directories. The floors of
+ *                   WithTempDirs apply in both modes.
+ *         History : v1.81.0 - October 15, 2026 - Original release.
+ */
+    return func(s *Sorter) { s.tempPlacement = mode }
+} //end func WithTempPlacement
+//Private ----------------------------------------------------------------------------------------------------------------------
+type awayCreator interface {
+    createAway(prefix string, avoid []string) (TempFile, error) //creates a file as CreateTemp does, off the devices of avoid
+}
+type dirsStorage struct {
+    dirs    []osStorage                                           //storages of the directories, in order of preference
+    floors  []int64                                               //free space to leave on their volumes
+    devices []string                                              //devices of their volumes, or their paths if unknown
+    mode    TempPlacement
+    mutex   sync.Mutex
+    next    int                                                   //directory tried first in turn
+}
+func newDirsStorage(dirs []TempDir, mode TempPlacement, limiter *fileLimiter) *dirsStorage {
+    //Returns the storage spreading the temporary files over the directories
+    d := &dirsStorage{mode:mode}
+    for _, dir := range dirs {
+        storage := newOSStorage(dir.Path, limiter)
+        device  := "path " + storage.dir
+        if dev, known := deviceOf(storage.dir); known { device = fmt.Sprint("device ", dev) }
+        d.dirs    = append(d.dirs, storage)
+        d.floors  = append(d.floors, dir.MinFree)
+        d.devices = append(d.devices, device)
+    }
+    return d
+} //end func newDirsStorage
+func (d *dirsStorage) CreateTemp(prefix string) (TempFile, error) { return d.createAway(prefix, nil) }
+func (d *dirsStorage) createAway(prefix string, avoid []string) (TempFile, error) {
+    storage, err := d.pick(avoid)
+    if err != nil { return nil, err }
+    return storage.CreateTemp(prefix)
+} //end func createAway
+func (d *dirsStorage) createNamed(name string) (TempFile, error) {
+    storage, err := d.pick(nil)
+    if err != nil { return nil, err }
+    return storage.createNamed(name)
+} //end func createNamed
+func (d *dirsStorage) Open(name string) (TempFile, error) { return d.owner(name).Open(name) }
+func (d *dirsStorage) Remove(name string) error           { return d.owner(name).Remove(name) }
+func (d *dirsStorage) List(prefix string) ([]string, error) {
+    var names []string
+    for _, storage := range d.dirs {
+        listed, err := storage.List(prefix)
+        if err != nil { return nil, err }
+        names = append(names, listed...)
+    }
+    sort.Strings(names)
+    return names, nil
+} //end func List
+func (d *dirsStorage) pick(avoid []string) (osStorage, error) {
+    //Returns the storage of the directory with room for a new file, the first one or, in turn, the next one off the devices
+    //of the files to avoid if possible, or an error matching ErrTempSpace if none has room
+    if d.mode == PlaceFirstFit {
+        for k, storage := range d.dirs {
+            if d.hasRoom(k) { return storage, nil }
+        }
+    } else {
+        busy := map[string]bool{}                                 //devices of the files to avoid
+        for _, name := range avoid {
+            if k := d.index(name); k >= 0 { busy[d.devices[k]] = true }
+        }
+        d.mutex.Lock()
+        defer d.mutex.Unlock()
+        fallback := -1                                            //next directory with room, whatever its device
+        for n := 0; n < len(d.dirs); n++ {
+            k := (d.next + n) % len(d.dirs)
+            if !d.hasRoom(k) { continue }
+            if fallback < 0 { fallback = k }
+            if !busy[d.devices[k]] {
+                d.next = (k + 1) % len(d.dirs)
+                return d.dirs[k], nil
+            }
+        }
+        if fallback >= 0 {
+            d.next = (fallback + 1) % len(d.dirs)
+            return d.dirs[fallback], nil
+        }
+    }
+    return osStorage{}, &kindError{kind:ErrTempSpace,
+                                   err:errors.New("no temporary directory has more free space than its floor")}
+} //end func pick
+func (d *dirsStorage) hasRoom(k int) bool {
+    //Returns whether the volume of a directory has more free space than its floor, or if its free space is unknown
+    free, known := freeSpace(d.dirs[k].dir)
+    return !known || free > d.floors[k]
+} //end func hasRoom
+func (d *dirsStorage) index(name string) int {
+    //Returns the index of the directory holding a file, -1 if none does
+    dir := filepath.Dir(name)
+    for k, storage := range d.dirs {
+        if storage.dir == dir { return k }
+    }
+    return -1
+} //end func index
+func (d *dirsStorage) owner(name string) osStorage {
+    //Returns the storage of the directory holding a file, the first one if none does
+    if k := d.index(name); k >= 0 { return d.dirs[k] }
+    return d.dirs[0]
+} //end func owner
+func (c *cipherStorage) createAway(prefix string, avoid []string) (TempFile, error) {
+    creator, away := c.TempStorage.(awayCreator)
+    if !away { return c.CreateTemp(prefix) }
+    fh, err := creator.createAway(prefix, avoid)
+    if err != nil { return nil, err }
+    return c.register(fh)
+} //end func createAway
+func (t *tieredStorage) createAway(prefix string, avoid []string) (TempFile, error) {
+    fh, err := t.CreateTemp(prefix)
+    if err == nil { fh.(*tieredFile).avoid = avoid }
+    return fh, err
+} //end func createAway
+func (r *sortRun) fullerFirst(names []string) {
+    //Orders the temporary files so that those of the directories whose volumes have the least room come first, the others,
+    //e.g. held in memory, keeping their order at the end
+    if len(r.tempDirs) < 2 || r.deterministic { return }
+    rooms := map[string]int64{}
+    for _, dir := range r.tempDirs {
+        room := int64(math.MaxInt64)
+        if free, known := freeSpace(dir.Path); known { room = free - dir.MinFree }
+        rooms[filepath.Clean(dir.Path)] = room
+    }
+    roomOf := func(name string) int64 {
+        if room, ok := rooms[filepath.Dir(name)]; ok { return room }
+        return math.MaxInt64
+    }
+    sort.SliceStable(names, func(i, j int) bool { return roomOf(names[i]) < roomOf(names[j]) })
+} //end func fullerFirst
+func (r *sortRun) placement(name string) string {
+    //Returns the directory of a temporary file for the verbose echo, if there are several directories
+    if len(r.tempDirs) < 2 { return "" }
+    dir := filepath.Dir(name)
+    for _, tempDir := range r.tempDirs {
+        if filepath.Clean(tempDir.Path) == dir { return " in " + dir }
+    }
+    return ""
+} //end func placement
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file tempdirs.go
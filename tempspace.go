@@ -0,0 +1,116 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     tempspace.go
+ * Overview:
+ *     cap on the temporary space of a sort, checked against its estimate before the runs are generated and enforced on
+ *     the bytes actually written while it runs.
+ * Functions:
+ *     WithMaxTempSpace(bytes int64) Option
+ *         Option failing the sorts whose temporary files would hold more than bytes at once.
+ * History:
+ *     v1.79.0 - October 15, 2026 - Original release.
+ *     v1.88.0 - October 15, 2026 - The bytes & files present counted without a cap too, for their peaks.
+ *     v1.98.0 - October 15, 2026 - The bytes present also counted against the cap of a Pool.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "fmt"
+    "sync"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func WithMaxTempSpace(bytes int64) Option {
+/*         Purpose : Fails the sorts whose temporary files would hold more than a number of bytes at once.
+ *       Arguments : bytes = the most bytes of temporary files present at once, 0 for no cap, the default.
+ *         Returns : The option.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : None.
+ *         Remarks : Before the keys of an input are generated, its peak temporary space is worked out as Estimate does and
+ *                   the sort fails at once if it exceeds the cap, no run having been written. While the sort runs, the bytes
+ *                   written to each temporary file are counted, those of a removed file being given back, and the sort fails
+ *                   as soon as the files present exceed the cap, e.g. when the input's records do not resemble its sample.
+ *                   Its temporary files are then removed, as on any failure. Both errors match ErrTempSpace and state the
+ *                   estimate, the cap and the bytes present, e.g. "estimated 900 bytes, limit 1000 bytes, used 1024 bytes",
+ *                   or "not estimated" for the files of a SpillQueue or of a spool. The bytes counted are those stored, i.e.
+ *                   after the compression of WithTempCodec, and include the files held in memory per
+ *                   WithInMemorySpillThreshold and the spooled inputs of SortChan and RunFS. The cap applies to every
+ *                   temporary file of a sort, SpillQueue and Reverse included, the estimate to the inputs keyed by the Sort
+ *                   family only. Inputs sorted in memory use no temporary space.
+ *         History : v1.79.0 - October 15, 2026 - Original release.
+ */
+    return func(s *Sorter) { s.maxTemp = bytes }
+} //end func WithMaxTempSpace
+//Private ----------------------------------------------------------------------------------------------------------------------
+type tempSpace struct {
+    mutex     sync.Mutex
+    limit     int64                                               //cap on the bytes of temporary files present, 0 for none
+    planned   bool                                                //peak worked out before the runs
+    estimated int64                                               //the peak, if planned
+    used      int64                                               //bytes of the temporary files present
+    peak      int64                                               //most bytes of temporary files present at once so far
+    peakFiles int                                                 //most temporary files present at once so far
+    files     map[string]int64                                    //bytes written to each file present, by name
+    owner     string                                              //setter of the cap, for its error, "" for WithMaxTempSpace
+    pool      *tempSpace                                          //space shared with the other sorts of a Pool, nil if none
+}
+func (r *sortRun) planTempSpace(inFile string, size int64) {
+    //Halts if the estimated peak temporary space of the input, added to the bytes already present, exceeds the cap
+    if r.space.limit == 0 { return }
+    peak := r.estimate(EstimateInput{File:inFile, Size:size}).PeakTempBytes
+    r.space.mutex.Lock()
+    r.space.planned, r.space.estimated = true, peak
+    err := r.space.exceeded(peak + r.space.used)
+    r.space.mutex.Unlock()
+    if r.verbose { fmt.Println("func Sort - estimated peak temporary space =", peak, "bytes, limit =", r.space.limit) }
+    if err != nil { panic(haltError{err}) }
+} //end func planTempSpace
+func (t *tempSpace) grow(name string, n int) {
+    //Counts the bytes written to a temporary file, halting once the files present exceed the cap
+    if t == nil || n == 0 { return }
+    t.mutex.Lock()
+    t.files[name] += int64(n)
+    t.used        += int64(n)
+    if t.used > t.peak { t.peak = t.used }
+    if len(t.files) > t.peakFiles { t.peakFiles = len(t.files) }
+    err           := t.exceeded(t.used)
+    t.mutex.Unlock()
+    if err != nil { panic(haltError{err}) }
+    t.pool.grow(name, n)
+} //end func grow
+func (t *tempSpace) release(name string) {
+    //Gives back the bytes of a removed temporary file
+    if t == nil { return }
+    t.mutex.Lock()
+    t.used -= t.files[name]
+    delete(t.files, name)
+    t.mutex.Unlock()
+    t.pool.release(name)
+} //end func release
+func (t *tempSpace) exceeded(bytes int64) error {
+    //Returns the error of a cap exceeded by a number of bytes, nil if within it or without a cap; to be called with the
+    //mutex held
+    if t.limit == 0 || bytes <= t.limit { return nil }
+    estimate, owner := "not estimated", t.owner
+    if t.planned { estimate = fmt.Sprintf("estimated %d bytes", t.estimated) }
+    if owner == "" { owner = "WithMaxTempSpace" }
+    return &kindError{kind:ErrTempSpace, err:fmt.Errorf("mergesort: temporary space over the limit of %s - " +
+                      "%s, limit %d bytes, used %d bytes", owner, estimate, t.limit, t.used)}
+} //end func exceeded
+func (t *tempSpace) peaks() (bytes int64, files int) {
+    //Returns the most bytes and the most temporary files present at once so far
+    if t == nil { return 0, 0 }
+    t.mutex.Lock()
+    defer t.mutex.Unlock()
+    return t.peak, t.peakFiles
+} //end func peaks
+func (t *tempSpace) present() (bytes int64, files int) {
+    //Returns the bytes and the number of the temporary files present
+    t.mutex.Lock()
+    defer t.mutex.Unlock()
+    return t.used, len(t.files)
+} //end func present
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file tempspace.go
@@ -0,0 +1,134 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     thousands.go
+ * Overview:
+ *     numeric index fields whose integer parts are grouped by thousands, e.g. "1,234,567" or "12 345", or whose decimal
+ *     separator is a comma, e.g. "1.234,56".
+ * Functions:
+ *     WithThousandsSeparator(column int, group Grouping) Option
+ *         Option stripping the thousands separators of a numeric field before it is parsed.
+ *     WithDecimalSeparator(column int, decimal rune, grouped bool) Option
+ *         Option setting the decimal separator of a numeric field.
+ * Types:
+ *     Grouping
+ *         Thousands separator of a numeric field.
+ * History:
+ *     v1.51.0 - October 15, 2026 - Original release.
+ *     v1.53.0 - October 15, 2026 - Added WithDecimalSeparator.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "fmt"
+    "strings"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+type Grouping rune
+const(
+    GroupNone       Grouping = 0    //no thousands separator, the default
+    GroupComma      Grouping = ','  //e.g. "1,234,567.89"
+    GroupSpace      Grouping = ' '  //e.g. "1 234 567.89"
+    GroupApostrophe Grouping = '\'' //e.g. "1'234'567.89"
+    GroupPoint      Grouping = '.'  //e.g. "1.234.567,89", with a decimal comma only
+)
+
+func WithThousandsSeparator(column int, group Grouping) Option {
+/*         Purpose : Strips the thousands separators of a numeric field before it is parsed.
+ *       Arguments : column = number of the field, the first being 1. Must be an index field of type FieldNumeric.
+ *                   group  = the thousands separator.
+ *         Returns : The option.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : None.
+ *         Remarks : The separators may only appear in the integer part of a value, after any sign, and must split it
+ *                   into a leading group of 1 to 3 digits followed by groups of exactly 3, e.g. "-1,234.5" or
+ *                   "+12,345e3". Any other placement, e.g. "1,23,456", "1234,567" or "1,234.567,8", makes the value one
+ *                   that does not parse rather than a different number. Values without separators parse as usual. The
+ *                   decimal separator is a period unless WithDecimalSeparator sets a comma. A later option for the
+ *                   same field replaces an earlier one, and GroupNone removes it.
+ *         History : v1.51.0 - October 15, 2026 - Original release.
+ *                   v1.53.0 - October 15, 2026 - Added GroupPoint.
+ */
+    return func(s *Sorter) {
+        if s.groupings == nil { s.groupings = map[int]Grouping{} }
+        s.groupings[column - 1] = group
+    }
+} //end func WithThousandsSeparator
+func WithDecimalSeparator(column int, decimal rune, grouped bool) Option {
+/*         Purpose : Sets the decimal separator of a numeric field.
+ *       Arguments : column  = number of the field, the first being 1. Must be an index field of type FieldNumeric.
+ *                   decimal = the decimal separator, '.' or ','.
+ *                   grouped = boolean flag for taking the other character as the thousands separator, as
+ *                             WithThousandsSeparator does.
+ *         Returns : The option.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : None.
+ *         Remarks : With a decimal comma, "3,14" parses as 3.14 and "1,234" as 1.234, never as 1234, while a period
+ *                   makes the value one that does not parse unless it is the thousands separator, e.g. "1.234,56" once
+ *                   grouped. The values enter the composite key as with a decimal point, so fields written with
+ *                   either convention sort alike. A later WithThousandsSeparator for the same field replaces the
+ *                   grouping, which may not use the decimal separator.
+ *         History : v1.53.0 - October 15, 2026 - Original release.
+ */
+    return func(s *Sorter) {
+        if s.decimals == nil { s.decimals = map[int]rune{} }
+        s.decimals[column - 1] = decimal
+        if !grouped { return }
+        if s.groupings == nil { s.groupings = map[int]Grouping{} }
+        s.groupings[column - 1] = GroupComma
+        if decimal == ',' { s.groupings[column - 1] = GroupPoint }
+    }
+} //end func WithDecimalSeparator
+//Private ----------------------------------------------------------------------------------------------------------------------
+func (s *Sorter) checkGroupings() {
+    //Halts on a grouped field or a decimal separator that is not for a numeric index field, on an unknown thousands or
+    //decimal separator, or on the two being the same
+    for colIdx, group := range s.groupings {
+        if !s.isIndexField(colIdx) { haltKind(ErrBadFieldSpec, fmt.Sprintf("the grouped column %d is not an index field",
+                                                                          colIdx + 1), nil) }
+        switch group {
+            case GroupNone, GroupComma, GroupSpace, GroupApostrophe, GroupPoint:
+            default: halt(fmt.Sprintf("the thousands separator of column %d is unknown", colIdx + 1))
+        }
+        if group != GroupNone && s.fieldTypes[colIdx] != FieldNumeric {
+            halt(fmt.Sprintf("the grouped column %d is not of type FieldNumeric", colIdx + 1))
+        }
+        if rune(group) == s.decimalSeparator(colIdx) {
+            halt(fmt.Sprintf("the thousands and decimal separators of column %d are the same", colIdx + 1))
+        }
+    }
+    for colIdx, decimal := range s.decimals {
+        if !s.isIndexField(colIdx) { haltKind(ErrBadFieldSpec, fmt.Sprintf("the decimal column %d is not an index field",
+                                                                          colIdx + 1), nil) }
+        if decimal != '.' && decimal != ',' { halt(fmt.Sprintf("the decimal separator of column %d is unknown", colIdx + 1)) }
+        if s.fieldTypes[colIdx] != FieldNumeric {
+            halt(fmt.Sprintf("the decimal column %d is not of type FieldNumeric", colIdx + 1))
+        }
+    }
+} //end func checkGroupings
+func (s *Sorter) decimalSeparator(colIdx int) rune {
+    //Returns the decimal separator of a numeric field
+    if decimal, ok := s.decimals[colIdx]; ok { return decimal }
+    return '.'
+} //end func decimalSeparator
+func ungroup(value string, group Grouping, decimal rune) (string, bool) {
+    //Strips the thousands separators of the integer part of a trimmed number, and returns false if one is misplaced
+    sep := string(group)
+    if group == GroupNone || !strings.Contains(value, sep) { return value, true }
+    sign := ""
+    if strings.HasPrefix(value, "+") || strings.HasPrefix(value, "-") { sign, value = value[:1], value[1:] }
+    end  := strings.IndexAny(value, string(decimal) + "eE")
+    if end < 0 { end = len(value) }
+    if strings.Contains(value[end:], sep) { return "", false }
+    groups := strings.Split(value[:end], sep)
+    for k, digits := range groups {
+        if strings.Trim(digits, "0123456789") != "" || len(digits) > 3 { return "", false }
+        if len(digits) < 3 && (k > 0 || digits == "")               { return "", false }
+    }
+    return sign + strings.Join(groups, "") + value[end:], true
+} //end func ungroup
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file thousands.go
@@ -0,0 +1,50 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     thousands_test.go
+ * Overview:
+ *     tests of numeric fields written with a decimal comma and with a decimal point side by side in the same file.
+ * Functions:
+ *     TestDecimalConventions(t *testing.T)
+ *         Checks that "1,234" is 1.234 in the field of decimal commas and 1234 in the field grouped by commas.
+ * History:
+ *     v1.53.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "fmt"
+    "strings"
+    "testing"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func TestDecimalConventions(t *testing.T) {
+    //Field 1 per the European convention, field 2 per the English one, then the record's index
+    records := []string{"1,234\t1,234\t0", "2\t2\t1", "-3,5\t-3.5\t2", "1.000,5\t1,000.5\t3", "0,75\t0.75\t4",
+                        "12,5\t12,500\t5", "1,2340\t1.234\t6"}
+    opts    := []Option{WithFieldType(1, FieldNumeric), WithDecimalSeparator(1, ',', true),
+                        WithFieldType(2, FieldNumeric), WithThousandsSeparator(2, GroupComma)}
+    tests   := []struct {
+        fields  string
+        sortAsc bool
+        order   []int                                             //indexes of the records in output order
+    }{
+        {"1,2", true, []int{2, 4, 6, 0, 1, 5, 3}},                //1,234 = 1,2340 = 1.234, then 1.234 ahead of 1234
+        {"1,2", false, []int{3, 5, 1, 0, 6, 4, 2}},
+        {"2,1", true, []int{2, 4, 6, 1, 3, 0, 5}},                //1,234 = 1234
+        {"2,1", false, []int{5, 0, 3, 1, 6, 4, 2}},
+    }
+    var stats SortStats
+    for _, test := range tests {
+        name     := fmt.Sprintf("fields=%s/asc=%v", test.fields, test.sortAsc)
+        expected := ""
+        for _, k := range test.order { expected += records[k] + "\n" }
+        output   := sortBytes(t, strings.Join(records, "\n") + "\n",
+                              append(opts, WithFields(test.fields), WithAscending(test.sortAsc), WithStats(&stats))...)
+        if string(output) != expected { t.Errorf("%s: output\n%s\nexpected\n%s", name, output, expected) }
+        if stats.InvalidValues != 0 { t.Errorf("%s: %d values do not parse", name, stats.InvalidValues) }
+    }
+} //end func TestDecimalConventions
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file thousands_test.go
@@ -0,0 +1,73 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     tracing.go
+ * Overview:
+ *     runtime/trace regions and pprof labels delimiting the phases, merge passes and workers of a sort, so that execution
+ *     traces and CPU profiles can be broken down by them.
+ * Functions:
+ *     WithTracing(ctx context.Context) Option
+ *         Option marking the phases of each sort with trace regions and profiler labels.
+ * History:
+ *     v1.83.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "context"
+    "runtime/pprof"
+    "runtime/trace"
+    "strconv"
+    "sync/atomic"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func WithTracing(ctx context.Context) Option {
+/*         Purpose : Marks the phases of each sort with runtime/trace regions and pprof labels.
+ *       Arguments : ctx = the context of the regions and labels, e.g. one holding a trace task or the caller's own labels;
+ *                         nil for context.Background().
+ *         Returns : The option.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : None.
+ *         Remarks : Each phase timed in SortStats.Phases but the merge passes, i.e. the width scan, the key generation and
+ *                   the output, runs within a region named "mergesort: " and the phase, on a goroutine labelled with
+ *                   "phase" set to it. Each merge runs within a region "mergesort: merge", labelled with "phase" set to
+ *                   "merge" and "pass" to its pass, on the merging goroutine or, with WithDeterministic and for MergeRuns,
+ *                   on the caller's. The goroutines fetching the records of the output per WithParallelism are labelled
+ *                   with "worker", numbered from 0, or set to "feeder" for the one handing them the keys. The regions
+ *                   belong to the trace task of ctx, if any, and the labels are added to those of ctx, to which the
+ *                   goroutine is reset at the end of each phase, any other label set by the caller being dropped; the
+ *                   regions of a phase failing are left open. "go tool trace" then shows the regions of a sort and "go
+ *                   tool pprof -tagfocus phase=output", e.g., its share of the profile. Without the option, the default,
+ *                   a sort sets no label and starts no region.
+ *         History : v1.83.0 - October 15, 2026 - Original release.
+ */
+    if ctx == nil { ctx = context.Background() }
+    return func(s *Sorter) { s.traceCtx = ctx }
+} //end func WithTracing
+//Private ----------------------------------------------------------------------------------------------------------------------
+func (r *sortRun) traced(phase string, labels ...string) func() {
+    //Opens the trace region of a phase and labels the goroutine with it, returning the function closing the region and
+    //resetting the labels to those of the tracing context; does nothing without tracing
+    if r.traceCtx == nil { return func() {} }
+    ctx    := pprof.WithLabels(r.traceCtx, pprof.Labels(append([]string{"phase", phase}, labels...)...))
+    pprof.SetGoroutineLabels(ctx)
+    region := trace.StartRegion(ctx, "mergesort: " + phase)
+    return func() {
+        region.End()
+        pprof.SetGoroutineLabels(r.traceCtx)
+    }
+} //end func traced
+func (r *sortRun) tracedMerge() func() {
+    //Opens the trace region of a merge, labelled with its pass
+    if r.traceCtx == nil { return func() {} }
+    return r.traced("merge", "pass", strconv.FormatInt(atomic.LoadInt64(&r.counters.passes), 10))
+} //end func tracedMerge
+func (r *sortRun) labelWorker(phase, worker string) {
+    //Labels a goroutine of a phase with its worker id, if tracing
+    if r.traceCtx == nil { return }
+    pprof.SetGoroutineLabels(pprof.WithLabels(r.traceCtx, pprof.Labels("phase", phase, "worker", worker)))
+} //end func labelWorker
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file tracing.go
@@ -0,0 +1,148 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     trailer.go
+ * Overview:
+ *     trailers of the composite-key files, giving their number of keys and checksum, so that a key file short-written or
+ *     altered fails the sort rather than silently losing records.
+ * History:
+ *     v1.58.0 - October 15, 2026 - Original release.
+ *     v1.92.0 - October 15, 2026 - Buffered key writers, also writing keys held as bytes.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "bufio"
+    "bytes"
+    "errors"
+    "fmt"
+    "hash"
+    "hash/crc32"
+    "io"
+    "strconv"
+    "strings"
+)
+//Private ----------------------------------------------------------------------------------------------------------------------
+const(
+    _trailerMagic = "mergesort-trailer-v1" //first part of the last line of a key file
+    _trailerMax   = 64                     //longest trailer, including its line feed
+    _keyBuffer    = 64 << 10               //bytes of keys buffered by a key writer
+)
+type keyWriter struct {
+    writer  *bufio.Writer                  //buffer of the keys, flushed to the file or the codec's encoder
+    encoder io.WriteCloser                 //codec's encoder of the keys, nil if none
+    line    []byte                         //the key being written, with its line feed
+    count   int                            //number of keys written
+    hash    hash.Hash32                    //CRC-32 checksum of the keys written, with their line feeds
+}
+func (r *sortRun) newKeyWriter(writer io.Writer) *keyWriter {
+    //Returns the writer of the keys of a new key file, encoded by the run's codec if any
+    w := &keyWriter{hash:crc32.NewIEEE()}
+    if r.codec != nil {
+        w.encoder = r.encodeKeys(writer)
+        writer    = w.encoder
+    }
+    w.writer = bufio.NewWriterSize(writer, _keyBuffer)
+    return w
+} //end func newKeyWriter
+func (w *keyWriter) write(key string) {
+    //Writes a key, followed by a line feed
+    w.line = append(append(w.line[:0], key...), '\n')
+    w.put()
+} //end func write
+func (w *keyWriter) writeBytes(key []byte) {
+    //Writes a key held as bytes, followed by a line feed, without retaining them
+    w.line = append(append(w.line[:0], key...), '\n')
+    w.put()
+} //end func writeBytes
+func (w *keyWriter) put() {
+    //Buffers the line of a key and adds it to the checksum
+    w.hash.Write(w.line)
+    w.count++
+    w.writer.Write(w.line)
+} //end func put
+func (w *keyWriter) close() {
+    //Writes the trailer and flushes the keys, to be called once all the keys are written
+    fmt.Fprintf(w.writer, "%s%s%d%s%08x\n", _trailerMagic, _asciiGS, w.count, _asciiGS, w.hash.Sum32())
+    if err := w.writer.Flush(); err != nil { haltTemp("writer.Flush", err) }
+    if w.encoder != nil {
+        if err := w.encoder.Close(); err != nil { haltTemp("encoder.Close", err) }
+    }
+} //end func close
+func (r *sortRun) openKeys(name string) (TempFile, *bufio.Scanner) {
+    //Opens a key file, decoded by the run's codec if encoded, halting if its trailer is missing, and returns it with a scanner
+    //of its keys that fails on reaching the trailer if the keys read do not match it
+    fh := r.decodeKeys(r.openTemp(name), name)
+    if _, decoded := fh.(*decodedFile); !decoded && !hasTrailer(fh) { //decoded trailers checked by the scanner only
+        fh.Close()
+        haltKind(ErrCorruptKeys, "the key file " + name + " is truncated: its trailer is missing", nil)
+    }
+    scanner := bufio.NewScanner(fh)
+    scanner.Buffer(nil, bufio.MaxScanTokenSize << 8)
+    scanner.Split(trailedLines(name))
+    return fh, scanner
+} //end func openKeys
+func hasTrailer(fh io.ReadSeeker) bool {
+    //Returns whether the file ends with a trailer, leaving it positioned at its start
+    size, err := fh.Seek(0, io.SeekEnd)
+    if err != nil { halt("Seek - " + err.Error()) }
+    if size == 0 { return false }
+    n := int64(_trailerMax)
+    if n > size { n = size }
+    tail := make([]byte, n)
+    if _, err = fh.Seek(size - n, io.SeekStart); err == nil { _, err = io.ReadFull(fh, tail) }
+    if err == nil { _, err = fh.Seek(0, io.SeekStart) }
+    if err != nil { halt("reading the trailer - " + err.Error()) }
+    k := bytes.LastIndexByte(tail[:len(tail) - 1], '\n')
+    if k < 0 && n < size { return false }
+    _, _, ok := parseTrailer(tail[k + 1:])
+    return ok
+} //end func hasTrailer
+func parseTrailer(line []byte) (count int, checksum uint32, ok bool) {
+    //Returns the number of keys and the checksum given by a trailer, with its line feed
+    parts := strings.Split(strings.TrimSuffix(string(line), "\n"), _asciiGS)
+    if len(parts) != 3 || parts[0] != _trailerMagic || !bytes.HasSuffix(line, []byte("\n")) { return 0, 0, false }
+    count, err1 := strconv.Atoi(parts[1])
+    sum, err2   := strconv.ParseUint(parts[2], 16, 32)
+    return count, uint32(sum), err1 == nil && err2 == nil
+} //end func parseTrailer
+func trailedLines(name string) bufio.SplitFunc {
+    //Returns the split function of a key file, yielding its keys without their line feeds and checking their number and
+    //checksum against the trailer of the file
+    var(
+        count int                                                 //number of keys read
+        hash  = crc32.NewIEEE()                                   //checksum of the keys read
+        ended bool                                                //trailer read
+    )
+    corrupt := func(problem string) error {
+        return &kindError{kind:ErrCorruptKeys, err:errors.New("mergesort: the key file " + name + " " + problem)}
+    }
+    return func(data []byte, atEOF bool) (int, []byte, error) {
+        if ended {
+            if len(data) > 0 { return 0, nil, corrupt("continues past its trailer") }
+            return 0, nil, nil
+        }
+        k := bytes.IndexByte(data, '\n')
+        switch {
+            case k < 0 && atEOF:
+                return 0, nil, corrupt("is truncated: its trailer is missing")
+            case k < 0 || (k == len(data) - 1 && !atEOF):              //line incomplete, or possibly the trailer
+                return 0, nil, nil
+            case k == len(data) - 1:                                    //last line: the trailer
+                written, checksum, ok := parseTrailer(data)
+                if !ok { return 0, nil, corrupt("is truncated: its trailer is missing") }
+                if written != count || checksum != hash.Sum32() {
+                    return 0, nil, corrupt(fmt.Sprintf("is corrupt: %d keys read with checksum %08x, %d written with " +
+                                                       "checksum %08x", count, hash.Sum32(), written, checksum))
+                }
+                ended = true
+                return len(data), nil, nil
+        }
+        hash.Write(data[:k + 1])
+        count++
+        return k + 1, data[:k], nil
+    }
+} //end func trailedLines
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file trailer.go
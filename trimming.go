@@ -0,0 +1,56 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     trimming.go
+ * Overview:
+ *     trimming of the records before they are split into fields.
+ * Functions:
+ *     WithTrimming(mode TrimMode) Option
+ *         Option setting what is trimmed from the records before they are split into fields.
+ * Types:
+ *     TrimMode
+ *         Trimming applied to the records before they are split into fields.
+ * History:
+ *     v1.25.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "strings"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+type TrimMode int
+const(
+    TrimSpaces  TrimMode = iota //spaces, carriage returns & newlines at both ends, the default
+    TrimNewline                 //the line terminator, "\n" or "\r\n"
+    TrimNone                    //the "\n" ending the line only
+)
+
+func WithTrimming(mode TrimMode) Option {
+/*         Purpose : Sets what is trimmed from the records before they are split into fields.
+ *       Arguments : mode = the trimming.
+ *         Returns : The option.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : None.
+ *         Remarks : The trimming applies alike to the width scan and to the key generation. With TrimNewline or
+ *                   TrimNone, leading spaces belong to the first field and trailing ones to the last, and a record
+ *                   holding only spaces is no longer blank, so it is sorted rather than dropped. With TrimNone, a
+ *                   carriage return belongs to the last field. The records are output as read whatever the mode.
+ *         History : v1.25.0 - October 15, 2026 - Original release.
+ */
+    return func(s *Sorter) { s.trim = mode }
+} //end func WithTrimming
+//Private ----------------------------------------------------------------------------------------------------------------------
+func (s *Sorter) trimRecord(record string) string {
+    //Returns the part of a record that is split into fields, its first line if grouped
+    record = s.firstLine(record)
+    switch s.trim {
+        case TrimNewline: return strings.TrimSuffix(strings.TrimSuffix(record, "\n"), "\r")
+        case TrimNone:    return strings.TrimSuffix(record, "\n")
+    }
+    return strings.Trim(record, " \r\n")
+} //end func trimRecord
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file trimming.go
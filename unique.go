@@ -0,0 +1,98 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     unique.go
+ * Overview:
+ *     check that the index fields of the output records are unique, made while the output is written, the duplicates either
+ *     failing the sort or merely counted.
+ * Functions:
+ *     WithExpectUnique() Option
+ *         Option checking that no two output records have equal index fields.
+ *     WithUniqueMode(mode UniqueMode) Option
+ *         Option setting whether the duplicates found by WithExpectUnique fail the sort.
+ * Types:
+ *     UniqueMode
+ *         Outcome of the duplicates found by WithExpectUnique.
+ * History:
+ *     v1.96.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "fmt"
+    "strings"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+type UniqueMode int
+const(
+    UniqueFail  UniqueMode = iota //duplicates failing the sort with ErrDuplicateKeys, the default
+    UniqueCount                   //duplicates counted in SortStats.DuplicateKeys only
+)
+
+func WithExpectUnique() Option {
+/*         Purpose : Checks that no two output records have equal index fields.
+ *       Arguments : None.
+ *         Returns : The option.
+ * Externals -  In : _duplicatesQuoted
+ * Externals - Out : None.
+ *       Functions : None.
+ *         Remarks : As the output is written, the key of each record is compared with that of the record before it,
+ *                   equal keys being adjacent once sorted, so that no second pass over the output is needed. Only the
+ *                   index-field part of the keys is compared, the record offsets keeping equal index fields in input
+ *                   order being disregarded, so the keys compare as the sort does, e.g. with folding, typing or
+ *                   WithKeyNormalizer. Each record whose key equals that of the record before it is a duplicate, counted by
+ *                   SortStats.DuplicateKeys. With UniqueFail, the default of WithUniqueMode, the sort stops at the
+ *                   _duplicatesQuoted-th duplicate, or at the end of the output if fewer, and fails with an error matching
+ *                   ErrDuplicateKeys that quotes the values of the index fields and the output line of each, along with
+ *                   its input line with WithOriginalLineNumbers; the output is then left incomplete. With UniqueCount, the
+ *                   sort completes, the count being echoed in verbose mode. The records filtered out or rejected are not
+ *                   output, hence not checked.
+ *         History : v1.96.0 - October 15, 2026 - Original release.
+ */
+    return func(s *Sorter) { s.expectUnique = true }
+} //end func WithExpectUnique
+//Sets whether the duplicates found by WithExpectUnique fail the sort, UniqueFail, or are only counted, UniqueCount
+func WithUniqueMode(mode UniqueMode) Option { return func(s *Sorter) { s.uniqueMode = mode } }
+//Private ----------------------------------------------------------------------------------------------------------------------
+const _duplicatesQuoted = 10 //number of duplicates quoted by the error of UniqueFail
+type uniqueCheck struct {
+    line    int                      //output line of the last record checked
+    prevIdx string                   //index part of the key of the last record checked
+    quoted  []string                 //first duplicates, located
+}
+func (r *sortRun) checkUnique(key, record string) bool {
+    //Counts a record as a duplicate if its key equals that of the record before it, quoting it if among the first ones, and
+    //returns whether the output is to go on
+    if !r.expectUnique { return true }
+    u   := &r.unique
+    idx := keyIndexPart(key)
+    u.line++
+    duplicate := u.line > 1 && idx == u.prevIdx
+    u.prevIdx  = idx
+    if !duplicate { return true }
+    r.stats.DuplicateKeys++
+    if len(u.quoted) < _duplicatesQuoted {
+        trimmed := r.trimRecord(strings.TrimRight(record, "\r\n"))
+        value   := strings.Join(fieldValues(r.splitFields(trimmed, -1), r.colIdxs), r.sep)
+        if len(r.colIdxs) == 0 { value = trimmed }                //key derived by WithDerivedKey alone
+        if len(value) > 80 { value = value[:80] + "..." }
+        where   := fmt.Sprintf("output line %d", u.line)
+        if parts := strings.Split(key, _asciiGS); r.lineNumbers && len(parts) > 3 { where += ", input line " + parts[3] }
+        u.quoted = append(u.quoted, fmt.Sprintf("%s %q", where, value))
+    }
+    return r.uniqueMode != UniqueFail || len(u.quoted) < _duplicatesQuoted
+} //end func checkUnique
+func (r *sortRun) settleUnique() {
+    //Halts on the duplicates quoted if they fail the sort, or else echoes their count
+    if !r.expectUnique { return }
+    if u := &r.unique; r.uniqueMode == UniqueFail && len(u.quoted) > 0 {
+        count := fmt.Sprint(r.stats.DuplicateKeys)
+        if len(u.quoted) == _duplicatesQuoted { count = "at least " + count } //the output stopped at the last one quoted
+        haltKind(ErrDuplicateKeys, fmt.Sprintf("%s duplicate keys in the output: %s", count, strings.Join(u.quoted, "; ")),
+                 nil)
+    }
+    if r.verbose { fmt.Println("func Sort - duplicate keys:", r.stats.DuplicateKeys) }
+} //end func settleUnique
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file unique.go
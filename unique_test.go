@@ -0,0 +1,58 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     unique_test.go
+ * Overview:
+ *     tests of the check that the index fields of the output records are unique.
+ * Functions:
+ *     TestExpectUnique(t *testing.T)
+ *         Checks the duplicates failing the sort per UniqueFail, quoted, or counted per UniqueCount.
+ * History:
+ *     v1.96.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "errors"
+    "fmt"
+    "path/filepath"
+    "strings"
+    "testing"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func TestExpectUnique(t *testing.T) {
+    run := func(input string, opts ...Option) (SortStats, error) {
+        var stats SortStats
+        sorter, err := NewSorter(append([]Option{WithFields("1"), WithSeparator("\t"), WithKeysPerSort(2),
+                                                 WithTempDir(t.TempDir()), WithExpectUnique(), WithStats(&stats)},
+                                        opts...)...)
+        if err != nil { t.Fatal(err) }
+        err = sorter.Run(writeInput(t, input), filepath.Join(t.TempDir(), "out.txt"))
+        return stats, err
+    }
+    //Unique keys passing the check
+    stats, err := run("c\t1\na\t1\nb\t1\n")
+    if err != nil || stats.DuplicateKeys != 0 { t.Errorf("unique keys: error %v, %d duplicates", err, stats.DuplicateKeys) }
+    //3 duplicates, on output lines 2, 4 and 5, failing the sort or counted
+    const input = "b\t1\na\t1\nb\t2\nc\t1\na\t2\nb\t3\n"
+    _, err = run(input)
+    if !errors.Is(err, ErrDuplicateKeys) { t.Fatalf("error %v, expected ErrDuplicateKeys", err) }
+    for _, quoted := range []string{"3 duplicate keys", `output line 2 "a"`, `output line 4 "b"`, `output line 5 "b"`} {
+        if !strings.Contains(err.Error(), quoted) { t.Errorf("error %q, expected to hold %q", err, quoted) }
+    }
+    stats, err = run(input, WithUniqueMode(UniqueCount))
+    if err != nil || stats.DuplicateKeys != 3 || stats.OutputRecords != 6 {
+        t.Errorf("UniqueCount: error %v, %d duplicates in %d records, expected 3 in 6", err, stats.DuplicateKeys,
+                 stats.OutputRecords)
+    }
+    //The sort stopped at the _duplicatesQuoted-th duplicate
+    var many strings.Builder
+    for k := 0; k <= 2 * _duplicatesQuoted; k++ { fmt.Fprintf(&many, "k\t%d\n", k) }
+    _, err = run(many.String())
+    if !errors.Is(err, ErrDuplicateKeys) || !strings.Contains(err.Error(), fmt.Sprintf("at least %d", _duplicatesQuoted)) {
+        t.Errorf("error %v, expected at least %d duplicate keys", err, _duplicatesQuoted)
+    }
+} //end func TestExpectUnique
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file unique_test.go
@@ -0,0 +1,108 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     widthsample.go
+ * Overview:
+ *     estimation of the widths of the index fields from a sample of the input, sparing the exact width scan.
+ * Functions:
+ *     WithSampledWidths(headBytes int64, blocks, margin int, restart bool) Option
+ *         Option estimating the field widths from the head of the input and random blocks of the rest.
+ * History:
+ *     v1.46.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "bufio"
+    "fmt"
+    "io"
+    "math"
+    "math/rand"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func WithSampledWidths(headBytes int64, blocks, margin int, restart bool) Option {
+/*         Purpose : Estimates the widths of the index fields from the head of the input and random blocks of the rest,
+ *                   instead of measuring them over the whole input.
+ *       Arguments : headBytes = number of bytes at the start of the input whose records are all measured.
+ *                   blocks    = number of blocks of _sampleBlockLen bytes measured past the head, at random offsets.
+ *                   margin    = number of bytes added to each measured width.
+ *                   restart   = boolean flag for restarting the key generation with exact widths when a value exceeds
+ *                               its estimated width. If false, the sort fails.
+ * Externals -  In : _sampleBlockLen
+ * Externals - Out : None.
+ *       Functions : None.
+ *         Remarks : The exact scan, the default, reads the whole input once more before the keys are generated. The
+ *                   sample reads at most headBytes plus blocks * _sampleBlockLen bytes, the offsets of the blocks
+ *                   depending only on the input's size so that a sort is reproducible. Every text value is checked
+ *                   against its estimated width as its key is generated. A longer one either fails the sort with an
+ *                   error locating its record or, if restart is set, discards the runs produced so far, measures the
+ *                   widths exactly and generates the keys again. The input's checksum and the count of the invalid
+ *                   values are then taken during the key generation. The line range does not restrict the sample.
+ *                   Inputs sorted in memory are always measured exactly.
+ *         History : v1.46.0 - October 15, 2026 - Original release.
+ */
+    return func(s *Sorter) {
+        s.sampled, s.sampleHead, s.sampleBlocks, s.sampleMargin, s.sampleRestart = true, headBytes, blocks, margin, restart
+    }
+} //end func WithSampledWidths
+//Private ----------------------------------------------------------------------------------------------------------------------
+const _sampleBlockLen = 1 << 16 //number of bytes of each block sampled past the head of the input
+type widthError struct {
+    column int                   //1-based number of the field
+    length int                   //length of the value
+    width  int                   //sampled width of the field
+}
+func (e *widthError) Error() string {
+    return fmt.Sprintf("a value of field %d is %d bytes long, beyond its sampled width of %d", e.column, e.length, e.width)
+} //end func Error
+func (r *sortRun) sampleWidths(fhIn io.ReadSeeker, numFields int) []float64 {
+    //Returns the widths of the index fields measured over the sample of the input, each increased by the margin
+    widths  := make([]float64, numFields)
+    size, err := fhIn.Seek(0, io.SeekEnd)
+    if err != nil { halt("fhIn.Seek - " + err.Error()) }
+    measure := func(start, length int64, partial bool) {
+        //Measures the records from start until length bytes are read, skipping the one cut by start if partial
+        if _, err := fhIn.Seek(start, io.SeekStart); err != nil { halt("fhIn.Seek - " + err.Error()) }
+        reader := bufio.NewReader(fhIn)
+        if partial {
+            readString(reader)
+            r.skipContinuations(reader)                           //lines of the record cut by start
+        }
+        for read := int64(0); read < length; {
+            record, _ := r.readRecord(reader)
+            if len(record) == 0 { break }
+            read += int64(len(record))
+            r.measureFields(widths, r.trimRecord(record))
+        }
+    }
+    head    := r.sampleHead
+    if head > size { head = size }
+    measure(0, head, false)
+    random  := rand.New(rand.NewSource(size))                    //offsets depending on the input only
+    for k := 0; k < r.sampleBlocks && head < size; k++ {
+        measure(head + random.Int63n(size - head), _sampleBlockLen, true)
+    }
+    for k := range widths { widths[k] += float64(r.sampleMargin) }
+    if r.verbose { fmt.Println("func Sort - field widths sampled from", head, "bytes and", r.sampleBlocks, "blocks") }
+    return widths
+} //end func sampleWidths
+func (r *sortRun) measureFields(widths []float64, record string) []string {
+    //Widens the widths of the index fields to their values in a trimmed record, and returns its fields up to the last index
+    //field, the rest being left unsplit
+    fields := r.splitFields(record, r.lastIndexField() + 2)
+    for _, k := range r.colIdxs {
+        if k < len(fields) && k < len(widths) { widths[k] = math.Max(widths[k], float64(len(r.prepare(k, fields[k])))) }
+    }
+    return fields
+} //end func measureFields
+func (s *Sorter) lastIndexField() int {
+    //Returns the 0-based column of the rightmost index field
+    maxCol := 0
+    for _, colIdx := range s.colIdxs {
+        if colIdx > maxCol { maxCol = colIdx }
+    }
+    return maxCol
+} //end func lastIndexField
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file widthsample.go
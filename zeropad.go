@@ -0,0 +1,67 @@
+/*===== Copyright 2026, Webpraxis Consulting Ltd. - ALL RIGHTS RESERVED - Email: webpraxis@gmail.com ===========================
+ * Package:
+ *     mergesort
+ * File:
+ *     zeropad.go
+ * Overview:
+ *     index fields of non-negative integers padded with zeros in the composite keys, so that their string order is their
+ *     numeric order.
+ * Functions:
+ *     WithZeroPadding(column int, strict bool) Option
+ *         Option padding the digits of an index field with zeros rather than spaces.
+ * History:
+ *     v1.55.0 - October 15, 2026 - Original release.
+ *============================================================================================================================*/
+package mergesort
+
+import(
+    "fmt"
+    "strings"
+)
+//Exported ---------------------------------------------------------------------------------------------------------------------
+func WithZeroPadding(column int, strict bool) Option {
+/*         Purpose : Pads the digits of an index field with zeros rather than spaces, e.g. "00042" rather than "   42".
+ *       Arguments : column = number of the field, the first being 1. Must be an index field of type FieldText.
+ *                   strict = boolean flag for failing the sort on a value that is not all digits. If false, such values
+ *                            are keyed as text, ahead of the numbers.
+ *         Returns : The option.
+ * Externals -  In : None.
+ * Externals - Out : None.
+ *       Functions : None.
+ *         Remarks : A lighter alternative to FieldNumeric for columns of non-negative integers: the key keeps the width
+ *                   of the field, plus one byte telling numbers from other values, and needs no decoding. Values equal
+ *                   but for their leading zeros, e.g. "042" and "42", compare equal and keep their input order. Empty
+ *                   values are accepted in both modes and precede all others, unless placed by WithNullsFirst or
+ *                   WithNullsLast. A value failing a strict field is reported as a RecordError. Signs, fractions and
+ *                   spaces are not digits. A later option for the same field replaces an earlier one.
+ *         History : v1.55.0 - October 15, 2026 - Original release.
+ */
+    return func(s *Sorter) {
+        if s.zeroPads == nil { s.zeroPads = map[int]bool{} }
+        s.zeroPads[column - 1] = strict
+    }
+} //end func WithZeroPadding
+//Private ----------------------------------------------------------------------------------------------------------------------
+func (s *Sorter) checkZeroPads() {
+    //Halts on a zero-padded field that is not an index field or is typed
+    for colIdx := range s.zeroPads {
+        if !s.isIndexField(colIdx) { haltKind(ErrBadFieldSpec, fmt.Sprintf("the zero-padded column %d is not an index field",
+                                                                          colIdx + 1), nil) }
+        if s.fieldTypes[colIdx] != FieldText {
+            halt(fmt.Sprintf("the zero-padded column %d is not of type FieldText", colIdx + 1))
+        }
+    }
+} //end func checkZeroPads
+func zeroPadded(column int, strict bool, value string) (class, canonical string) {
+    //Returns the class of a value of a zero-padded field, "1" if all digits and "0" otherwise, and the value stripped of its
+    //leading zeros if all digits or unchanged otherwise; halts on a non-empty value that is not all digits if strict
+    if value == "" || strings.Trim(value, "0123456789") != "" {
+        if strict && value != "" { halt(fmt.Sprintf("the value %q of the zero-padded column %d is not all digits", value,
+                                                    column)) }
+        return "0", value
+    }
+    if canonical = strings.TrimLeft(value, "0"); canonical == "" { canonical = "0" }
+    return "1", canonical
+} //end func zeroPadded
+//===== Copyright (c) 2026 Yves Beaudoin - All rights reserved - MIT LICENSE (MIT) - Email: webpraxis@gmail.com ================
+//end of file zeropad.go